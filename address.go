@@ -1,8 +1,10 @@
 package rtnetlink
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net"
 
 	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
@@ -111,8 +113,13 @@ type AddressService struct {
 
 // New creates a new address using the AddressMessage information.
 func (a *AddressService) New(req *AddressMessage) error {
+	return a.NewContext(context.Background(), req)
+}
+
+// NewContext is like New, but takes a context. See Conn.ExecuteContext.
+func (a *AddressService) NewContext(ctx context.Context, req *AddressMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
-	_, err := a.c.Execute(req, unix.RTM_NEWADDR, flags)
+	_, err := a.c.ExecuteContext(ctx, req, unix.RTM_NEWADDR, flags)
 	if err != nil {
 		return err
 	}
@@ -122,8 +129,13 @@ func (a *AddressService) New(req *AddressMessage) error {
 
 // Delete removes an address using the AddressMessage information.
 func (a *AddressService) Delete(req *AddressMessage) error {
+	return a.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (a *AddressService) DeleteContext(ctx context.Context, req *AddressMessage) error {
 	flags := netlink.Request | netlink.Acknowledge
-	_, err := a.c.Execute(req, unix.RTM_DELADDR, flags)
+	_, err := a.c.ExecuteContext(ctx, req, unix.RTM_DELADDR, flags)
 	if err != nil {
 		return err
 	}
@@ -133,10 +145,15 @@ func (a *AddressService) Delete(req *AddressMessage) error {
 
 // List retrieves all addresses.
 func (a *AddressService) List() ([]AddressMessage, error) {
+	return a.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (a *AddressService) ListContext(ctx context.Context) ([]AddressMessage, error) {
 	req := AddressMessage{}
 
 	flags := netlink.Request | netlink.Dump
-	msgs, err := a.c.Execute(&req, unix.RTM_GETADDR, flags)
+	msgs, err := a.c.ExecuteContext(ctx, &req, unix.RTM_GETADDR, flags)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +165,87 @@ func (a *AddressService) List() ([]AddressMessage, error) {
 	return addresses, nil
 }
 
+// ListMatch retrieves the addresses matching req. The Family and Index of req
+// are used as filter when non-zero, all other fields are ignored.
+//
+// The kernel filters the dump if the Conn has strict checking enabled, see
+// netlink.Config.Strict, which requires Linux 4.20 or later. Otherwise the
+// addresses are filtered after they have been received.
+func (a *AddressService) ListMatch(req *AddressMessage) ([]AddressMessage, error) {
+	return a.ListMatchContext(context.Background(), req)
+}
+
+// ListMatchContext is like ListMatch, but takes a context. See Conn.ExecuteContext.
+func (a *AddressService) ListMatchContext(ctx context.Context, req *AddressMessage) ([]AddressMessage, error) {
+	// A strict dump request carries no attributes, and only the family and
+	// index in its header.
+	filter := &AddressMessage{
+		Family: req.Family,
+		Index:  req.Index,
+	}
+
+	flags := netlink.Request | netlink.Dump
+	msgs, err := a.c.ExecuteContext(ctx, filter, unix.RTM_GETADDR, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]AddressMessage, 0, len(msgs))
+	for _, m := range msgs {
+		addr := m.(*AddressMessage)
+		if addr.matches(filter) {
+			addresses = append(addresses, *addr)
+		}
+	}
+	return addresses, nil
+}
+
+// ListSeq is like List, but returns an iterator which yields the addresses
+// one at a time. See Conn.ExecuteSeq.
+func (a *AddressService) ListSeq() iter.Seq2[AddressMessage, error] {
+	return a.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (a *AddressService) ListSeqContext(ctx context.Context) iter.Seq2[AddressMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[AddressMessage](ctx, a.c, &AddressMessage{}, unix.RTM_GETADDR, flags)
+}
+
+// ListMatchSeq is like ListMatch, but returns an iterator which yields the
+// addresses one at a time. See Conn.ExecuteSeq.
+func (a *AddressService) ListMatchSeq(req *AddressMessage) iter.Seq2[AddressMessage, error] {
+	return a.ListMatchSeqContext(context.Background(), req)
+}
+
+// ListMatchSeqContext is like ListMatchSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (a *AddressService) ListMatchSeqContext(ctx context.Context, req *AddressMessage) iter.Seq2[AddressMessage, error] {
+	filter := &AddressMessage{
+		Family: req.Family,
+		Index:  req.Index,
+	}
+
+	flags := netlink.Request | netlink.Dump
+	addresses := executeSeq[AddressMessage](ctx, a.c, filter, unix.RTM_GETADDR, flags)
+
+	return filterSeq(addresses, func(addr *AddressMessage) bool {
+		return addr.matches(filter)
+	})
+}
+
+// matches reports whether m matches the non-zero Family and Index of the
+// dump filter f.
+func (m *AddressMessage) matches(f *AddressMessage) bool {
+	if f.Family != 0 && m.Family != f.Family {
+		return false
+	}
+	if f.Index != 0 && m.Index != f.Index {
+		return false
+	}
+
+	return true
+}
+
 // AddressAttributes contains all attributes for an interface.
 type AddressAttributes struct {
 	Address   net.IP // Interface Ip address
@@ -0,0 +1,360 @@
+package rtnetlink
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// LinkAFSpec contains the address family specific attributes of an interface
+// (IFLA_AF_SPEC). The IPv4 and IPv6 attributes are reported by the kernel and
+// not encoded when setting a link with LinkService.Set, so that a link which
+// was retrieved can be set again; use LinkService.SetAFSpec to change them.
+// The bridge attributes are only encoded in messages of family AF_BRIDGE.
+type LinkAFSpec struct {
+	Inet  *LinkAFSpecInet
+	Inet6 *LinkAFSpecInet6
+
+	// Bridge attributes, only present in messages of family AF_BRIDGE, which
+	// carry no other address family specific attributes.
+	Bridge *LinkAFSpecBridge
+}
+
+func (s *LinkAFSpec) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.AF_INET:
+			s.Inet = &LinkAFSpecInet{}
+			ad.Nested(s.Inet.decode)
+		case unix.AF_INET6:
+			s.Inet6 = &LinkAFSpecInet6{}
+			ad.Nested(s.Inet6.decode)
+		}
+	}
+
+	return nil
+}
+
+// LinkAFSpecChange contains the writable IPv4 and IPv6 specific attributes of
+// an interface, written with LinkService.SetAFSpec. Only the values present
+// are changed.
+type LinkAFSpecChange struct {
+	// IPv4 configuration values (net.ipv4.conf.<interface>.*) to write,
+	// keyed by unix.IPV4_DEVCONF_*. Values written are no longer changed
+	// along with net.ipv4.conf.default.
+	InetConf map[uint16]uint32
+
+	// Interface identifier used to generate IPv6 addresses from router
+	// advertisements. The kernel rejects it for loopback and NOARP
+	// interfaces and for interfaces which don't accept router
+	// advertisements.
+	Inet6Token net.IP
+
+	// IPv6 address generation mode (unix.IN6_ADDR_GEN_MODE_*)
+	Inet6AddrGenMode *uint8
+}
+
+func (c *LinkAFSpecChange) encode(ae *netlink.AttributeEncoder) error {
+	if len(c.InetConf) > 0 {
+		ae.Nested(unix.AF_INET, c.encodeInet)
+	}
+
+	// The kernel rejects an IPv6 specification without writable attributes.
+	if c.Inet6Token != nil || c.Inet6AddrGenMode != nil {
+		ae.Nested(unix.AF_INET6, c.encodeInet6)
+	}
+
+	return nil
+}
+
+func (c *LinkAFSpecChange) encodeInet(ae *netlink.AttributeEncoder) error {
+	ae.Nested(unix.IFLA_INET_CONF, func(nae *netlink.AttributeEncoder) error {
+		keys := make([]uint16, 0, len(c.InetConf))
+		for k := range c.InetConf {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			nae.Uint32(k, c.InetConf[k])
+		}
+		return nil
+	})
+
+	return nil
+}
+
+func (c *LinkAFSpecChange) encodeInet6(ae *netlink.AttributeEncoder) error {
+	if c.Inet6Token != nil {
+		ae.Do(unix.IFLA_INET6_TOKEN, encodeIP(c.Inet6Token.To16()))
+	}
+
+	if c.Inet6AddrGenMode != nil {
+		ae.Uint8(unix.IFLA_INET6_ADDR_GEN_MODE, *c.Inet6AddrGenMode)
+	}
+
+	return nil
+}
+
+// LinkAFSpecInet contains the IPv4 specific attributes of an interface.
+type LinkAFSpecInet struct {
+	// IPv4 configuration of the interface (net.ipv4.conf.<interface>.*),
+	// keyed by unix.IPV4_DEVCONF_*
+	Conf map[uint16]uint32
+}
+
+func (i *LinkAFSpecInet) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.IFLA_INET_CONF {
+			ad.Do(func(b []byte) error {
+				// An array of the values, starting with IPV4_DEVCONF_FORWARDING.
+				if len(b)%4 != 0 {
+					return fmt.Errorf("incorrect IFLA_INET_CONF size, got: %d", len(b))
+				}
+
+				i.Conf = make(map[uint16]uint32, len(b)/4)
+				for off := 0; off < len(b); off += 4 {
+					i.Conf[uint16(off/4+1)] = nativeEndian.Uint32(b[off : off+4])
+				}
+				return nil
+			})
+		}
+	}
+
+	return nil
+}
+
+// LinkAFSpecInet6 contains the IPv6 specific attributes of an interface.
+type LinkAFSpecInet6 struct {
+	// Interface flags (IF_RA_*, IF_READY)
+	Flags *uint32
+
+	// IPv6 configuration of the interface (net.ipv6.conf.<interface>.*),
+	// keyed by unix.DEVCONF_*. It is only reported by the kernel.
+	Conf map[uint16]int32
+
+	// Neighbor discovery timers of the interface
+	CacheInfo *LinkInet6CacheInfo
+
+	// IP statistics of the interface, indexed by IPSTATS_MIB_*. The first
+	// element holds the number of counters.
+	Stats []uint64
+
+	// ICMPv6 statistics of the interface, indexed by ICMP6_MIB_*. The first
+	// element holds the number of counters.
+	ICMP6Stats []uint64
+
+	// Interface identifier used to generate addresses from router
+	// advertisements
+	Token net.IP
+
+	// Address generation mode (unix.IN6_ADDR_GEN_MODE_*)
+	AddrGenMode *uint8
+
+	// MTU advertised by routers
+	RAMTU *uint32
+}
+
+func (i *LinkAFSpecInet6) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_INET6_FLAGS:
+			v := ad.Uint32()
+			i.Flags = &v
+		case unix.IFLA_INET6_CONF:
+			ad.Do(func(b []byte) error {
+				if len(b)%4 != 0 {
+					return fmt.Errorf("incorrect IFLA_INET6_CONF size, got: %d", len(b))
+				}
+
+				i.Conf = make(map[uint16]int32, len(b)/4)
+				for off := 0; off < len(b); off += 4 {
+					i.Conf[uint16(off/4)] = int32(nativeEndian.Uint32(b[off : off+4]))
+				}
+				return nil
+			})
+		case unix.IFLA_INET6_CACHEINFO:
+			i.CacheInfo = &LinkInet6CacheInfo{}
+			ad.Do(i.CacheInfo.unmarshalBinary)
+		case unix.IFLA_INET6_STATS:
+			ad.Do(decodeUint64s(&i.Stats))
+		case unix.IFLA_INET6_ICMP6STATS:
+			ad.Do(decodeUint64s(&i.ICMP6Stats))
+		case unix.IFLA_INET6_TOKEN:
+			ad.Do(decodeIP(&i.Token))
+		case unix.IFLA_INET6_ADDR_GEN_MODE:
+			v := ad.Uint8()
+			i.AddrGenMode = &v
+		case unix.IFLA_INET6_RA_MTU:
+			v := ad.Uint32()
+			i.RAMTU = &v
+		}
+	}
+
+	return nil
+}
+
+// LinkInet6CacheInfo contains the neighbor discovery timers of an interface
+// (struct ifla_cacheinfo). Times are in milliseconds.
+type LinkInet6CacheInfo struct {
+	MaxReasmLen   uint32
+	Tstamp        uint32 // time of the last change, in hundredths of seconds since boot
+	ReachableTime uint32
+	RetransTime   uint32
+}
+
+func (c *LinkInet6CacheInfo) unmarshalBinary(b []byte) error {
+	if len(b) != 16 {
+		return fmt.Errorf("incorrect LinkInet6CacheInfo size, want: 16, got: %d", len(b))
+	}
+
+	c.MaxReasmLen = nativeEndian.Uint32(b[0:4])
+	c.Tstamp = nativeEndian.Uint32(b[4:8])
+	c.ReachableTime = nativeEndian.Uint32(b[8:12])
+	c.RetransTime = nativeEndian.Uint32(b[12:16])
+
+	return nil
+}
+
+// LinkAFSpecBridge contains the bridge specific attributes of a bridge or
+// bridge port.
+type LinkAFSpecBridge struct {
+	// Whether a request is handled by the bridge of a port
+	// (unix.BRIDGE_FLAGS_MASTER, the default) or by the device itself
+	// (unix.BRIDGE_FLAGS_SELF)
+	Flags *uint16
+
+	// Hairpin mode of a device handling requests itself (BRIDGE_MODE_*)
+	Mode *uint16
+
+	// VLANs of the port. Dumps requested with
+	// unix.RTEXT_FILTER_BRVLAN_COMPRESSED report ranges of VLANs as two
+	// entries flagged unix.BRIDGE_VLAN_INFO_RANGE_BEGIN and
+	// unix.BRIDGE_VLAN_INFO_RANGE_END, which can be used in requests as well.
+	VlanInfo []BridgeVlanInfo
+
+	// Mappings of VLANs to tunnel IDs of a port with VLAN tunneling enabled
+	VlanTunnelInfo []BridgeVlanTunnelInfo
+}
+
+func (b *LinkAFSpecBridge) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_BRIDGE_FLAGS:
+			v := ad.Uint16()
+			b.Flags = &v
+		case unix.IFLA_BRIDGE_MODE:
+			v := ad.Uint16()
+			b.Mode = &v
+		case unix.IFLA_BRIDGE_VLAN_INFO:
+			var v BridgeVlanInfo
+			ad.Do(v.unmarshalBinary)
+			b.VlanInfo = append(b.VlanInfo, v)
+		case unix.IFLA_BRIDGE_VLAN_TUNNEL_INFO:
+			var v BridgeVlanTunnelInfo
+			ad.Nested(v.decode)
+			b.VlanTunnelInfo = append(b.VlanTunnelInfo, v)
+		}
+	}
+
+	return nil
+}
+
+func (b *LinkAFSpecBridge) encode(ae *netlink.AttributeEncoder) error {
+	if b.Flags != nil {
+		ae.Uint16(unix.IFLA_BRIDGE_FLAGS, *b.Flags)
+	}
+
+	if b.Mode != nil {
+		ae.Uint16(unix.IFLA_BRIDGE_MODE, *b.Mode)
+	}
+
+	for _, v := range b.VlanInfo {
+		ae.Bytes(unix.IFLA_BRIDGE_VLAN_INFO, v.marshalBinary())
+	}
+
+	for _, v := range b.VlanTunnelInfo {
+		ae.Nested(unix.IFLA_BRIDGE_VLAN_TUNNEL_INFO, v.encode)
+	}
+
+	return nil
+}
+
+// BridgeVlanInfo describes a VLAN of a bridge or bridge port
+// (struct bridge_vlan_info).
+type BridgeVlanInfo struct {
+	Flags uint16 // unix.BRIDGE_VLAN_INFO_*
+	Vid   uint16
+}
+
+func (v *BridgeVlanInfo) marshalBinary() []byte {
+	b := make([]byte, 4)
+	nativeEndian.PutUint16(b[0:2], v.Flags)
+	nativeEndian.PutUint16(b[2:4], v.Vid)
+
+	return b
+}
+
+func (v *BridgeVlanInfo) unmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return fmt.Errorf("incorrect BridgeVlanInfo size, want: 4, got: %d", len(b))
+	}
+
+	v.Flags = nativeEndian.Uint16(b[0:2])
+	v.Vid = nativeEndian.Uint16(b[2:4])
+
+	return nil
+}
+
+// BridgeVlanTunnelInfo maps a VLAN of a bridge port to a tunnel ID, e.g. the
+// VNI of a VXLAN device in collect metadata mode.
+type BridgeVlanTunnelInfo struct {
+	TunnelID uint32
+	Vid      uint16
+	Flags    uint16 // unix.BRIDGE_VLAN_INFO_RANGE_BEGIN or unix.BRIDGE_VLAN_INFO_RANGE_END
+}
+
+func (t *BridgeVlanTunnelInfo) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_BRIDGE_VLAN_TUNNEL_ID:
+			t.TunnelID = ad.Uint32()
+		case unix.IFLA_BRIDGE_VLAN_TUNNEL_VID:
+			t.Vid = ad.Uint16()
+		case unix.IFLA_BRIDGE_VLAN_TUNNEL_FLAGS:
+			t.Flags = ad.Uint16()
+		}
+	}
+
+	return nil
+}
+
+func (t *BridgeVlanTunnelInfo) encode(ae *netlink.AttributeEncoder) error {
+	ae.Uint32(unix.IFLA_BRIDGE_VLAN_TUNNEL_ID, t.TunnelID)
+	ae.Uint16(unix.IFLA_BRIDGE_VLAN_TUNNEL_VID, t.Vid)
+	if t.Flags != 0 {
+		ae.Uint16(unix.IFLA_BRIDGE_VLAN_TUNNEL_FLAGS, t.Flags)
+	}
+
+	return nil
+}
+
+// decodeUint64s returns a function which decodes an array of native endian
+// 64 bit values into v.
+func decodeUint64s(v *[]uint64) func(b []byte) error {
+	return func(b []byte) error {
+		if len(b)%8 != 0 {
+			return fmt.Errorf("incorrect size of 64 bit value array, got: %d", len(b))
+		}
+
+		*v = make([]uint64, 0, len(b)/8)
+		for off := 0; off < len(b); off += 8 {
+			*v = append(*v, nativeEndian.Uint64(b[off:off+8]))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,194 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestLinkAFSpecMarshalBinary(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m *LinkMessage
+		b []byte
+	}{
+		"change inet and inet6": {
+			m: afSpecRequest(1, &LinkAFSpecChange{
+				InetConf: map[uint16]uint32{
+					unix.IPV4_DEVCONF_RP_FILTER:  2,
+					unix.IPV4_DEVCONF_FORWARDING: 1,
+				},
+				Inet6Token:       net.ParseIP("::1:2:3:4"),
+				Inet6AddrGenMode: uint8Ptr(unix.IN6_ADDR_GEN_MODE_NONE),
+			}),
+			b: []byte{
+				0x3c, 0x00, 0x1a, 0x80, 0x18, 0x00, 0x02, 0x80, 0x14, 0x00, 0x01, 0x80,
+				0x08, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x08, 0x00, 0x08, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x20, 0x00, 0x0a, 0x80, 0x14, 0x00, 0x07, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x02,
+				0x00, 0x03, 0x00, 0x04, 0x05, 0x00, 0x08, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+		},
+		"change inet6 only": {
+			m: afSpecRequest(1, &LinkAFSpecChange{
+				Inet6AddrGenMode: uint8Ptr(unix.IN6_ADDR_GEN_MODE_NONE),
+			}),
+			b: []byte{
+				0x10, 0x00, 0x1a, 0x80, 0x0c, 0x00, 0x0a, 0x80, 0x05, 0x00, 0x08, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+			},
+		},
+		"reported attributes": {
+			// A retrieved link can be set again without writing its
+			// reported configuration, not even as an empty IFLA_AF_SPEC.
+			m: &LinkMessage{
+				Attributes: &LinkAttributes{
+					AFSpec: &LinkAFSpec{
+						Inet: &LinkAFSpecInet{
+							Conf: map[uint16]uint32{unix.IPV4_DEVCONF_FORWARDING: 1},
+						},
+						Inet6: &LinkAFSpecInet6{
+							Flags:       uint32Ptr(0x80000000),
+							Conf:        map[uint16]int32{unix.DEVCONF_FORWARDING: 1},
+							Token:       net.ParseIP("::1:2:3:4"),
+							AddrGenMode: uint8Ptr(unix.IN6_ADDR_GEN_MODE_NONE),
+							RAMTU:       uint32Ptr(1500),
+						},
+					},
+				},
+			},
+			b: []byte{},
+		},
+		"bridge attributes of another family": {
+			m: &LinkMessage{
+				Family: unix.AF_UNSPEC,
+				Attributes: &LinkAttributes{
+					AFSpec: &LinkAFSpec{
+						Bridge: &LinkAFSpecBridge{Flags: uint16Ptr(unix.BRIDGE_FLAGS_MASTER)},
+					},
+				},
+			},
+			b: []byte{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b[unix.SizeofIfInfomsg:]; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected attribute bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+			}
+		})
+	}
+}
+
+func TestLinkAFSpecUnmarshalBinary(t *testing.T) {
+	skipBigEndian(t)
+
+	b := append(make([]byte, unix.SizeofIfInfomsg),
+		0x78, 0x00, 0x1a, 0x80, 0x10, 0x00, 0x02, 0x80, 0x0c, 0x00, 0x01, 0x00,
+		0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x64, 0x00, 0x0a, 0x80,
+		0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x80, 0x14, 0x00, 0x02, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0xdc, 0x05, 0x00, 0x00,
+		0xff, 0xff, 0xff, 0xff, 0x14, 0x00, 0x05, 0x00, 0xff, 0xff, 0x00, 0x00,
+		0x10, 0x00, 0x00, 0x00, 0x30, 0x75, 0x00, 0x00, 0xe8, 0x03, 0x00, 0x00,
+		0x14, 0x00, 0x03, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x14, 0x00, 0x07, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x05, 0x00, 0x08, 0x00, 0x01, 0x00, 0x00, 0x00,
+	)
+
+	want := &LinkAFSpec{
+		Inet: &LinkAFSpecInet{
+			Conf: map[uint16]uint32{
+				unix.IPV4_DEVCONF_FORWARDING:    1,
+				unix.IPV4_DEVCONF_MC_FORWARDING: 2,
+			},
+		},
+		Inet6: &LinkAFSpecInet6{
+			Flags: uint32Ptr(0x80000000),
+			Conf: map[uint16]int32{
+				unix.DEVCONF_FORWARDING: 0,
+				unix.DEVCONF_HOPLIMIT:   64,
+				unix.DEVCONF_MTU6:       1500,
+				unix.DEVCONF_ACCEPT_RA:  -1,
+			},
+			CacheInfo: &LinkInet6CacheInfo{
+				MaxReasmLen:   0xffff,
+				Tstamp:        16,
+				ReachableTime: 30000,
+				RetransTime:   1000,
+			},
+			Stats:       []uint64{2, 7},
+			Token:       net.ParseIP("::1"),
+			AddrGenMode: uint8Ptr(unix.IN6_ADDR_GEN_MODE_NONE),
+		},
+	}
+
+	var m LinkMessage
+	if err := m.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if got := m.Attributes.AFSpec; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected LinkAFSpec:\n- want: %#v\n-  got: %#v", want, got)
+	}
+}
+
+func TestLinkAFSpecBridge(t *testing.T) {
+	skipBigEndian(t)
+
+	m := &LinkMessage{
+		Family: unix.AF_BRIDGE,
+		Index:  3,
+		Attributes: &LinkAttributes{
+			AFSpec: &LinkAFSpec{
+				Bridge: &LinkAFSpecBridge{
+					Flags: uint16Ptr(unix.BRIDGE_FLAGS_MASTER),
+					VlanInfo: []BridgeVlanInfo{
+						{Flags: unix.BRIDGE_VLAN_INFO_RANGE_BEGIN, Vid: 10},
+						{Flags: unix.BRIDGE_VLAN_INFO_RANGE_END, Vid: 20},
+					},
+					VlanTunnelInfo: []BridgeVlanTunnelInfo{
+						{TunnelID: 3000, Vid: 30},
+					},
+				},
+			},
+		},
+	}
+
+	b := []byte{
+		0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x30, 0x00, 0x1a, 0x80, 0x06, 0x00, 0x00, 0x00,
+		0x01, 0x00, 0x00, 0x00, 0x08, 0x00, 0x02, 0x00, 0x08, 0x00, 0x0a, 0x00,
+		0x08, 0x00, 0x02, 0x00, 0x10, 0x00, 0x14, 0x00, 0x14, 0x00, 0x03, 0x80,
+		0x08, 0x00, 0x01, 0x00, 0xb8, 0x0b, 0x00, 0x00, 0x06, 0x00, 0x02, 0x00,
+		0x1e, 0x00, 0x00, 0x00,
+	}
+
+	got, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if !bytes.Equal(b, got) {
+		t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", b, got)
+	}
+
+	var um LinkMessage
+	if err := um.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(m, &um) {
+		t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", m, &um)
+	}
+}
@@ -0,0 +1,449 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// errInvalidBridgeVlanMessage is returned when a BridgeVlanMessage is malformed.
+var errInvalidBridgeVlanMessage = errors.New("rtnetlink BridgeVlanMessage is invalid or too short")
+
+// sizeofBrVlanMsg is the size of a struct br_vlan_msg.
+const sizeofBrVlanMsg = 8
+
+var _ Message = &BridgeVlanMessage{}
+
+// A BridgeVlanMessage is a route netlink bridge VLAN message, describing the
+// VLANs of a bridge or bridge port.
+type BridgeVlanMessage struct {
+	// Always set to AF_BRIDGE
+	Family uint8
+
+	// Interface index of the bridge or bridge port
+	Index uint32
+
+	// Optional attributes which are appended when not nil.
+	Attributes *BridgeVlanAttributes
+}
+
+// MarshalBinary marshals a BridgeVlanMessage into a byte slice.
+func (m *BridgeVlanMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofBrVlanMsg)
+
+	b[0] = m.Family
+	// 3 bytes of padding
+	nativeEndian.PutUint32(b[4:8], m.Index)
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a BridgeVlanMessage.
+func (m *BridgeVlanMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < sizeofBrVlanMsg {
+		return errInvalidBridgeVlanMessage
+	}
+
+	m.Family = b[0]
+	m.Index = nativeEndian.Uint32(b[4:8])
+
+	if l > sizeofBrVlanMsg {
+		m.Attributes = &BridgeVlanAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[sizeofBrVlanMsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*BridgeVlanMessage) rtMessage() {}
+
+// BridgeVlanAttributes contains all attributes for the VLANs of a bridge or
+// bridge port.
+type BridgeVlanAttributes struct {
+	// Flags of a dump request (unix.BRIDGE_VLANDB_DUMPF_*), e.g. to include
+	// the statistics of the VLANs. Only used in dump requests.
+	DumpFlags *uint32
+
+	// VLANs or ranges of VLANs, with their options
+	Entries []BridgeVlanEntry
+}
+
+func (a *BridgeVlanAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.BRIDGE_VLANDB_ENTRY {
+			var e BridgeVlanEntry
+			ad.Nested(e.decode)
+			a.Entries = append(a.Entries, e)
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *BridgeVlanAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.DumpFlags != nil {
+		ae.Uint32(unix.BRIDGE_VLANDB_DUMP_FLAGS, *a.DumpFlags)
+	}
+
+	for _, e := range a.Entries {
+		ae.Nested(unix.BRIDGE_VLANDB_ENTRY, e.encode)
+	}
+
+	return nil
+}
+
+// A BridgeVlanEntry describes a VLAN, or a range of VLANs sharing the same
+// options, of a bridge or bridge port. Options which are nil are left
+// unchanged when adding existing VLANs.
+type BridgeVlanEntry struct {
+	// The VLAN, or the first VLAN of a range, and its flags
+	// (unix.BRIDGE_VLAN_INFO_PVID, unix.BRIDGE_VLAN_INFO_UNTAGGED, ...)
+	Info BridgeVlanInfo
+
+	// Last VLAN of a range
+	RangeEnd *uint16
+
+	// Spanning tree state of the VLAN on a port (BR_STATE_*, see
+	// driver.BridgePortState)
+	State *uint8
+
+	// Tunnel ID mapping of the VLAN on a port with VLAN tunneling enabled
+	Tunnel *BridgeVlanTunnel
+
+	// Statistics of the VLAN, reported when requested with
+	// unix.BRIDGE_VLANDB_DUMPF_STATS
+	Stats *BridgeVlanStats
+
+	// Multicast router mode of the VLAN (unix.MDB_RTR_TYPE_*)
+	McastRouter *uint8
+
+	// Number of multicast groups joined on the VLAN of a port
+	McastNGroups *uint32
+
+	// Maximum number of multicast groups joined on the VLAN of a port, 0 for
+	// no limit
+	McastMaxGroups *uint32
+
+	// Neighbor suppression on the VLAN of a port
+	NeighSuppress *uint8
+}
+
+func (e *BridgeVlanEntry) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.BRIDGE_VLANDB_ENTRY_INFO:
+			ad.Do(e.Info.unmarshalBinary)
+		case unix.BRIDGE_VLANDB_ENTRY_RANGE:
+			v := ad.Uint16()
+			e.RangeEnd = &v
+		case unix.BRIDGE_VLANDB_ENTRY_STATE:
+			v := ad.Uint8()
+			e.State = &v
+		case unix.BRIDGE_VLANDB_ENTRY_TUNNEL_INFO:
+			e.Tunnel = &BridgeVlanTunnel{}
+			ad.Nested(e.Tunnel.decode)
+		case unix.BRIDGE_VLANDB_ENTRY_STATS:
+			e.Stats = &BridgeVlanStats{}
+			ad.Nested(e.Stats.decode)
+		case unix.BRIDGE_VLANDB_ENTRY_MCAST_ROUTER:
+			v := ad.Uint8()
+			e.McastRouter = &v
+		case unix.BRIDGE_VLANDB_ENTRY_MCAST_N_GROUPS:
+			v := ad.Uint32()
+			e.McastNGroups = &v
+		case unix.BRIDGE_VLANDB_ENTRY_MCAST_MAX_GROUPS:
+			v := ad.Uint32()
+			e.McastMaxGroups = &v
+		case unix.BRIDGE_VLANDB_ENTRY_NEIGH_SUPPRESS:
+			v := ad.Uint8()
+			e.NeighSuppress = &v
+		}
+	}
+
+	return nil
+}
+
+func (e *BridgeVlanEntry) encode(ae *netlink.AttributeEncoder) error {
+	ae.Bytes(unix.BRIDGE_VLANDB_ENTRY_INFO, e.Info.marshalBinary())
+
+	if e.RangeEnd != nil {
+		ae.Uint16(unix.BRIDGE_VLANDB_ENTRY_RANGE, *e.RangeEnd)
+	}
+
+	if e.State != nil {
+		ae.Uint8(unix.BRIDGE_VLANDB_ENTRY_STATE, *e.State)
+	}
+
+	if e.Tunnel != nil {
+		ae.Nested(unix.BRIDGE_VLANDB_ENTRY_TUNNEL_INFO, e.Tunnel.encode)
+	}
+
+	if e.McastRouter != nil {
+		ae.Uint8(unix.BRIDGE_VLANDB_ENTRY_MCAST_ROUTER, *e.McastRouter)
+	}
+
+	if e.McastMaxGroups != nil {
+		ae.Uint32(unix.BRIDGE_VLANDB_ENTRY_MCAST_MAX_GROUPS, *e.McastMaxGroups)
+	}
+
+	if e.NeighSuppress != nil {
+		ae.Uint8(unix.BRIDGE_VLANDB_ENTRY_NEIGH_SUPPRESS, *e.NeighSuppress)
+	}
+
+	return nil
+}
+
+// BridgeVlanTunnel maps a VLAN of a bridge port to a tunnel ID, e.g. the VNI
+// of a VXLAN device in collect metadata mode.
+type BridgeVlanTunnel struct {
+	ID uint32
+
+	// Removes the mapping instead of setting it. Only used in requests.
+	Remove bool
+}
+
+func (t *BridgeVlanTunnel) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.BRIDGE_VLANDB_TINFO_ID {
+			t.ID = ad.Uint32()
+		}
+	}
+
+	return nil
+}
+
+func (t *BridgeVlanTunnel) encode(ae *netlink.AttributeEncoder) error {
+	ae.Uint32(unix.BRIDGE_VLANDB_TINFO_ID, t.ID)
+
+	cmd := uint32(unix.RTM_SETLINK)
+	if t.Remove {
+		cmd = unix.RTM_DELLINK
+	}
+	ae.Uint32(unix.BRIDGE_VLANDB_TINFO_CMD, cmd)
+
+	return nil
+}
+
+// BridgeVlanStats contains the statistics of a VLAN, counted if enabled on
+// the bridge.
+type BridgeVlanStats struct {
+	RXBytes   uint64
+	RXPackets uint64
+	TXBytes   uint64
+	TXPackets uint64
+}
+
+func (s *BridgeVlanStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.BRIDGE_VLANDB_STATS_RX_BYTES:
+			s.RXBytes = ad.Uint64()
+		case unix.BRIDGE_VLANDB_STATS_RX_PACKETS:
+			s.RXPackets = ad.Uint64()
+		case unix.BRIDGE_VLANDB_STATS_TX_BYTES:
+			s.TXBytes = ad.Uint64()
+		case unix.BRIDGE_VLANDB_STATS_TX_PACKETS:
+			s.TXPackets = ad.Uint64()
+		}
+	}
+
+	return nil
+}
+
+// BridgeVlanService is used to manage the VLANs of bridges and bridge ports.
+//
+// Add, Delete and List use the VLAN API of kernel 5.9+, which supports
+// ranges and per-VLAN options. AddLink, DeleteLink and ListLink use the
+// IFLA_AF_SPEC attribute of links of family AF_BRIDGE, which is supported by
+// older kernels and by devices handling VLANs themselves.
+type BridgeVlanService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a BridgeVlanMessage slice
+func (b *BridgeVlanService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]BridgeVlanMessage, error) {
+	msgs, err := b.c.ExecuteContext(ctx, m, family, flags)
+
+	vlans := make([]BridgeVlanMessage, len(msgs))
+	for i, msg := range msgs {
+		if vm, ok := msg.(*BridgeVlanMessage); ok {
+			vlans[i] = *vm
+		}
+	}
+
+	return vlans, err
+}
+
+// Add adds the VLANs of req to the bridge or bridge port with the Index of
+// req, or changes the options of existing VLANs.
+func (b *BridgeVlanService) Add(req *BridgeVlanMessage) error {
+	return b.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (b *BridgeVlanService) AddContext(ctx context.Context, req *BridgeVlanMessage) error {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := b.c.ExecuteContext(ctx, &r, unix.RTM_NEWVLAN, flags)
+
+	return err
+}
+
+// Delete deletes the VLANs of req from the bridge or bridge port with the
+// Index of req.
+func (b *BridgeVlanService) Delete(req *BridgeVlanMessage) error {
+	return b.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (b *BridgeVlanService) DeleteContext(ctx context.Context, req *BridgeVlanMessage) error {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := b.c.ExecuteContext(ctx, &r, unix.RTM_DELVLAN, flags)
+
+	return err
+}
+
+// List retrieves the VLANs of the bridge or bridge port with the Index of
+// req, or of all bridges and bridge ports if the Index is zero. Ranges of
+// VLANs with the same options are reported as a single entry.
+func (b *BridgeVlanService) List(req *BridgeVlanMessage) ([]BridgeVlanMessage, error) {
+	return b.ListContext(context.Background(), req)
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (b *BridgeVlanService) ListContext(ctx context.Context, req *BridgeVlanMessage) ([]BridgeVlanMessage, error) {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Dump
+	return b.execute(ctx, &r, unix.RTM_GETVLAN, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the VLANs
+// one at a time. See Conn.ExecuteSeq.
+func (b *BridgeVlanService) ListSeq(req *BridgeVlanMessage) iter.Seq2[BridgeVlanMessage, error] {
+	return b.ListSeqContext(context.Background(), req)
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (b *BridgeVlanService) ListSeqContext(ctx context.Context, req *BridgeVlanMessage) iter.Seq2[BridgeVlanMessage, error] {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[BridgeVlanMessage](ctx, b.c, &r, unix.RTM_GETVLAN, flags)
+}
+
+// linkRequest returns a link request of family AF_BRIDGE with the bridge
+// attributes spec.
+func linkRequest(index uint32, spec *LinkAFSpecBridge) *LinkMessage {
+	return &LinkMessage{
+		Family: unix.AF_BRIDGE,
+		Index:  index,
+		Attributes: &LinkAttributes{
+			AFSpec: &LinkAFSpec{Bridge: spec},
+		},
+	}
+}
+
+// AddLink adds the VLANs and VLAN tunnel mappings of spec to the bridge port
+// with the given index, or to the bridge itself when setting the Flags of
+// spec to unix.BRIDGE_FLAGS_SELF. The flags of existing VLANs are replaced.
+func (b *BridgeVlanService) AddLink(index uint32, spec *LinkAFSpecBridge) error {
+	return b.AddLinkContext(context.Background(), index, spec)
+}
+
+// AddLinkContext is like AddLink, but takes a context. See
+// Conn.ExecuteContext.
+func (b *BridgeVlanService) AddLinkContext(ctx context.Context, index uint32, spec *LinkAFSpecBridge) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := b.c.ExecuteContext(ctx, linkRequest(index, spec), unix.RTM_SETLINK, flags)
+
+	return err
+}
+
+// DeleteLink deletes the VLANs and VLAN tunnel mappings of spec from the
+// bridge port with the given index.
+func (b *BridgeVlanService) DeleteLink(index uint32, spec *LinkAFSpecBridge) error {
+	return b.DeleteLinkContext(context.Background(), index, spec)
+}
+
+// DeleteLinkContext is like DeleteLink, but takes a context. See
+// Conn.ExecuteContext.
+func (b *BridgeVlanService) DeleteLinkContext(ctx context.Context, index uint32, spec *LinkAFSpecBridge) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := b.c.ExecuteContext(ctx, linkRequest(index, spec), unix.RTM_DELLINK, flags)
+
+	return err
+}
+
+// ListLink retrieves the bridges and bridge ports as links of family
+// AF_BRIDGE, with their VLANs in the Bridge attributes of their AFSpec.
+// Ranges of VLANs are reported as single VLANs.
+func (b *BridgeVlanService) ListLink() ([]LinkMessage, error) {
+	return b.ListLinkContext(context.Background())
+}
+
+// ListLinkContext is like ListLink, but takes a context. See
+// Conn.ExecuteContext.
+func (b *BridgeVlanService) ListLinkContext(ctx context.Context) ([]LinkMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return b.c.Link.execute(ctx, bridgeLinkDumpRequest(), unix.RTM_GETLINK, flags)
+}
+
+// ListLinkSeq is like ListLink, but returns an iterator which yields the bridges and bridge ports
+// one at a time. See Conn.ExecuteSeq.
+func (b *BridgeVlanService) ListLinkSeq() iter.Seq2[LinkMessage, error] {
+	return b.ListLinkSeqContext(context.Background())
+}
+
+// ListLinkSeqContext is like ListLinkSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (b *BridgeVlanService) ListLinkSeqContext(ctx context.Context) iter.Seq2[LinkMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[LinkMessage](ctx, b.c, bridgeLinkDumpRequest(), unix.RTM_GETLINK, flags)
+}
+
+// bridgeLinkDumpRequest returns the request to dump the bridges and bridge
+// ports as links of family AF_BRIDGE, with their VLANs.
+func bridgeLinkDumpRequest() *LinkMessage {
+	extMask := uint32(unix.RTEXT_FILTER_BRVLAN)
+	return &LinkMessage{
+		Family: unix.AF_BRIDGE,
+		Attributes: &LinkAttributes{
+			ExtMask: &extMask,
+		},
+	}
+}
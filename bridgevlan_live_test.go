@@ -0,0 +1,194 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestBridgeVlan(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	const (
+		bridgeIndex = 3201
+		portIndex   = 3202
+	)
+
+	// VLANs of ports can only be managed with VLAN filtering enabled.
+	ae := netlink.NewAttributeEncoder()
+	ae.Uint8(unix.IFLA_BR_VLAN_FILTERING, 1)
+	data, err := ae.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode bridge attributes: %v", err)
+	}
+
+	err = conn.Link.New(&LinkMessage{
+		Index: bridgeIndex,
+		Attributes: &LinkAttributes{
+			Name: "vlanbr0",
+			Info: &LinkInfo{Kind: "bridge", Data: &LinkData{Name: "bridge", Data: data}},
+		},
+	})
+	if errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Skip("kernel without bridge VLAN filtering support")
+	}
+	if err != nil {
+		t.Fatalf("failed to create bridge: %v", err)
+	}
+
+	master := uint32(bridgeIndex)
+	err = conn.Link.New(&LinkMessage{
+		Index: portIndex,
+		Attributes: &LinkAttributes{
+			Name:   "vlanveth0",
+			Info:   &LinkInfo{Kind: "veth"},
+			Master: &master,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create bridge port: %v", err)
+	}
+
+	// portVlans returns the VLAN entries of the port.
+	portVlans := func() []BridgeVlanEntry {
+		t.Helper()
+
+		msgs, err := conn.BridgeVlan.List(&BridgeVlanMessage{Index: portIndex})
+		if err != nil {
+			t.Fatalf("failed to list vlans: %v", err)
+		}
+
+		var entries []BridgeVlanEntry
+		for _, m := range msgs {
+			if m.Index == portIndex && m.Attributes != nil {
+				entries = append(entries, m.Attributes.Entries...)
+			}
+		}
+		return entries
+	}
+
+	// findVlan returns the entry of the port starting with vid.
+	findVlan := func(vid uint16) *BridgeVlanEntry {
+		t.Helper()
+
+		for _, e := range portVlans() {
+			if e.Info.Vid == vid {
+				return &e
+			}
+		}
+		return nil
+	}
+
+	var (
+		end     = uint16(20)
+		blocked = uint8(4) // BR_STATE_BLOCKING
+	)
+	err = conn.BridgeVlan.Add(&BridgeVlanMessage{
+		Index: portIndex,
+		Attributes: &BridgeVlanAttributes{
+			Entries: []BridgeVlanEntry{
+				{Info: BridgeVlanInfo{Vid: 10}, RangeEnd: &end},
+				{Info: BridgeVlanInfo{Vid: 30, Flags: unix.BRIDGE_VLAN_INFO_PVID | unix.BRIDGE_VLAN_INFO_UNTAGGED}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to add vlans: %v", err)
+	}
+
+	if e := findVlan(10); e == nil || e.RangeEnd == nil || *e.RangeEnd != end {
+		t.Fatalf("expected vlan range 10-20, got: %+v", portVlans())
+	}
+	if e := findVlan(30); e == nil || e.Info.Flags&unix.BRIDGE_VLAN_INFO_PVID == 0 || e.Info.Flags&unix.BRIDGE_VLAN_INFO_UNTAGGED == 0 {
+		t.Fatalf("expected untagged pvid 30, got: %+v", portVlans())
+	}
+
+	// Change an option of the VLANs of the range.
+	err = conn.BridgeVlan.Add(&BridgeVlanMessage{
+		Index: portIndex,
+		Attributes: &BridgeVlanAttributes{
+			Entries: []BridgeVlanEntry{
+				{Info: BridgeVlanInfo{Vid: 10, Flags: unix.BRIDGE_VLAN_INFO_ONLY_OPTS}, RangeEnd: &end, State: &blocked},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to set vlan options: %v", err)
+	}
+	if e := findVlan(10); e == nil || e.State == nil || *e.State != blocked {
+		t.Fatalf("expected blocked vlans 10-20, got: %+v", e)
+	}
+
+	err = conn.BridgeVlan.Delete(&BridgeVlanMessage{
+		Index: portIndex,
+		Attributes: &BridgeVlanAttributes{
+			Entries: []BridgeVlanEntry{{Info: BridgeVlanInfo{Vid: 10}, RangeEnd: &end}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to delete vlans: %v", err)
+	}
+	if e := findVlan(10); e != nil {
+		t.Fatalf("expected vlans 10-20 to be deleted, got: %+v", e)
+	}
+
+	// The same through the bridge attributes of the link.
+	spec := &LinkAFSpecBridge{
+		VlanInfo: []BridgeVlanInfo{{Vid: 40, Flags: unix.BRIDGE_VLAN_INFO_UNTAGGED}},
+	}
+	if err := conn.BridgeVlan.AddLink(portIndex, spec); err != nil {
+		t.Fatalf("failed to add vlan to link: %v", err)
+	}
+
+	// linkVlans returns the VLANs of the port reported for the link.
+	linkVlans := func() []BridgeVlanInfo {
+		t.Helper()
+
+		links, err := conn.BridgeVlan.ListLink()
+		if err != nil {
+			t.Fatalf("failed to list bridge links: %v", err)
+		}
+
+		for _, l := range links {
+			if l.Family != unix.AF_BRIDGE {
+				t.Fatalf("unexpected link family: %d", l.Family)
+			}
+			if l.Index == portIndex && l.Attributes.AFSpec != nil && l.Attributes.AFSpec.Bridge != nil {
+				return l.Attributes.AFSpec.Bridge.VlanInfo
+			}
+		}
+		t.Fatalf("no bridge attributes for the port in: %+v", links)
+		return nil
+	}
+
+	hasVlan := func(vid uint16) bool {
+		for _, v := range linkVlans() {
+			if v.Vid == vid {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !hasVlan(40) || !hasVlan(30) {
+		t.Fatalf("expected vlans 30 and 40, got: %+v", linkVlans())
+	}
+
+	if err := conn.BridgeVlan.DeleteLink(portIndex, spec); err != nil {
+		t.Fatalf("failed to delete vlan from link: %v", err)
+	}
+	if hasVlan(40) {
+		t.Fatalf("expected vlan 40 to be deleted, got: %+v", linkVlans())
+	}
+}
@@ -0,0 +1,190 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestBridgeVlanMessage(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m            Message
+		b            []byte
+		marshalErr   error
+		unmarshalErr error
+	}{
+		"empty": {
+			m: &BridgeVlanMessage{},
+			b: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		"entries": {
+			m: &BridgeVlanMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				Attributes: &BridgeVlanAttributes{
+					Entries: []BridgeVlanEntry{
+						{
+							Info:     BridgeVlanInfo{Vid: 10},
+							RangeEnd: uint16Ptr(20),
+							State:    uint8Ptr(4),
+						},
+						{
+							Info: BridgeVlanInfo{
+								Flags: unix.BRIDGE_VLAN_INFO_PVID | unix.BRIDGE_VLAN_INFO_UNTAGGED,
+								Vid:   30,
+							},
+							Tunnel:         &BridgeVlanTunnel{ID: 3000},
+							McastRouter:    uint8Ptr(1),
+							McastMaxGroups: uint32Ptr(100),
+							NeighSuppress:  uint8Ptr(1),
+						},
+					},
+				},
+			},
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x1c, 0x00, 0x01, 0x80,
+				0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x06, 0x00, 0x02, 0x00,
+				0x14, 0x00, 0x00, 0x00, 0x05, 0x00, 0x03, 0x00, 0x04, 0x00, 0x00, 0x00,
+				0x38, 0x00, 0x01, 0x80, 0x08, 0x00, 0x01, 0x00, 0x06, 0x00, 0x1e, 0x00,
+				0x14, 0x00, 0x04, 0x80, 0x08, 0x00, 0x01, 0x00, 0xb8, 0x0b, 0x00, 0x00,
+				0x08, 0x00, 0x02, 0x00, 0x13, 0x00, 0x00, 0x00, 0x05, 0x00, 0x06, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x08, 0x00, 0x08, 0x00, 0x64, 0x00, 0x00, 0x00,
+				0x05, 0x00, 0x09, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b []byte
+			t.Run("marshal", func(t *testing.T) {
+				var marshalErr error
+				b, marshalErr = tt.m.MarshalBinary()
+
+				if !errors.Is(marshalErr, tt.marshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.marshalErr, marshalErr)
+				}
+			})
+
+			t.Run("compare bytes", func(t *testing.T) {
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+
+			m := &BridgeVlanMessage{}
+			t.Run("unmarshal", func(t *testing.T) {
+				unmarshalErr := (m).UnmarshalBinary(b)
+				if !errors.Is(unmarshalErr, tt.unmarshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.unmarshalErr, unmarshalErr)
+				}
+			})
+
+			t.Run("compare messages", func(t *testing.T) {
+				if !reflect.DeepEqual(tt.m, m) {
+					t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", tt.m, m)
+				}
+			})
+		})
+	}
+
+	t.Run("request", func(t *testing.T) {
+		tests := map[string]struct {
+			m *BridgeVlanMessage
+			b []byte
+		}{
+			"remove tunnel": {
+				m: &BridgeVlanMessage{
+					Family: unix.AF_BRIDGE,
+					Index:  3,
+					Attributes: &BridgeVlanAttributes{
+						Entries: []BridgeVlanEntry{{
+							Info:   BridgeVlanInfo{Vid: 30},
+							Tunnel: &BridgeVlanTunnel{ID: 3000, Remove: true},
+						}},
+					},
+				},
+				b: []byte{
+					0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x20, 0x00, 0x01, 0x80,
+					0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x1e, 0x00, 0x14, 0x00, 0x04, 0x80,
+					0x08, 0x00, 0x01, 0x00, 0xb8, 0x0b, 0x00, 0x00, 0x08, 0x00, 0x02, 0x00,
+					0x11, 0x00, 0x00, 0x00,
+				},
+			},
+			"dump statistics": {
+				m: &BridgeVlanMessage{
+					Family: unix.AF_BRIDGE,
+					Attributes: &BridgeVlanAttributes{
+						DumpFlags: uint32Ptr(unix.BRIDGE_VLANDB_DUMPF_STATS),
+					},
+				},
+				b: []byte{
+					0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00,
+					0x01, 0x00, 0x00, 0x00,
+				},
+			},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				b, err := tt.m.MarshalBinary()
+				if err != nil {
+					t.Fatalf("failed to marshal: %v", err)
+				}
+
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		b := []byte{
+			0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x40, 0x00, 0x01, 0x80,
+			0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x34, 0x00, 0x05, 0x80,
+			0x0c, 0x00, 0x01, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x0c, 0x00, 0x02, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x0c, 0x00, 0x03, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x0c, 0x00, 0x04, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		}
+
+		want := &BridgeVlanMessage{
+			Family: unix.AF_BRIDGE,
+			Index:  3,
+			Attributes: &BridgeVlanAttributes{
+				Entries: []BridgeVlanEntry{{
+					Info: BridgeVlanInfo{Vid: 10},
+					Stats: &BridgeVlanStats{
+						RXBytes:   4096,
+						RXPackets: 16,
+						TXBytes:   8192,
+						TXPackets: 32,
+					},
+				}},
+			},
+		}
+
+		m := &BridgeVlanMessage{}
+		if err := m.UnmarshalBinary(b); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+
+		if !reflect.DeepEqual(want, m) {
+			t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", want, m)
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &BridgeVlanMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x07, 0x00, 0x00})
+		if !errors.Is(unmarshalErr, errInvalidBridgeVlanMessage) {
+			t.Fatalf("Expected 'errInvalidBridgeVlanMessage' but got '%v'", unmarshalErr)
+		}
+	})
+}
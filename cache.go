@@ -0,0 +1,637 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// cacheGroups are the multicast groups a Cache keeps itself current from.
+var cacheGroups = []uint32{
+	unix.RTNLGRP_LINK,
+	unix.RTNLGRP_IPV4_IFADDR,
+	unix.RTNLGRP_IPV6_IFADDR,
+	unix.RTNLGRP_IPV4_ROUTE,
+	unix.RTNLGRP_IPV6_ROUTE,
+}
+
+// A Cache is a local, indexed copy of the links, addresses and routes of a
+// network namespace.
+//
+// Run performs an initial dump through the Link, Address and Route services
+// and then keeps the Cache current from rtnetlink notifications. Whenever
+// notifications are lost because the socket receive buffer overran, or a dump
+// is interrupted by a concurrent change (NLM_F_DUMP_INTR), the Cache is
+// re-dumped.
+//
+// Messages returned by a Cache are shared with the Cache and must not be
+// modified.
+type Cache struct {
+	dump, watch *Conn
+
+	synced   chan struct{}
+	syncOnce sync.Once
+
+	mu       sync.RWMutex
+	handlers []func(Event)
+	links    map[uint32]LinkMessage
+	names    map[string]uint32
+	addrs    map[addressKey]AddressMessage
+	routes   map[routeKey]RouteMessage
+
+	addrsByIndex   map[uint32]map[addressKey]struct{}
+	routesByIndex  map[uint32]map[routeKey]struct{}
+	routesByTable  map[uint32]map[routeKey]struct{}
+	routesByPrefix map[prefixKey]map[routeKey]struct{}
+}
+
+// addressKey identifies an address the way the kernel does.
+type addressKey struct {
+	family uint8
+	index  uint32
+	length uint8
+	addr   string
+}
+
+// prefixKey identifies a route destination prefix.
+type prefixKey struct {
+	family uint8
+	dst    string
+	length uint8
+}
+
+// routeKey identifies a route the way the kernel does.
+type routeKey struct {
+	prefixKey
+	table    uint32
+	src      string
+	srcLen   uint8
+	tos      uint8
+	priority uint32
+}
+
+// NewCache dials the route netlink connections used by a Cache. Config
+// specifies optional configuration for the underlying netlink connections.
+// If config is nil, a default configuration will be used.
+func NewCache(config *netlink.Config) (*Cache, error) {
+	dump, err := Dial(config)
+	if err != nil {
+		return nil, err
+	}
+
+	watch, err := Dial(config)
+	if err != nil {
+		_ = dump.Close()
+		return nil, err
+	}
+
+	return newCache(dump, watch), nil
+}
+
+// newCache creates a Cache which dumps over the dump Conn and receives
+// notifications on the watch Conn. It is used for testing.
+func newCache(dump, watch *Conn) *Cache {
+	dump.failInterrupted = true
+
+	return &Cache{
+		dump:   dump,
+		watch:  watch,
+		synced: make(chan struct{}),
+
+		links:  make(map[uint32]LinkMessage),
+		names:  make(map[string]uint32),
+		addrs:  make(map[addressKey]AddressMessage),
+		routes: make(map[routeKey]RouteMessage),
+
+		addrsByIndex:   make(map[uint32]map[addressKey]struct{}),
+		routesByIndex:  make(map[uint32]map[routeKey]struct{}),
+		routesByTable:  make(map[uint32]map[routeKey]struct{}),
+		routesByPrefix: make(map[prefixKey]map[routeKey]struct{}),
+	}
+}
+
+// Close closes the connections of the Cache.
+func (c *Cache) Close() error {
+	err := c.watch.Close()
+	if derr := c.dump.Close(); err == nil {
+		err = derr
+	}
+	return err
+}
+
+// AddHandler registers fn to be called for every change to the Cache.
+//
+// fn is called synchronously from Run, in the order the changes were applied,
+// with a LinkEvent, AddressEvent or RouteEvent. The Op of the event is
+// relative to the Cache: a notification for an object which is already cached
+// is reported as EventChanged. Changes found by a re-dump are reported as
+// events as well, so handlers never see a ResyncEvent. fn may call the lookup
+// methods of the Cache, but must not block.
+func (c *Cache) AddHandler(fn func(Event)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers = append(c.handlers, fn)
+}
+
+// Run fills the Cache and keeps it current until ctx is done or an error
+// occurs. Run must only be called once.
+func (c *Cache) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sub, err := c.watch.Subscribe(ctx, cacheGroups...)
+	if err != nil {
+		return err
+	}
+	// When returning early the Subscription is ended as well, which leaves
+	// the multicast groups before Run returns.
+	defer func() {
+		cancel()
+		for range sub.Events() {
+		}
+	}()
+
+	// Notifications received while dumping are queued on the subscription
+	// and applied afterwards, which leaves the Cache consistent since they
+	// are delivered in the order the kernel applied them.
+	if err := c.resync(ctx); err != nil {
+		return err
+	}
+	c.syncOnce.Do(func() { close(c.synced) })
+
+	for ev := range sub.Events() {
+		if _, ok := ev.(ResyncEvent); ok {
+			if err := c.resync(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		events := c.apply(ev)
+		c.mu.Unlock()
+		c.notify(events)
+	}
+
+	return sub.Err()
+}
+
+// WaitForSync blocks until the initial dump of the Cache has completed or ctx
+// is done.
+func (c *Cache) WaitForSync(ctx context.Context) error {
+	select {
+	case <-c.synced:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Link returns the link with the given interface index.
+func (c *Cache) Link(index uint32) (LinkMessage, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	l, ok := c.links[index]
+	return l, ok
+}
+
+// LinkByName returns the link with the given interface name.
+func (c *Cache) LinkByName(name string) (LinkMessage, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index, ok := c.names[name]
+	if !ok {
+		return LinkMessage{}, false
+	}
+	return c.links[index], true
+}
+
+// Links returns all links, sorted by interface index.
+func (c *Cache) Links() []LinkMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	links := make([]LinkMessage, 0, len(c.links))
+	for _, l := range c.links {
+		links = append(links, l)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Index < links[j].Index })
+
+	return links
+}
+
+// Addresses returns all addresses, in no particular order.
+func (c *Cache) Addresses() []AddressMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	addrs := make([]AddressMessage, 0, len(c.addrs))
+	for _, a := range c.addrs {
+		addrs = append(addrs, a)
+	}
+
+	return addrs
+}
+
+// AddressesByIndex returns the addresses of the link with the given interface
+// index, in no particular order.
+func (c *Cache) AddressesByIndex(index uint32) []AddressMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	addrs := make([]AddressMessage, 0, len(c.addrsByIndex[index]))
+	for k := range c.addrsByIndex[index] {
+		addrs = append(addrs, c.addrs[k])
+	}
+
+	return addrs
+}
+
+// Routes returns all routes, in no particular order.
+func (c *Cache) Routes() []RouteMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	routes := make([]RouteMessage, 0, len(c.routes))
+	for _, r := range c.routes {
+		routes = append(routes, r)
+	}
+
+	return routes
+}
+
+// RoutesByTable returns the routes in the given routing table, in no
+// particular order.
+func (c *Cache) RoutesByTable(table uint32) []RouteMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.routesFor(c.routesByTable[table])
+}
+
+// RoutesByPrefix returns the routes to exactly the given destination prefix
+// in any routing table, in no particular order.
+func (c *Cache) RoutesByPrefix(dst *net.IPNet) []RouteMessage {
+	k := prefixKey{family: unix.AF_INET6, dst: string(dst.IP.To16())}
+	if dst.IP.To4() != nil {
+		k = prefixKey{family: unix.AF_INET, dst: string(dst.IP.To4())}
+	}
+	ones, _ := dst.Mask.Size()
+	k.length = uint8(ones)
+
+	// The kernel omits the destination of default routes.
+	if ones == 0 {
+		k.dst = ""
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.routesFor(c.routesByPrefix[k])
+}
+
+func (c *Cache) routesFor(keys map[routeKey]struct{}) []RouteMessage {
+	routes := make([]RouteMessage, 0, len(keys))
+	for k := range keys {
+		routes = append(routes, c.routes[k])
+	}
+
+	return routes
+}
+
+// resync dumps all links, addresses and routes and replaces the contents of
+// the Cache with them, retrying as long as the dump is interrupted.
+func (c *Cache) resync(ctx context.Context) error {
+	for {
+		links, addrs, routes, err := c.list(ctx)
+		if errors.Is(err, errDumpInterrupted) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		events := c.replace(links, addrs, routes)
+		c.mu.Unlock()
+		c.notify(events)
+
+		return nil
+	}
+}
+
+func (c *Cache) list(ctx context.Context) ([]LinkMessage, []AddressMessage, []RouteMessage, error) {
+	links, err := c.dump.Link.ListContext(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	addrs, err := c.dump.Address.ListContext(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	routes, err := c.dump.Route.ListContext(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return links, addrs, routes, nil
+}
+
+// replace replaces the contents of the Cache with the given dump and returns
+// the events describing the differences. The caller must hold c.mu.
+func (c *Cache) replace(links []LinkMessage, addrs []AddressMessage, routes []RouteMessage) []Event {
+	var events []Event
+
+	seenLinks := make(map[uint32]struct{}, len(links))
+	for _, l := range links {
+		seenLinks[l.Index] = struct{}{}
+		if old, ok := c.links[l.Index]; !ok || !reflect.DeepEqual(old, l) {
+			events = append(events, c.setLink(l))
+		}
+	}
+	seenAddrs := make(map[addressKey]struct{}, len(addrs))
+	for _, a := range addrs {
+		k := newAddressKey(&a)
+		seenAddrs[k] = struct{}{}
+		if old, ok := c.addrs[k]; !ok || !reflect.DeepEqual(old, a) {
+			events = append(events, c.setAddress(k, a))
+		}
+	}
+	seenRoutes := make(map[routeKey]struct{}, len(routes))
+	for _, r := range routes {
+		k := newRouteKey(&r)
+		seenRoutes[k] = struct{}{}
+		if old, ok := c.routes[k]; !ok || !reflect.DeepEqual(old, r) {
+			events = append(events, c.setRoute(k, r))
+		}
+	}
+
+	for k := range c.routes {
+		if _, ok := seenRoutes[k]; !ok {
+			events = append(events, c.deleteRoute(k))
+		}
+	}
+	for k := range c.addrs {
+		if _, ok := seenAddrs[k]; !ok {
+			events = append(events, c.deleteAddress(k))
+		}
+	}
+	for index := range c.links {
+		if _, ok := seenLinks[index]; !ok {
+			events = append(events, c.deleteLink(index))
+		}
+	}
+
+	return events
+}
+
+// apply applies a notification to the Cache and returns the resulting events.
+// The caller must hold c.mu.
+func (c *Cache) apply(ev Event) []Event {
+	switch ev := ev.(type) {
+	case LinkEvent:
+		// Bridge ports are also reported with family AF_BRIDGE, carrying
+		// only their bridge attributes. RTM_DELLINK is sent as well when a
+		// port leaves its bridge, while the link itself remains.
+		if ev.Link.Family != unix.AF_UNSPEC {
+			return nil
+		}
+
+		if ev.Op == EventRemoved {
+			if _, ok := c.links[ev.Link.Index]; !ok {
+				return nil
+			}
+			// The kernel does not notify about the addresses and IPv4
+			// routes which are flushed along with the link.
+			var events []Event
+			for k := range c.addrsByIndex[ev.Link.Index] {
+				events = append(events, c.deleteAddress(k))
+			}
+			events = append(events, c.flushRoutes(ev.Link.Index, 0)...)
+			return append(events, c.deleteLink(ev.Link.Index))
+		}
+
+		events := []Event{c.setLink(ev.Link)}
+		if ev.Link.Flags&unix.IFF_UP == 0 {
+			// IPv4 routes are flushed without notification when the link
+			// goes down.
+			events = append(events, c.flushRoutes(ev.Link.Index, unix.AF_INET)...)
+		}
+		return events
+	case AddressEvent:
+		k := newAddressKey(&ev.Address)
+		if ev.Op == EventRemoved {
+			if _, ok := c.addrs[k]; !ok {
+				return nil
+			}
+			return []Event{c.deleteAddress(k)}
+		}
+		return []Event{c.setAddress(k, ev.Address)}
+	case RouteEvent:
+		k := newRouteKey(&ev.Route)
+		if ev.Op == EventRemoved {
+			if _, ok := c.routes[k]; !ok {
+				return nil
+			}
+			return []Event{c.deleteRoute(k)}
+		}
+		return []Event{c.setRoute(k, ev.Route)}
+	}
+
+	return nil
+}
+
+// flushRoutes removes the routes of the given family (or all families if
+// family is 0) which leave through the link with the given interface index.
+func (c *Cache) flushRoutes(index uint32, family uint8) []Event {
+	var events []Event
+	for k := range c.routesByIndex[index] {
+		if family == 0 || k.family == family {
+			events = append(events, c.deleteRoute(k))
+		}
+	}
+
+	return events
+}
+
+func (c *Cache) notify(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	c.mu.RLock()
+	handlers := c.handlers
+	c.mu.RUnlock()
+
+	for _, ev := range events {
+		for _, fn := range handlers {
+			fn(ev)
+		}
+	}
+}
+
+func (c *Cache) setLink(l LinkMessage) Event {
+	op := EventAdded
+	if old, ok := c.links[l.Index]; ok {
+		op = EventChanged
+		if name := linkName(&old); c.names[name] == l.Index {
+			delete(c.names, name)
+		}
+	}
+
+	c.links[l.Index] = l
+	if name := linkName(&l); name != "" {
+		c.names[name] = l.Index
+	}
+
+	return LinkEvent{Op: op, Link: l}
+}
+
+func (c *Cache) deleteLink(index uint32) Event {
+	l := c.links[index]
+	if name := linkName(&l); c.names[name] == index {
+		delete(c.names, name)
+	}
+	delete(c.links, index)
+
+	return LinkEvent{Op: EventRemoved, Link: l}
+}
+
+func (c *Cache) setAddress(k addressKey, a AddressMessage) Event {
+	op := EventAdded
+	if _, ok := c.addrs[k]; ok {
+		op = EventChanged
+	}
+
+	c.addrs[k] = a
+	addIndex(c.addrsByIndex, k.index, k)
+
+	return AddressEvent{Op: op, Address: a}
+}
+
+func (c *Cache) deleteAddress(k addressKey) Event {
+	a := c.addrs[k]
+	delete(c.addrs, k)
+	removeIndex(c.addrsByIndex, k.index, k)
+
+	return AddressEvent{Op: EventRemoved, Address: a}
+}
+
+func (c *Cache) setRoute(k routeKey, r RouteMessage) Event {
+	op := EventAdded
+	if old, ok := c.routes[k]; ok {
+		op = EventChanged
+		for _, index := range routeIfaces(&old) {
+			removeIndex(c.routesByIndex, index, k)
+		}
+	}
+
+	c.routes[k] = r
+	for _, index := range routeIfaces(&r) {
+		addIndex(c.routesByIndex, index, k)
+	}
+	addIndex(c.routesByTable, k.table, k)
+	addIndex(c.routesByPrefix, k.prefixKey, k)
+
+	return RouteEvent{Op: op, Route: r}
+}
+
+func (c *Cache) deleteRoute(k routeKey) Event {
+	r := c.routes[k]
+	delete(c.routes, k)
+	for _, index := range routeIfaces(&r) {
+		removeIndex(c.routesByIndex, index, k)
+	}
+	removeIndex(c.routesByTable, k.table, k)
+	removeIndex(c.routesByPrefix, k.prefixKey, k)
+
+	return RouteEvent{Op: EventRemoved, Route: r}
+}
+
+func linkName(l *LinkMessage) string {
+	if l.Attributes == nil {
+		return ""
+	}
+	return l.Attributes.Name
+}
+
+func newAddressKey(a *AddressMessage) addressKey {
+	k := addressKey{
+		family: a.Family,
+		index:  a.Index,
+		length: a.PrefixLength,
+	}
+	if a.Attributes != nil {
+		// IFA_LOCAL is the address of the interface, IFA_ADDRESS the peer
+		// address on point-to-point links.
+		k.addr = string(a.Attributes.Local)
+		if k.addr == "" {
+			k.addr = string(a.Attributes.Address)
+		}
+	}
+
+	return k
+}
+
+func newRouteKey(r *RouteMessage) routeKey {
+	table := r.Attributes.Table
+	if table == 0 {
+		table = uint32(r.Table)
+	}
+
+	return routeKey{
+		prefixKey: prefixKey{
+			family: r.Family,
+			dst:    string(r.Attributes.Dst),
+			length: r.DstLength,
+		},
+		table:    table,
+		src:      string(r.Attributes.Src),
+		srcLen:   r.SrcLength,
+		tos:      r.Tos,
+		priority: r.Attributes.Priority,
+	}
+}
+
+// routeIfaces returns the interface indexes a route leaves through, which for
+// multipath routes are those of each of its nexthops.
+func routeIfaces(r *RouteMessage) []uint32 {
+	indexes := []uint32{r.Attributes.OutIface}
+	for _, nh := range r.Attributes.Multipath {
+		indexes = append(indexes, nh.Hop.IfIndex)
+	}
+
+	return indexes
+}
+
+func addIndex[I, K comparable](idx map[I]map[K]struct{}, i I, k K) {
+	keys, ok := idx[i]
+	if !ok {
+		keys = make(map[K]struct{})
+		idx[i] = keys
+	}
+	keys[k] = struct{}{}
+}
+
+func removeIndex[I, K comparable](idx map[I]map[K]struct{}, i I, k K) {
+	keys := idx[i]
+	delete(keys, k)
+	if len(keys) == 0 {
+		delete(idx, i)
+	}
+}
@@ -0,0 +1,323 @@
+//go:build linux
+// +build linux
+
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestCache(t *testing.T) {
+	skipBigEndian(t)
+
+	var (
+		lo = cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+			Index: 1, Flags: unix.IFF_UP, Attributes: &LinkAttributes{Name: "lo"},
+		})
+		eth0 = cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+			Index: 2, Flags: unix.IFF_UP, Attributes: &LinkAttributes{Name: "eth0"},
+		})
+		eth0Down = cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+			Index: 2, Attributes: &LinkAttributes{Name: "eth0"},
+		})
+		eth1 = cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+			Index: 3, Attributes: &LinkAttributes{Name: "eth1"},
+		})
+		addr1 = cacheMessage(unix.RTM_NEWADDR, &AddressMessage{
+			Family: unix.AF_INET, PrefixLength: 24, Index: 2,
+			Attributes: &AddressAttributes{Address: net.IPv4(10, 0, 0, 1).To4(), Local: net.IPv4(10, 0, 0, 1).To4()},
+		})
+		addr2 = cacheMessage(unix.RTM_NEWADDR, &AddressMessage{
+			Family: unix.AF_INET, PrefixLength: 24, Index: 2,
+			Attributes: &AddressAttributes{Address: net.IPv4(10, 0, 0, 2).To4(), Local: net.IPv4(10, 0, 0, 2).To4()},
+		})
+		route4 = cacheMessage(unix.RTM_NEWROUTE, &RouteMessage{
+			Family: unix.AF_INET, DstLength: 24, Table: unix.RT_TABLE_MAIN,
+			Attributes: RouteAttributes{Dst: net.IPv4(10, 0, 0, 0).To4(), OutIface: 2},
+		})
+		route6 = cacheMessage(unix.RTM_NEWROUTE, &RouteMessage{
+			Family: unix.AF_INET6, DstLength: 64, Table: unix.RT_TABLE_MAIN,
+			Attributes: RouteAttributes{Dst: net.ParseIP("2001:db8::"), OutIface: 2, Table: unix.RT_TABLE_MAIN},
+		})
+	)
+
+	dc := &cacheDumpConn{
+		interrupt: 1,
+		replies: map[netlink.HeaderType][]netlink.Message{
+			unix.RTM_GETLINK:  {lo, eth0},
+			unix.RTM_GETADDR:  {addr1},
+			unix.RTM_GETROUTE: {route4, route6},
+		},
+	}
+	_, wc := testSubscribeConn(t)
+	c := newCache(newConn(dc), newConn(wc))
+
+	events := make(chan Event, 16)
+	c.AddHandler(func(ev Event) { events <- ev })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	if err := c.WaitForSync(ctx); err != nil {
+		t.Fatalf("failed to wait for sync: %v", err)
+	}
+	if want, got := 4, dc.dumps(unix.RTM_GETLINK)+dc.dumps(unix.RTM_GETADDR)+dc.dumps(unix.RTM_GETROUTE); want != got {
+		t.Fatalf("unexpected number of dumps: want %d, got %d", want, got)
+	}
+
+	expectEvents(t, events, []Event{
+		LinkEvent{Op: EventAdded, Link: *cacheUnpack(t, lo).(*LinkMessage)},
+		LinkEvent{Op: EventAdded, Link: *cacheUnpack(t, eth0).(*LinkMessage)},
+		AddressEvent{Op: EventAdded, Address: *cacheUnpack(t, addr1).(*AddressMessage)},
+		RouteEvent{Op: EventAdded, Route: *cacheUnpack(t, route4).(*RouteMessage)},
+		RouteEvent{Op: EventAdded, Route: *cacheUnpack(t, route6).(*RouteMessage)},
+	})
+
+	if l, ok := c.LinkByName("eth0"); !ok || l.Index != 2 {
+		t.Fatalf("unexpected link by name: %v, %v", l, ok)
+	}
+	if l, ok := c.Link(1); !ok || l.Attributes.Name != "lo" {
+		t.Fatalf("unexpected link by index: %v, %v", l, ok)
+	}
+	if want, got := 2, len(c.Links()); want != got {
+		t.Fatalf("unexpected number of links: want %d, got %d", want, got)
+	}
+	if want, got := 1, len(c.AddressesByIndex(2)); want != got {
+		t.Fatalf("unexpected number of addresses: want %d, got %d", want, got)
+	}
+	if want, got := 2, len(c.RoutesByTable(unix.RT_TABLE_MAIN)); want != got {
+		t.Fatalf("unexpected number of routes in main table: want %d, got %d", want, got)
+	}
+	_, dst, _ := net.ParseCIDR("2001:db8::/64")
+	if rs := c.RoutesByPrefix(dst); len(rs) != 1 || rs[0].Family != unix.AF_INET6 {
+		t.Fatalf("unexpected routes by prefix: %v", rs)
+	}
+
+	// An address is added and the link goes down, which flushes its IPv4
+	// routes without a notification.
+	wc.recv <- subscribeResult{msgs: []netlink.Message{addr2, eth0Down}}
+	expectEvents(t, events, []Event{
+		AddressEvent{Op: EventAdded, Address: *cacheUnpack(t, addr2).(*AddressMessage)},
+		LinkEvent{Op: EventChanged, Link: *cacheUnpack(t, eth0Down).(*LinkMessage)},
+		RouteEvent{Op: EventRemoved, Route: *cacheUnpack(t, route4).(*RouteMessage)},
+	})
+
+	// Notifications are lost, the re-dump reports the differences.
+	dc.setReplies(map[netlink.HeaderType][]netlink.Message{
+		unix.RTM_GETLINK:  {lo, eth0Down, eth1},
+		unix.RTM_GETADDR:  {addr1},
+		unix.RTM_GETROUTE: {route6},
+	})
+	wc.recv <- subscribeResult{err: &netlink.OpError{Op: "receive", Err: os.NewSyscallError("recvmsg", unix.ENOBUFS)}}
+	expectEvents(t, events, []Event{
+		LinkEvent{Op: EventAdded, Link: *cacheUnpack(t, eth1).(*LinkMessage)},
+		AddressEvent{Op: EventRemoved, Address: *cacheUnpack(t, addr2).(*AddressMessage)},
+	})
+
+	// Bridge port notifications of family AF_BRIDGE, including the
+	// RTM_DELLINK sent when a port leaves its bridge, leave the link alone:
+	// no events are emitted ahead of the removal below.
+	master := uint32(4)
+	port := cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+		Index: 2, Attributes: &LinkAttributes{Master: &master},
+	})
+	port.Data[0] = unix.AF_BRIDGE // ifi_family
+	portDel := port
+	portDel.Header.Type = unix.RTM_DELLINK
+	wc.recv <- subscribeResult{msgs: []netlink.Message{port, portDel}}
+
+	// Removing a link also removes its addresses and routes.
+	del := eth0Down
+	del.Header.Type = unix.RTM_DELLINK
+	wc.recv <- subscribeResult{msgs: []netlink.Message{del}}
+	expectEvents(t, events, []Event{
+		AddressEvent{Op: EventRemoved, Address: *cacheUnpack(t, addr1).(*AddressMessage)},
+		RouteEvent{Op: EventRemoved, Route: *cacheUnpack(t, route6).(*RouteMessage)},
+		LinkEvent{Op: EventRemoved, Link: *cacheUnpack(t, eth0Down).(*LinkMessage)},
+	})
+
+	if _, ok := c.LinkByName("eth0"); ok {
+		t.Fatal("removed link is still cached")
+	}
+	if want, got := 0, len(c.Addresses())+len(c.Routes()); want != got {
+		t.Fatalf("unexpected number of addresses and routes: want %d, got %d", want, got)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestCacheMultipath(t *testing.T) {
+	skipBigEndian(t)
+
+	var (
+		eth0 = cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+			Index: 2, Flags: unix.IFF_UP, Attributes: &LinkAttributes{Name: "eth0"},
+		})
+		eth1 = cacheMessage(unix.RTM_NEWLINK, &LinkMessage{
+			Index: 3, Flags: unix.IFF_UP, Attributes: &LinkAttributes{Name: "eth1"},
+		})
+		route = cacheMessage(unix.RTM_NEWROUTE, &RouteMessage{
+			Family: unix.AF_INET6, DstLength: 64, Table: unix.RT_TABLE_MAIN,
+			Attributes: RouteAttributes{
+				Dst:   net.ParseIP("2001:db8::"),
+				Table: unix.RT_TABLE_MAIN,
+				Multipath: []NextHop{
+					{Hop: RTNextHop{Length: 16, IfIndex: 2}, Gateway: net.ParseIP("fe80::1")},
+					{Hop: RTNextHop{Length: 16, IfIndex: 3}, Gateway: net.ParseIP("fe80::2")},
+				},
+			},
+		})
+	)
+
+	dc := &cacheDumpConn{
+		replies: map[netlink.HeaderType][]netlink.Message{
+			unix.RTM_GETLINK:  {eth0, eth1},
+			unix.RTM_GETROUTE: {route},
+		},
+	}
+	_, wc := testSubscribeConn(t)
+	c := newCache(newConn(dc), newConn(wc))
+
+	events := make(chan Event, 16)
+	c.AddHandler(func(ev Event) { events <- ev })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	if err := c.WaitForSync(ctx); err != nil {
+		t.Fatalf("failed to wait for sync: %v", err)
+	}
+	expectEvents(t, events, []Event{
+		LinkEvent{Op: EventAdded, Link: *cacheUnpack(t, eth0).(*LinkMessage)},
+		LinkEvent{Op: EventAdded, Link: *cacheUnpack(t, eth1).(*LinkMessage)},
+		RouteEvent{Op: EventAdded, Route: *cacheUnpack(t, route).(*RouteMessage)},
+	})
+
+	// Removing the link of one of the nexthops removes the route.
+	del := eth1
+	del.Header.Type = unix.RTM_DELLINK
+	wc.recv <- subscribeResult{msgs: []netlink.Message{del}}
+	expectEvents(t, events, []Event{
+		RouteEvent{Op: EventRemoved, Route: *cacheUnpack(t, route).(*RouteMessage)},
+		LinkEvent{Op: EventRemoved, Link: *cacheUnpack(t, eth1).(*LinkMessage)},
+	})
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestCacheRunError(t *testing.T) {
+	wantErr := errors.New("dump failed")
+	dc := &cacheDumpConn{err: wantErr}
+	_, wc := testSubscribeConn(t)
+	c := newCache(newConn(dc), newConn(wc))
+
+	if err := c.Run(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got: %v", wantErr, err)
+	}
+
+	// The Subscription has ended before Run returned.
+	if want, got := cacheGroups, wc.left; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected left groups:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func expectEvents(t *testing.T, events <-chan Event, want []Event) {
+	t.Helper()
+
+	got := make([]Event, 0, len(want))
+	for len(got) < len(want) {
+		got = append(got, <-events)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected events:\n- want: %#v\n-  got: %#v", want, got)
+	}
+}
+
+func cacheMessage(typ netlink.HeaderType, m Message) netlink.Message {
+	return netlink.Message{
+		Header: netlink.Header{Type: typ},
+		Data:   mustMarshal(m),
+	}
+}
+
+func cacheUnpack(t *testing.T, nm netlink.Message) Message {
+	t.Helper()
+
+	msgs, err := unpackMessages([]netlink.Message{nm})
+	if err != nil {
+		t.Fatalf("failed to unpack message: %v", err)
+	}
+	return msgs[0]
+}
+
+// cacheDumpConn is a conn which replies to dump requests with the configured
+// messages, flagging the first interrupt link dumps as interrupted, or fails
+// them with err.
+type cacheDumpConn struct {
+	mu        sync.Mutex
+	interrupt int
+	replies   map[netlink.HeaderType][]netlink.Message
+	count     map[netlink.HeaderType]int
+	err       error
+
+	noopConn
+}
+
+func (c *cacheDumpConn) Execute(m netlink.Message) ([]netlink.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.count == nil {
+		c.count = make(map[netlink.HeaderType]int)
+	}
+	c.count[m.Header.Type]++
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	msgs := append([]netlink.Message(nil), c.replies[m.Header.Type]...)
+	if m.Header.Type == unix.RTM_GETLINK && c.interrupt > 0 {
+		c.interrupt--
+		for i := range msgs {
+			msgs[i].Header.Flags |= netlink.DumpInterrupted
+		}
+	}
+
+	return msgs, nil
+}
+
+func (c *cacheDumpConn) setReplies(replies map[netlink.HeaderType][]netlink.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.replies = replies
+}
+
+func (c *cacheDumpConn) dumps(typ netlink.HeaderType) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.count[typ]
+}
@@ -1,7 +1,11 @@
 package rtnetlink
 
 import (
+	"context"
 	"encoding"
+	"errors"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
@@ -12,14 +16,37 @@ import (
 // A Conn is a route netlink connection. A Conn can be used to send and
 // receive route netlink messages to and from netlink.
 type Conn struct {
-	c       conn
-	Link    *LinkService
-	Address *AddressService
-	Route   *RouteService
-	Neigh   *NeighService
-	Rule    *RuleService
+	c          conn
+	Link       *LinkService
+	Address    *AddressService
+	Route      *RouteService
+	Neigh      *NeighService
+	NeighTable *NeighTableService
+	Rule       *RuleService
+	Nexthop    *NexthopService
+	NSID       *NSIDService
+	Netconf    *NetconfService
+	Stats      *StatsService
+	BridgeVlan *BridgeVlanService
+	MDB        *MDBService
+	Qdisc      *QdiscService
+	Class      *ClassService
+	Filter     *FilterService
+
+	// failInterrupted makes Execute fail with errDumpInterrupted when a dump
+	// was interrupted by a concurrent change, instead of returning the
+	// possibly inconsistent results.
+	failInterrupted bool
+
+	// mu serializes requests, so that a reply consumed while iterating by
+	// ExecuteSeq is not received by another request.
+	mu sync.Mutex
 }
 
+// errDumpInterrupted is returned by Execute if failInterrupted is set and a
+// reply carried NLM_F_DUMP_INTR.
+var errDumpInterrupted = errors.New("rtnetlink dump was interrupted")
+
 var _ conn = &netlink.Conn{}
 
 // A conn is a netlink connection, which can be swapped for tests.
@@ -37,12 +64,20 @@ type conn interface {
 // Dial dials a route netlink connection.  Config specifies optional
 // configuration for the underlying netlink connection.  If config is
 // nil, a default configuration will be used.
+//
+// The connection requests extended acknowledgements from the kernel, so that
+// errors returned by Execute carry the message and offending attribute
+// reported by the kernel. See OpError.
 func Dial(config *netlink.Config) (*Conn, error) {
 	c, err := netlink.Dial(unix.NETLINK_ROUTE, config)
 	if err != nil {
 		return nil, err
 	}
 
+	// Kernels without support for extended acknowledgements still report
+	// the error number, so a failure here is not fatal.
+	_ = c.SetOption(netlink.ExtendedAcknowledge, true)
+
 	return newConn(c), nil
 }
 
@@ -57,7 +92,17 @@ func newConn(c conn) *Conn {
 	rtc.Address = &AddressService{c: rtc}
 	rtc.Route = &RouteService{c: rtc}
 	rtc.Neigh = &NeighService{c: rtc}
+	rtc.NeighTable = &NeighTableService{c: rtc}
 	rtc.Rule = &RuleService{c: rtc}
+	rtc.Nexthop = &NexthopService{c: rtc}
+	rtc.NSID = &NSIDService{c: rtc}
+	rtc.Netconf = &NetconfService{c: rtc}
+	rtc.Stats = &StatsService{c: rtc}
+	rtc.BridgeVlan = &BridgeVlanService{c: rtc}
+	rtc.MDB = &MDBService{c: rtc}
+	rtc.Qdisc = &QdiscService{c: rtc}
+	rtc.Class = &ClassService{c: rtc}
+	rtc.Filter = &FilterService{c: rtc}
 
 	return rtc
 }
@@ -140,7 +185,17 @@ func (c *Conn) Receive() (rtmsgs []Message, msgs []netlink.Message, err error) {
 //
 // See the documentation of Send, Receive, and netlink.Validate for details
 // about each function.
+//
+// Errors returned by the kernel in reply to the request are of type *OpError.
 func (c *Conn) Execute(m Message, family uint16, flags netlink.HeaderFlags) ([]Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.execute(m, family, flags)
+}
+
+// execute implements Execute for a locked Conn.
+func (c *Conn) execute(m Message, family uint16, flags netlink.HeaderFlags) ([]Message, error) {
 	nm, err := packMessage(m, family, flags)
 	if err != nil {
 		return nil, err
@@ -148,12 +203,102 @@ func (c *Conn) Execute(m Message, family uint16, flags netlink.HeaderFlags) ([]M
 
 	msgs, err := c.c.Execute(nm)
 	if err != nil {
-		return nil, err
+		return nil, newOpError(nm, err)
+	}
+
+	if c.failInterrupted {
+		for _, m := range msgs {
+			if m.Header.Flags&netlink.DumpInterrupted != 0 {
+				return nil, errDumpInterrupted
+			}
+		}
 	}
 
 	return unpackMessages(msgs)
 }
 
+// drainTimeout bounds each read done by drain. The kernel queues rtnetlink
+// replies while handling the request, so it only has to cover the time
+// needed to produce the next part of a dump.
+var drainTimeout = time.Second
+
+// ExecuteContext is like Execute, but the request is canceled when ctx is
+// done, in which case ctx.Err() is returned.
+//
+// Canceling a request interrupts the blocking read of the reply by moving the
+// read deadline of the Conn into the past. The remainder of the reply is then
+// read and discarded, so that the Conn can be used for the next request. Any
+// read deadline set on the Conn is cleared by ExecuteContext.
+func (c *Conn) ExecuteContext(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]Message, error) {
+	if ctx.Done() == nil {
+		return c.Execute(m, family, flags)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stop := c.interruptOnDone(ctx)
+	msgs, err := c.execute(m, family, flags)
+	stop()
+
+	if err != nil && errors.Is(err, os.ErrDeadlineExceeded) && ctx.Err() != nil {
+		c.drain()
+		return nil, ctx.Err()
+	}
+
+	return msgs, err
+}
+
+// drain discards the rest of a reply left on the socket by an interrupted
+// Execute, so that it is not mistaken for the reply to the next request.
+func (c *Conn) drain() {
+	defer func() { _ = c.c.SetReadDeadline(time.Time{}) }()
+
+	for {
+		_ = c.c.SetReadDeadline(time.Now().Add(drainTimeout))
+		msgs, err := c.c.Receive()
+		if err != nil || len(msgs) == 0 {
+			// Either the reply ended in an error, or only the trailing
+			// NLMSG_DONE was left.
+			return
+		}
+
+		// Notifications carry no sequence number, replies always do. A
+		// reply is only returned by Receive once it is complete.
+		for _, m := range msgs {
+			if m.Header.Sequence != 0 {
+				return
+			}
+		}
+	}
+}
+
+// interruptOnDone interrupts blocking reads on the Conn once ctx is done by
+// moving the read deadline into the past. The returned function must be
+// called when reading has finished; it stops watching ctx and clears the read
+// deadline again.
+func (c *Conn) interruptOnDone(ctx context.Context) func() {
+	stop := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			_ = c.c.SetReadDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-exited
+		_ = c.c.SetReadDeadline(time.Time{})
+	}
+}
+
 // Message is the interface used for passing around different kinds of rtnetlink messages
 type Message interface {
 	encoding.BinaryMarshaler
@@ -185,23 +330,8 @@ func unpackMessages(msgs []netlink.Message) ([]Message, error) {
 	lmsgs := make([]Message, len(msgs))
 
 	for i, nm := range msgs {
-		var m Message
-		switch nm.Header.Type {
-		case unix.RTM_GETLINK, unix.RTM_NEWLINK, unix.RTM_DELLINK:
-			m = &LinkMessage{filtered: (nm.Header.Flags&netlink.DumpFiltered != 0)}
-		case unix.RTM_GETADDR, unix.RTM_NEWADDR, unix.RTM_DELADDR:
-			m = &AddressMessage{}
-		case unix.RTM_GETROUTE, unix.RTM_NEWROUTE, unix.RTM_DELROUTE:
-			m = &RouteMessage{}
-		case unix.RTM_GETNEIGH, unix.RTM_NEWNEIGH, unix.RTM_DELNEIGH:
-			m = &NeighMessage{}
-		case unix.RTM_GETRULE, unix.RTM_NEWRULE, unix.RTM_DELRULE:
-			m = &RuleMessage{}
-		default:
-			continue
-		}
-
-		if err := (m).UnmarshalBinary(nm.Data); err != nil {
+		m, err := unpackMessage(nm)
+		if err != nil {
 			return nil, err
 		}
 		lmsgs[i] = m
@@ -209,3 +339,49 @@ func unpackMessages(msgs []netlink.Message) ([]Message, error) {
 
 	return lmsgs, nil
 }
+
+// unpackMessage unpacks a rtnetlink Message from a netlink.Message. It
+// returns a nil Message for messages other than route messages.
+func unpackMessage(nm netlink.Message) (Message, error) {
+	var m Message
+	switch nm.Header.Type {
+	case unix.RTM_GETLINK, unix.RTM_NEWLINK, unix.RTM_DELLINK:
+		m = &LinkMessage{filtered: (nm.Header.Flags&netlink.DumpFiltered != 0)}
+	case unix.RTM_GETADDR, unix.RTM_NEWADDR, unix.RTM_DELADDR:
+		m = &AddressMessage{}
+	case unix.RTM_GETROUTE, unix.RTM_NEWROUTE, unix.RTM_DELROUTE:
+		m = &RouteMessage{}
+	case unix.RTM_GETNEIGH, unix.RTM_NEWNEIGH, unix.RTM_DELNEIGH:
+		m = &NeighMessage{}
+	case unix.RTM_GETNEIGHTBL, unix.RTM_NEWNEIGHTBL, unix.RTM_SETNEIGHTBL:
+		m = &NeighTableMessage{}
+	case unix.RTM_GETRULE, unix.RTM_NEWRULE, unix.RTM_DELRULE:
+		m = &RuleMessage{}
+	case unix.RTM_GETNEXTHOP, unix.RTM_NEWNEXTHOP, unix.RTM_DELNEXTHOP:
+		m = &NexthopMessage{}
+	case unix.RTM_GETNSID, unix.RTM_NEWNSID, unix.RTM_DELNSID:
+		m = &NSIDMessage{}
+	case unix.RTM_GETNETCONF, unix.RTM_NEWNETCONF, unix.RTM_DELNETCONF:
+		m = &NetconfMessage{}
+	case unix.RTM_GETSTATS, unix.RTM_NEWSTATS:
+		m = &StatsMessage{}
+	case unix.RTM_GETVLAN, unix.RTM_NEWVLAN, unix.RTM_DELVLAN:
+		m = &BridgeVlanMessage{}
+	case unix.RTM_GETMDB, unix.RTM_NEWMDB, unix.RTM_DELMDB:
+		m = &MDBMessage{}
+	case unix.RTM_GETQDISC, unix.RTM_NEWQDISC, unix.RTM_DELQDISC:
+		m = &TcMessage{object: tcQdisc}
+	case unix.RTM_GETTCLASS, unix.RTM_NEWTCLASS, unix.RTM_DELTCLASS:
+		m = &TcMessage{object: tcClass}
+	case unix.RTM_GETTFILTER, unix.RTM_NEWTFILTER, unix.RTM_DELTFILTER:
+		m = &TcMessage{object: tcFilter}
+	default:
+		return nil, nil
+	}
+
+	if err := m.UnmarshalBinary(nm.Data); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -4,7 +4,9 @@
 package rtnetlink
 
 import (
+	"context"
 	"encoding"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -183,6 +185,68 @@ func TestConnReceive(t *testing.T) {
 	}
 }
 
+func TestConnExecuteContext(t *testing.T) {
+	skipBigEndian(t)
+
+	t.Run("background", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.receive = []netlink.Message{{
+			Header: netlink.Header{Type: unix.RTM_NEWLINK},
+			Data:   mustMarshal(&LinkMessage{Index: 1}),
+		}}
+
+		msgs, err := c.ExecuteContext(context.Background(), &LinkMessage{}, unix.RTM_GETLINK, netlink.Request)
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err)
+		}
+		if want, got := []Message{&LinkMessage{Index: 1}}, msgs; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected replies:\n- want: %#v\n-  got: %#v", want, got)
+		}
+	})
+
+	t.Run("canceled before", func(t *testing.T) {
+		bc := newBlockingConn()
+		c := newConn(bc)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := c.Link.ListContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got: %v", err)
+		}
+		if bc.executed {
+			t.Fatal("request was sent for a canceled context")
+		}
+	})
+
+	t.Run("canceled during", func(t *testing.T) {
+		bc := newBlockingConn()
+		bc.pending = [][]netlink.Message{
+			// A notification queued before the rest of the reply.
+			{{Header: netlink.Header{Type: unix.RTM_NEWLINK}}},
+			{{Header: netlink.Header{Type: unix.RTM_NEWLINK, Sequence: 1}}},
+			{{Header: netlink.Header{Type: unix.RTM_NEWLINK, Sequence: 2}}},
+		}
+		c := newConn(bc)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-bc.started
+			cancel()
+		}()
+
+		if _, err := c.Link.ListContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got: %v", err)
+		}
+		if want, got := 1, len(bc.pending); want != got {
+			t.Fatalf("unexpected number of batches left on the socket: want %d, got %d", want, got)
+		}
+		if !bc.deadline.IsZero() {
+			t.Fatalf("read deadline was not cleared: %v", bc.deadline)
+		}
+	})
+}
+
 func testConn(t *testing.T) (*Conn, *testNetlinkConn) {
 	c := &testNetlinkConn{}
 	return newConn(c), c
@@ -191,6 +255,7 @@ func testConn(t *testing.T) (*Conn, *testNetlinkConn) {
 type testNetlinkConn struct {
 	send    netlink.Message
 	receive []netlink.Message
+	err     error
 
 	noopConn
 }
@@ -201,14 +266,67 @@ func (c *testNetlinkConn) Send(m netlink.Message) (netlink.Message, error) {
 }
 
 func (c *testNetlinkConn) Receive() ([]netlink.Message, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
 	return c.receive, nil
 }
 
 func (c *testNetlinkConn) Execute(m netlink.Message) ([]netlink.Message, error) {
 	c.send = m
+	if c.err != nil {
+		return nil, c.err
+	}
 	return c.receive, nil
 }
 
+// blockingConn is a conn which blocks in Execute until a read deadline is
+// set, and then hands out the pending batches of messages from Receive.
+type blockingConn struct {
+	started     chan struct{}
+	interrupted chan struct{}
+	executed    bool
+	pending     [][]netlink.Message
+	deadline    time.Time
+
+	noopConn
+}
+
+func newBlockingConn() *blockingConn {
+	return &blockingConn{
+		started:     make(chan struct{}),
+		interrupted: make(chan struct{}, 1),
+	}
+}
+
+func (c *blockingConn) Execute(m netlink.Message) ([]netlink.Message, error) {
+	c.executed = true
+	close(c.started)
+	<-c.interrupted
+	return nil, &netlink.OpError{Op: "receive", Err: os.ErrDeadlineExceeded}
+}
+
+func (c *blockingConn) Receive() ([]netlink.Message, error) {
+	if len(c.pending) == 0 {
+		return nil, &netlink.OpError{Op: "receive", Err: os.ErrDeadlineExceeded}
+	}
+
+	msgs := c.pending[0]
+	c.pending = c.pending[1:]
+	return msgs, nil
+}
+
+func (c *blockingConn) SetReadDeadline(t time.Time) error {
+	c.deadline = t
+	if !t.IsZero() && t.Before(time.Now()) {
+		select {
+		case c.interrupted <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
 type noopConn struct{}
 
 func (c *noopConn) Close() error                                         { return nil }
@@ -0,0 +1,123 @@
+package driver
+
+import (
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// BpfFlag represents BPF classifier flags.
+type BpfFlag uint32
+
+// BPF classifier flags.
+const (
+	// BpfFlagActDirect uses the return code of the program as the action
+	// (direct-action mode).
+	BpfFlagActDirect BpfFlag = unix.TCA_BPF_FLAG_ACT_DIRECT
+)
+
+// TcClsFlag represents the generic classifier flags.
+type TcClsFlag uint32
+
+// Generic classifier flags.
+const (
+	TcClsFlagSkipHw TcClsFlag = unix.TCA_CLS_FLAGS_SKIP_HW
+	TcClsFlagSkipSw TcClsFlag = unix.TCA_CLS_FLAGS_SKIP_SW
+)
+
+// Bpf represents a BPF classifier (filter) configuration.
+type Bpf struct {
+	// FD is the file descriptor of the BPF program to attach.
+	FD *uint32
+
+	// Name is the name of the BPF program.
+	Name *string
+
+	// ClassID is the class selected by the filter.
+	ClassID *uint32
+
+	// Flags specifies BPF classifier flags (direct action).
+	Flags *BpfFlag
+
+	// FlagsGen specifies generic classifier flags (skip hw, skip sw).
+	FlagsGen *TcClsFlag
+
+	// Tag is the tag of the attached BPF program.
+	// It is only reported by the kernel.
+	Tag []byte
+
+	// ID is the ID of the attached BPF program.
+	// It is only reported by the kernel.
+	ID *uint32
+}
+
+var _ rtnetlink.TcFilterDriver = &Bpf{}
+
+// New creates a new Bpf instance.
+func (b *Bpf) New() rtnetlink.TcDriver {
+	return &Bpf{}
+}
+
+// Kind returns the BPF classifier kind.
+func (b *Bpf) Kind() string {
+	return "bpf"
+}
+
+// Filter marks the driver as a filter driver.
+func (b *Bpf) Filter() {}
+
+// Encode encodes the BPF classifier configuration into netlink attributes.
+func (b *Bpf) Encode(ae *netlink.AttributeEncoder) error {
+	if b.FD != nil {
+		ae.Uint32(unix.TCA_BPF_FD, *b.FD)
+	}
+
+	if b.Name != nil {
+		ae.String(unix.TCA_BPF_NAME, *b.Name)
+	}
+
+	if b.ClassID != nil {
+		ae.Uint32(unix.TCA_BPF_CLASSID, *b.ClassID)
+	}
+
+	if b.Flags != nil {
+		ae.Uint32(unix.TCA_BPF_FLAGS, uint32(*b.Flags))
+	}
+
+	if b.FlagsGen != nil {
+		ae.Uint32(unix.TCA_BPF_FLAGS_GEN, uint32(*b.FlagsGen))
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the BPF classifier configuration.
+func (b *Bpf) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_BPF_FD:
+			fd := ad.Uint32()
+			b.FD = &fd
+		case unix.TCA_BPF_NAME:
+			name := ad.String()
+			b.Name = &name
+		case unix.TCA_BPF_CLASSID:
+			classID := ad.Uint32()
+			b.ClassID = &classID
+		case unix.TCA_BPF_FLAGS:
+			flags := BpfFlag(ad.Uint32())
+			b.Flags = &flags
+		case unix.TCA_BPF_FLAGS_GEN:
+			flags := TcClsFlag(ad.Uint32())
+			b.FlagsGen = &flags
+		case unix.TCA_BPF_TAG:
+			b.Tag = ad.Bytes()
+		case unix.TCA_BPF_ID:
+			id := ad.Uint32()
+			b.ID = &id
+		}
+	}
+
+	return ad.Err()
+}
@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"github.com/jsimonetti/rtnetlink/v2"
+
+	"github.com/mdlayher/netlink"
+)
+
+// Clsact represents a clsact queueing discipline, which provides ingress and
+// egress hooks for filters and has no configuration.
+//
+// Filters are attached to rtnetlink.TcHandle(0xffff, rtnetlink.TcHandleMinIngress)
+// or rtnetlink.TcHandle(0xffff, rtnetlink.TcHandleMinEgress).
+type Clsact struct{}
+
+var _ rtnetlink.TcDriver = &Clsact{}
+
+// New creates a new Clsact instance.
+func (c *Clsact) New() rtnetlink.TcDriver {
+	return &Clsact{}
+}
+
+// Kind returns the clsact queueing discipline kind.
+func (c *Clsact) Kind() string {
+	return "clsact"
+}
+
+// Encode encodes the clsact configuration into netlink attributes.
+func (c *Clsact) Encode(ae *netlink.AttributeEncoder) error {
+	return nil
+}
+
+// Decode decodes netlink attributes into the clsact configuration.
+func (c *Clsact) Decode(ad *netlink.AttributeDecoder) error {
+	return nil
+}
+
+// Ingress represents an ingress queueing discipline, which provides an
+// ingress hook for filters and has no configuration.
+type Ingress struct{}
+
+var _ rtnetlink.TcDriver = &Ingress{}
+
+// New creates a new Ingress instance.
+func (i *Ingress) New() rtnetlink.TcDriver {
+	return &Ingress{}
+}
+
+// Kind returns the ingress queueing discipline kind.
+func (i *Ingress) Kind() string {
+	return "ingress"
+}
+
+// Encode encodes the ingress configuration into netlink attributes.
+func (i *Ingress) Encode(ae *netlink.AttributeEncoder) error {
+	return nil
+}
+
+// Decode decodes netlink attributes into the ingress configuration.
+func (i *Ingress) Decode(ad *netlink.AttributeDecoder) error {
+	return nil
+}
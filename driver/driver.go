@@ -1,5 +1,5 @@
-// Package driver provides link type specific decoding and encoding types
-// for use with the rtnetlink library.
+// Package driver provides link type and traffic control kind specific
+// decoding and encoding types for use with the rtnetlink library.
 package driver
 
 import (
@@ -17,12 +17,43 @@ func init() {
 		&BondSlave{},
 		&Bridge{},
 		&BridgePort{},
+		&Erspan{},
+		&Geneve{},
+		&Gre{},
+		&Gretap{},
+		&Ip6Erspan{},
+		&Ip6Gre{},
+		&Ip6Gretap{},
+		&Ip6Tnl{},
+		&Ipip{},
+		&Ipvlan{},
+		&Ipvtap{},
 		&Macvlan{},
 		&Netkit{},
+		&Sit{},
 		&Veth{},
 		&Vlan{},
+		&Vrf{},
+		&VrfPort{},
 		&Vxlan{},
 	} {
 		_ = rtnetlink.RegisterDriver(drv)
 	}
+
+	for _, drv := range []rtnetlink.TcDriver{
+		&Bpf{},
+		&Clsact{},
+		&Flower{},
+		&Fq{},
+		&FqCodel{},
+		&Htb{},
+		&HtbClass{},
+		&Ingress{},
+		&Matchall{},
+		&Netem{},
+		&Tbf{},
+		&U32{},
+	} {
+		_ = rtnetlink.RegisterTcDriver(drv)
+	}
 }
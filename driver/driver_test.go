@@ -16,13 +16,13 @@ func setupInterface(conn *rtnetlink.Conn, name string, index, master uint32, dri
 	}
 	flag := uint32(unix.IFF_UP)
 	if master > 0 {
-		// Check if this is a VLAN, VXLAN, or MACVLAN interface
+		// Check if this is a VLAN, VXLAN, MACVLAN or IPVLAN interface
 		// These types need the parent interface specified via Type/IFLA_LINK
-		kind := driver.Kind()
-		if kind == "vlan" || kind == "vxlan" || kind == "macvlan" {
-			// For VLAN/VXLAN/MACVLAN, the master parameter is actually the parent link index
+		switch driver.Kind() {
+		case "vlan", "vxlan", "macvlan", "ipvlan", "ipvtap":
+			// For VLAN/VXLAN/MACVLAN/IPVLAN, the master parameter is actually the parent link index
 			attrs.Type = master
-		} else {
+		default:
 			// For other types (like dummy being added to bridge), master is for enslaving
 			attrs.Master = &master
 		}
@@ -0,0 +1,235 @@
+package driver
+
+import (
+	"net"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// Flower represents a flower classifier (filter) configuration, which matches
+// packets on the values of their header fields.
+//
+// IPv4 addresses and masks are matched with the IPv4 keys, all other addresses
+// with the IPv6 keys. Setting EthType to the matching ethertype is required to
+// match on addresses or the IP protocol.
+type Flower struct {
+	// ClassID is the class selected by the filter.
+	ClassID *uint32
+
+	// Indev limits the filter to packets received on the named interface.
+	Indev *string
+
+	// Ethernet addresses and their masks
+	EthDst     net.HardwareAddr
+	EthDstMask net.HardwareAddr
+	EthSrc     net.HardwareAddr
+	EthSrcMask net.HardwareAddr
+
+	// EthType is the ethertype (unix.ETH_P_*) in host byte order.
+	EthType *uint16
+
+	// IPProto is the IP protocol (unix.IPPROTO_*).
+	IPProto *uint8
+
+	// IP addresses and their masks
+	Src     net.IP
+	SrcMask net.IPMask
+	Dst     net.IP
+	DstMask net.IPMask
+
+	// TCP and UDP ports in host byte order. IPProto has to be set as well.
+	TCPSrc *uint16
+	TCPDst *uint16
+	UDPSrc *uint16
+	UDPDst *uint16
+
+	// VLAN ID and priority
+	VlanID   *uint16
+	VlanPrio *uint8
+
+	// Flags specifies generic classifier flags (skip hw, skip sw).
+	Flags *TcClsFlag
+}
+
+var _ rtnetlink.TcFilterDriver = &Flower{}
+
+// New creates a new Flower instance.
+func (f *Flower) New() rtnetlink.TcDriver {
+	return &Flower{}
+}
+
+// Kind returns the flower classifier kind.
+func (f *Flower) Kind() string {
+	return "flower"
+}
+
+// Filter marks the driver as a filter driver.
+func (f *Flower) Filter() {}
+
+// Encode encodes the flower classifier configuration into netlink attributes.
+func (f *Flower) Encode(ae *netlink.AttributeEncoder) error {
+	if f.ClassID != nil {
+		ae.Uint32(unix.TCA_FLOWER_CLASSID, *f.ClassID)
+	}
+
+	if f.Indev != nil {
+		ae.String(unix.TCA_FLOWER_INDEV, *f.Indev)
+	}
+
+	if f.EthDst != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_ETH_DST, f.EthDst)
+	}
+
+	if f.EthDstMask != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_ETH_DST_MASK, f.EthDstMask)
+	}
+
+	if f.EthSrc != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_ETH_SRC, f.EthSrc)
+	}
+
+	if f.EthSrcMask != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_ETH_SRC_MASK, f.EthSrcMask)
+	}
+
+	if f.EthType != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_ETH_TYPE, be16(*f.EthType))
+	}
+
+	if f.IPProto != nil {
+		ae.Uint8(unix.TCA_FLOWER_KEY_IP_PROTO, *f.IPProto)
+	}
+
+	encodeFlowerIP(ae, f.Src, unix.TCA_FLOWER_KEY_IPV4_SRC, unix.TCA_FLOWER_KEY_IPV6_SRC)
+	encodeFlowerMask(ae, f.SrcMask, unix.TCA_FLOWER_KEY_IPV4_SRC_MASK, unix.TCA_FLOWER_KEY_IPV6_SRC_MASK)
+	encodeFlowerIP(ae, f.Dst, unix.TCA_FLOWER_KEY_IPV4_DST, unix.TCA_FLOWER_KEY_IPV6_DST)
+	encodeFlowerMask(ae, f.DstMask, unix.TCA_FLOWER_KEY_IPV4_DST_MASK, unix.TCA_FLOWER_KEY_IPV6_DST_MASK)
+
+	if f.TCPSrc != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_TCP_SRC, be16(*f.TCPSrc))
+	}
+
+	if f.TCPDst != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_TCP_DST, be16(*f.TCPDst))
+	}
+
+	if f.UDPSrc != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_UDP_SRC, be16(*f.UDPSrc))
+	}
+
+	if f.UDPDst != nil {
+		ae.Bytes(unix.TCA_FLOWER_KEY_UDP_DST, be16(*f.UDPDst))
+	}
+
+	if f.VlanID != nil {
+		ae.Uint16(unix.TCA_FLOWER_KEY_VLAN_ID, *f.VlanID)
+	}
+
+	if f.VlanPrio != nil {
+		ae.Uint8(unix.TCA_FLOWER_KEY_VLAN_PRIO, *f.VlanPrio)
+	}
+
+	if f.Flags != nil {
+		ae.Uint32(unix.TCA_FLOWER_FLAGS, uint32(*f.Flags))
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the flower classifier configuration.
+func (f *Flower) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_FLOWER_CLASSID:
+			classID := ad.Uint32()
+			f.ClassID = &classID
+		case unix.TCA_FLOWER_INDEV:
+			indev := ad.String()
+			f.Indev = &indev
+		case unix.TCA_FLOWER_KEY_ETH_DST:
+			f.EthDst = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_ETH_DST_MASK:
+			f.EthDstMask = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_ETH_SRC:
+			f.EthSrc = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_ETH_SRC_MASK:
+			f.EthSrcMask = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_ETH_TYPE:
+			f.EthType = decodeBe16(ad)
+		case unix.TCA_FLOWER_KEY_IP_PROTO:
+			proto := ad.Uint8()
+			f.IPProto = &proto
+		case unix.TCA_FLOWER_KEY_IPV4_SRC, unix.TCA_FLOWER_KEY_IPV6_SRC:
+			f.Src = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_IPV4_SRC_MASK, unix.TCA_FLOWER_KEY_IPV6_SRC_MASK:
+			f.SrcMask = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_IPV4_DST, unix.TCA_FLOWER_KEY_IPV6_DST:
+			f.Dst = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_IPV4_DST_MASK, unix.TCA_FLOWER_KEY_IPV6_DST_MASK:
+			f.DstMask = ad.Bytes()
+		case unix.TCA_FLOWER_KEY_TCP_SRC:
+			f.TCPSrc = decodeBe16(ad)
+		case unix.TCA_FLOWER_KEY_TCP_DST:
+			f.TCPDst = decodeBe16(ad)
+		case unix.TCA_FLOWER_KEY_UDP_SRC:
+			f.UDPSrc = decodeBe16(ad)
+		case unix.TCA_FLOWER_KEY_UDP_DST:
+			f.UDPDst = decodeBe16(ad)
+		case unix.TCA_FLOWER_KEY_VLAN_ID:
+			id := ad.Uint16()
+			f.VlanID = &id
+		case unix.TCA_FLOWER_KEY_VLAN_PRIO:
+			prio := ad.Uint8()
+			f.VlanPrio = &prio
+		case unix.TCA_FLOWER_FLAGS:
+			flags := TcClsFlag(ad.Uint32())
+			f.Flags = &flags
+		}
+	}
+
+	return ad.Err()
+}
+
+// encodeFlowerIP encodes ip with the IPv4 or IPv6 attribute type.
+func encodeFlowerIP(ae *netlink.AttributeEncoder, ip net.IP, v4, v6 uint16) {
+	if ip == nil {
+		return
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ae.Bytes(v4, ip4)
+		return
+	}
+	ae.Bytes(v6, ip.To16())
+}
+
+// encodeFlowerMask encodes mask with the IPv4 or IPv6 attribute type.
+func encodeFlowerMask(ae *netlink.AttributeEncoder, mask net.IPMask, v4, v6 uint16) {
+	switch len(mask) {
+	case 0:
+	case net.IPv4len:
+		ae.Bytes(v4, mask)
+	default:
+		ae.Bytes(v6, mask)
+	}
+}
+
+// be16 returns v in network byte order.
+func be16(v uint16) []byte {
+	return []byte{byte(v >> 8), byte(v)}
+}
+
+// decodeBe16 decodes an attribute in network byte order.
+func decodeBe16(ad *netlink.AttributeDecoder) *uint16 {
+	var v uint16
+	ad.Do(func(b []byte) error {
+		if len(b) != 2 {
+			return errInvalidTcOptions
+		}
+		v = uint16(b[0])<<8 | uint16(b[1])
+		return nil
+	})
+	return &v
+}
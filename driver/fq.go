@@ -0,0 +1,182 @@
+package driver
+
+import (
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// Fq represents a fair queue (fq) queueing discipline configuration.
+type Fq struct {
+	// PacketLimit is the maximum number of queued packets.
+	PacketLimit *uint32
+
+	// FlowPacketLimit is the maximum number of queued packets per flow.
+	FlowPacketLimit *uint32
+
+	// Quantum is the credit per dequeue round in bytes.
+	Quantum *uint32
+
+	// InitialQuantum is the initial credit of new flows in bytes.
+	InitialQuantum *uint32
+
+	// RateEnable enables or disables flow pacing.
+	RateEnable *uint32
+
+	// FlowMaxRate is the maximum rate of a flow in bytes per second.
+	FlowMaxRate *uint32
+
+	// BucketsLog is the log2 of the number of hash table buckets.
+	BucketsLog *uint32
+
+	// FlowRefillDelay is the flow credit refill delay in microseconds.
+	FlowRefillDelay *uint32
+
+	// OrphanMask is the mask applied to the hash of orphaned packets.
+	OrphanMask *uint32
+
+	// LowRateThreshold is the rate in bytes per second below which flows
+	// are paced per packet.
+	LowRateThreshold *uint32
+
+	// CEThreshold is the sojourn time in microseconds above which packets
+	// are marked with ECN CE.
+	CEThreshold *uint32
+
+	// TimerSlack is the timer slack in nanoseconds.
+	TimerSlack *uint32
+
+	// Horizon is the time horizon in microseconds of packets timestamped in
+	// the future.
+	Horizon *uint32
+
+	// HorizonDrop drops packets beyond Horizon when set to 1, and caps their
+	// timestamp when set to 0.
+	HorizonDrop *uint8
+}
+
+var _ rtnetlink.TcDriver = &Fq{}
+
+// New creates a new Fq instance.
+func (f *Fq) New() rtnetlink.TcDriver {
+	return &Fq{}
+}
+
+// Kind returns the fq queueing discipline kind.
+func (f *Fq) Kind() string {
+	return "fq"
+}
+
+// Encode encodes the fq configuration into netlink attributes.
+func (f *Fq) Encode(ae *netlink.AttributeEncoder) error {
+	if f.PacketLimit != nil {
+		ae.Uint32(unix.TCA_FQ_PLIMIT, *f.PacketLimit)
+	}
+
+	if f.FlowPacketLimit != nil {
+		ae.Uint32(unix.TCA_FQ_FLOW_PLIMIT, *f.FlowPacketLimit)
+	}
+
+	if f.Quantum != nil {
+		ae.Uint32(unix.TCA_FQ_QUANTUM, *f.Quantum)
+	}
+
+	if f.InitialQuantum != nil {
+		ae.Uint32(unix.TCA_FQ_INITIAL_QUANTUM, *f.InitialQuantum)
+	}
+
+	if f.RateEnable != nil {
+		ae.Uint32(unix.TCA_FQ_RATE_ENABLE, *f.RateEnable)
+	}
+
+	if f.FlowMaxRate != nil {
+		ae.Uint32(unix.TCA_FQ_FLOW_MAX_RATE, *f.FlowMaxRate)
+	}
+
+	if f.BucketsLog != nil {
+		ae.Uint32(unix.TCA_FQ_BUCKETS_LOG, *f.BucketsLog)
+	}
+
+	if f.FlowRefillDelay != nil {
+		ae.Uint32(unix.TCA_FQ_FLOW_REFILL_DELAY, *f.FlowRefillDelay)
+	}
+
+	if f.OrphanMask != nil {
+		ae.Uint32(unix.TCA_FQ_ORPHAN_MASK, *f.OrphanMask)
+	}
+
+	if f.LowRateThreshold != nil {
+		ae.Uint32(unix.TCA_FQ_LOW_RATE_THRESHOLD, *f.LowRateThreshold)
+	}
+
+	if f.CEThreshold != nil {
+		ae.Uint32(unix.TCA_FQ_CE_THRESHOLD, *f.CEThreshold)
+	}
+
+	if f.TimerSlack != nil {
+		ae.Uint32(unix.TCA_FQ_TIMER_SLACK, *f.TimerSlack)
+	}
+
+	if f.Horizon != nil {
+		ae.Uint32(unix.TCA_FQ_HORIZON, *f.Horizon)
+	}
+
+	if f.HorizonDrop != nil {
+		ae.Uint8(unix.TCA_FQ_HORIZON_DROP, *f.HorizonDrop)
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the fq configuration.
+func (f *Fq) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_FQ_PLIMIT:
+			v := ad.Uint32()
+			f.PacketLimit = &v
+		case unix.TCA_FQ_FLOW_PLIMIT:
+			v := ad.Uint32()
+			f.FlowPacketLimit = &v
+		case unix.TCA_FQ_QUANTUM:
+			v := ad.Uint32()
+			f.Quantum = &v
+		case unix.TCA_FQ_INITIAL_QUANTUM:
+			v := ad.Uint32()
+			f.InitialQuantum = &v
+		case unix.TCA_FQ_RATE_ENABLE:
+			v := ad.Uint32()
+			f.RateEnable = &v
+		case unix.TCA_FQ_FLOW_MAX_RATE:
+			v := ad.Uint32()
+			f.FlowMaxRate = &v
+		case unix.TCA_FQ_BUCKETS_LOG:
+			v := ad.Uint32()
+			f.BucketsLog = &v
+		case unix.TCA_FQ_FLOW_REFILL_DELAY:
+			v := ad.Uint32()
+			f.FlowRefillDelay = &v
+		case unix.TCA_FQ_ORPHAN_MASK:
+			v := ad.Uint32()
+			f.OrphanMask = &v
+		case unix.TCA_FQ_LOW_RATE_THRESHOLD:
+			v := ad.Uint32()
+			f.LowRateThreshold = &v
+		case unix.TCA_FQ_CE_THRESHOLD:
+			v := ad.Uint32()
+			f.CEThreshold = &v
+		case unix.TCA_FQ_TIMER_SLACK:
+			v := ad.Uint32()
+			f.TimerSlack = &v
+		case unix.TCA_FQ_HORIZON:
+			v := ad.Uint32()
+			f.Horizon = &v
+		case unix.TCA_FQ_HORIZON_DROP:
+			v := ad.Uint8()
+			f.HorizonDrop = &v
+		}
+	}
+
+	return ad.Err()
+}
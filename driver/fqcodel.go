@@ -0,0 +1,131 @@
+package driver
+
+import (
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// FqCodel represents a fair queue controlled delay (fq_codel) queueing
+// discipline configuration.
+type FqCodel struct {
+	// Target is the acceptable minimum queue delay in microseconds.
+	Target *uint32
+
+	// Limit is the maximum number of queued packets.
+	Limit *uint32
+
+	// Interval is the width of the delay measurement window in microseconds.
+	Interval *uint32
+
+	// ECN enables marking packets with ECN instead of dropping them.
+	ECN *uint32
+
+	// Flows is the number of flow buckets.
+	Flows *uint32
+
+	// Quantum is the credit per dequeue round in bytes.
+	Quantum *uint32
+
+	// CEThreshold is the sojourn time in microseconds above which packets
+	// are marked with ECN CE.
+	CEThreshold *uint32
+
+	// DropBatchSize is the maximum number of packets dropped at once when
+	// the limit is exceeded.
+	DropBatchSize *uint32
+
+	// MemoryLimit is the maximum memory used by queued packets in bytes.
+	MemoryLimit *uint32
+}
+
+var _ rtnetlink.TcDriver = &FqCodel{}
+
+// New creates a new FqCodel instance.
+func (f *FqCodel) New() rtnetlink.TcDriver {
+	return &FqCodel{}
+}
+
+// Kind returns the fq_codel queueing discipline kind.
+func (f *FqCodel) Kind() string {
+	return "fq_codel"
+}
+
+// Encode encodes the fq_codel configuration into netlink attributes.
+func (f *FqCodel) Encode(ae *netlink.AttributeEncoder) error {
+	if f.Target != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_TARGET, *f.Target)
+	}
+
+	if f.Limit != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_LIMIT, *f.Limit)
+	}
+
+	if f.Interval != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_INTERVAL, *f.Interval)
+	}
+
+	if f.ECN != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_ECN, *f.ECN)
+	}
+
+	if f.Flows != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_FLOWS, *f.Flows)
+	}
+
+	if f.Quantum != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_QUANTUM, *f.Quantum)
+	}
+
+	if f.CEThreshold != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_CE_THRESHOLD, *f.CEThreshold)
+	}
+
+	if f.DropBatchSize != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_DROP_BATCH_SIZE, *f.DropBatchSize)
+	}
+
+	if f.MemoryLimit != nil {
+		ae.Uint32(unix.TCA_FQ_CODEL_MEMORY_LIMIT, *f.MemoryLimit)
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the fq_codel configuration.
+func (f *FqCodel) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_FQ_CODEL_TARGET:
+			v := ad.Uint32()
+			f.Target = &v
+		case unix.TCA_FQ_CODEL_LIMIT:
+			v := ad.Uint32()
+			f.Limit = &v
+		case unix.TCA_FQ_CODEL_INTERVAL:
+			v := ad.Uint32()
+			f.Interval = &v
+		case unix.TCA_FQ_CODEL_ECN:
+			v := ad.Uint32()
+			f.ECN = &v
+		case unix.TCA_FQ_CODEL_FLOWS:
+			v := ad.Uint32()
+			f.Flows = &v
+		case unix.TCA_FQ_CODEL_QUANTUM:
+			v := ad.Uint32()
+			f.Quantum = &v
+		case unix.TCA_FQ_CODEL_CE_THRESHOLD:
+			v := ad.Uint32()
+			f.CEThreshold = &v
+		case unix.TCA_FQ_CODEL_DROP_BATCH_SIZE:
+			v := ad.Uint32()
+			f.DropBatchSize = &v
+		case unix.TCA_FQ_CODEL_MEMORY_LIMIT:
+			v := ad.Uint32()
+			f.MemoryLimit = &v
+		}
+	}
+
+	return ad.Err()
+}
@@ -0,0 +1,272 @@
+package driver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// GeneveDFMode specifies how to handle DF flag in outer IPv4 header
+type GeneveDFMode uint8
+
+const (
+	// GeneveDFUnset indicates DF flag is not set (default)
+	GeneveDFUnset GeneveDFMode = iota
+
+	// GeneveDFSet indicates DF flag is set
+	GeneveDFSet
+
+	// GeneveDFInherit indicates DF flag is inherited from inner IPv4 header
+	GeneveDFInherit
+)
+
+func (g GeneveDFMode) String() string {
+	switch g {
+	case GeneveDFUnset:
+		return "unset"
+	case GeneveDFSet:
+		return "set"
+	case GeneveDFInherit:
+		return "inherit"
+	default:
+		return fmt.Sprintf("unknown GeneveDFMode value (%d)", g)
+	}
+}
+
+// maxGeneveID is the largest Geneve Virtual Network Identifier.
+const maxGeneveID = 1<<24 - 1
+
+// maxGeneveLabel is the largest IPv6 flow label.
+const maxGeneveLabel = 1<<20 - 1
+
+// Geneve implements LinkDriverVerifier for the geneve driver
+type Geneve struct {
+	// Geneve Virtual Network Identifier - required unless CollectMetadata is set
+	ID *uint32
+
+	// Remote IP address of the tunnel (IPv4)
+	Remote net.IP
+
+	// Remote IP address of the tunnel (IPv6)
+	Remote6 net.IP
+
+	// TTL to use in outgoing packets
+	TTL *uint8
+
+	// Inherit TTL from inner packet
+	TTLInherit *bool
+
+	// TOS to use in outgoing packets
+	TOS *uint8
+
+	// Specifies how to handle DF flag in outer IPv4 header
+	DF *GeneveDFMode
+
+	// Flow label to use in outgoing packets (IPv6 only)
+	Label *uint32
+
+	// Destination port for Geneve traffic (default 6081)
+	Port *uint16
+
+	// Enable UDP checksums on transmit for outer IPv4
+	UDPCsum *bool
+
+	// Enable zero UDP checksums on transmit for outer IPv6
+	UDPZeroCsum6Tx *bool
+
+	// Allow zero UDP checksums on receive for outer IPv6
+	UDPZeroCsum6Rx *bool
+
+	// Enable metadata collection mode
+	CollectMetadata *bool
+
+	// Inherit the inner protocol, which allows carrying IP packets without
+	// an ethernet header
+	InnerProtoInherit *bool
+}
+
+var _ rtnetlink.LinkDriverVerifier = &Geneve{}
+
+// New creates a new Geneve instance.
+func (g *Geneve) New() rtnetlink.LinkDriver {
+	return &Geneve{}
+}
+
+// Kind returns the Geneve interface kind.
+func (*Geneve) Kind() string {
+	return "geneve"
+}
+
+// Verify checks that the remote is either IPv4 or IPv6, and that the VNI and
+// label are valid for it.
+func (g *Geneve) Verify(*rtnetlink.LinkMessage) error {
+	if g.Remote != nil && g.Remote6 != nil {
+		return fmt.Errorf("remote and remote6 are mutually exclusive")
+	}
+	if g.Remote != nil && g.Remote.To4() == nil {
+		return fmt.Errorf("remote must be an IPv4 address")
+	}
+	if g.Remote6 != nil && (g.Remote6.To4() != nil || g.Remote6.To16() == nil) {
+		return fmt.Errorf("remote6 must be an IPv6 address")
+	}
+	if g.ID != nil && *g.ID > maxGeneveID {
+		return fmt.Errorf("invalid VNI %d, must be at most %d", *g.ID, maxGeneveID)
+	}
+	if g.Label != nil && *g.Label != 0 {
+		if *g.Label > maxGeneveLabel {
+			return fmt.Errorf("invalid label %d, must be at most %d", *g.Label, maxGeneveLabel)
+		}
+		if g.Remote6 == nil {
+			return fmt.Errorf("label requires an IPv6 remote")
+		}
+	}
+	return nil
+}
+
+// Encode encodes the Geneve configuration into netlink attributes.
+func (g *Geneve) Encode(ae *netlink.AttributeEncoder) error {
+	if g.ID != nil {
+		ae.Uint32(unix.IFLA_GENEVE_ID, *g.ID)
+	}
+	if g.Remote != nil {
+		ip := g.Remote.To4()
+		if ip == nil {
+			return fmt.Errorf("remote must be an IPv4 address")
+		}
+		ae.Bytes(unix.IFLA_GENEVE_REMOTE, ip)
+	}
+	if g.Remote6 != nil {
+		// Check if it's actually an IPv6 address (not an IPv4 address)
+		if g.Remote6.To4() != nil {
+			return fmt.Errorf("remote6 must be an IPv6 address")
+		}
+		ip := g.Remote6.To16()
+		if ip == nil {
+			return fmt.Errorf("remote6 must be an IPv6 address")
+		}
+		ae.Bytes(unix.IFLA_GENEVE_REMOTE6, ip)
+	}
+	if g.TTL != nil {
+		ae.Uint8(unix.IFLA_GENEVE_TTL, *g.TTL)
+	}
+	if g.TTLInherit != nil {
+		var val uint8
+		if *g.TTLInherit {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_GENEVE_TTL_INHERIT, val)
+	}
+	if g.TOS != nil {
+		ae.Uint8(unix.IFLA_GENEVE_TOS, *g.TOS)
+	}
+	if g.DF != nil {
+		ae.Uint8(unix.IFLA_GENEVE_DF, uint8(*g.DF))
+	}
+	if g.Label != nil {
+		// Label must be in network byte order (big-endian)
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, *g.Label)
+		ae.Bytes(unix.IFLA_GENEVE_LABEL, buf)
+	}
+	if g.Port != nil {
+		// Port must be in network byte order (big-endian)
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, *g.Port)
+		ae.Bytes(unix.IFLA_GENEVE_PORT, buf)
+	}
+	if g.UDPCsum != nil {
+		var val uint8
+		if *g.UDPCsum {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_GENEVE_UDP_CSUM, val)
+	}
+	if g.UDPZeroCsum6Tx != nil {
+		var val uint8
+		if *g.UDPZeroCsum6Tx {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_GENEVE_UDP_ZERO_CSUM6_TX, val)
+	}
+	if g.UDPZeroCsum6Rx != nil {
+		var val uint8
+		if *g.UDPZeroCsum6Rx {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_GENEVE_UDP_ZERO_CSUM6_RX, val)
+	}
+	if g.CollectMetadata != nil {
+		if *g.CollectMetadata {
+			ae.Flag(unix.IFLA_GENEVE_COLLECT_METADATA, true)
+		}
+	}
+	if g.InnerProtoInherit != nil {
+		if *g.InnerProtoInherit {
+			ae.Flag(unix.IFLA_GENEVE_INNER_PROTO_INHERIT, true)
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the Geneve configuration.
+func (g *Geneve) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_GENEVE_ID:
+			val := ad.Uint32()
+			g.ID = &val
+		case unix.IFLA_GENEVE_REMOTE:
+			g.Remote = net.IP(ad.Bytes())
+		case unix.IFLA_GENEVE_REMOTE6:
+			g.Remote6 = net.IP(ad.Bytes())
+		case unix.IFLA_GENEVE_TTL:
+			val := ad.Uint8()
+			g.TTL = &val
+		case unix.IFLA_GENEVE_TTL_INHERIT:
+			val := ad.Uint8() != 0
+			g.TTLInherit = &val
+		case unix.IFLA_GENEVE_TOS:
+			val := ad.Uint8()
+			g.TOS = &val
+		case unix.IFLA_GENEVE_DF:
+			val := GeneveDFMode(ad.Uint8())
+			g.DF = &val
+		case unix.IFLA_GENEVE_LABEL:
+			// Label is in network byte order (big-endian)
+			buf := ad.Bytes()
+			if len(buf) >= 4 {
+				val := binary.BigEndian.Uint32(buf)
+				g.Label = &val
+			}
+		case unix.IFLA_GENEVE_PORT:
+			// Port is in network byte order (big-endian)
+			buf := ad.Bytes()
+			if len(buf) >= 2 {
+				val := binary.BigEndian.Uint16(buf)
+				g.Port = &val
+			}
+		case unix.IFLA_GENEVE_UDP_CSUM:
+			val := ad.Uint8() != 0
+			g.UDPCsum = &val
+		case unix.IFLA_GENEVE_UDP_ZERO_CSUM6_TX:
+			val := ad.Uint8() != 0
+			g.UDPZeroCsum6Tx = &val
+		case unix.IFLA_GENEVE_UDP_ZERO_CSUM6_RX:
+			val := ad.Uint8() != 0
+			g.UDPZeroCsum6Rx = &val
+		case unix.IFLA_GENEVE_COLLECT_METADATA:
+			val := true
+			g.CollectMetadata = &val
+		case unix.IFLA_GENEVE_INNER_PROTO_INHERIT:
+			val := true
+			g.InnerProtoInherit = &val
+		}
+	}
+	return nil
+}
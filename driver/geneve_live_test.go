@@ -0,0 +1,118 @@
+//go:build integration
+// +build integration
+
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/mdlayher/netlink"
+)
+
+func geneveT(d rtnetlink.LinkDriver) *Geneve {
+	g := d.(*Geneve)
+	return &Geneve{
+		ID:      g.ID,
+		Remote:  g.Remote,
+		Remote6: g.Remote6,
+		TTL:     g.TTL,
+		TOS:     g.TOS,
+		Label:   g.Label,
+		Port:    g.Port,
+	}
+}
+
+func TestGeneve(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	var (
+		vni100    uint32 = 100
+		vni200    uint32 = 200
+		port6081  uint16 = 6081 // Kernel default Geneve port
+		port7000  uint16 = 7000
+		ttl0      uint8  = 0
+		ttl64     uint8  = 64
+		tos0      uint8  = 0
+		label0    uint32 = 0
+		label1234 uint32 = 0x1234
+	)
+
+	tests := []struct {
+		name     string
+		linkName string
+		driver   *Geneve
+		expected *Geneve
+	}{
+		{
+			name:     "geneve with IPv4 remote",
+			linkName: "geneve0",
+			driver: &Geneve{
+				ID:     &vni100,
+				Remote: net.ParseIP("192.0.2.1"),
+			},
+			expected: &Geneve{
+				ID:     &vni100,
+				Remote: net.ParseIP("192.0.2.1").To4(),
+				TTL:    &ttl0,     // Kernel sets default TTL to 0
+				TOS:    &tos0,     // Kernel sets default TOS to 0
+				Label:  &label0,   // Kernel sets default label to 0
+				Port:   &port6081, // Kernel sets default port (IANA assigned)
+			},
+		},
+		{
+			name:     "geneve with IPv6 remote and settings",
+			linkName: "geneve1",
+			driver: &Geneve{
+				ID:      &vni200,
+				Remote6: net.ParseIP("2001:db8::1"),
+				TTL:     &ttl64,
+				Label:   &label1234,
+				Port:    &port7000,
+			},
+			expected: &Geneve{
+				ID:      &vni200,
+				Remote6: net.ParseIP("2001:db8::1"),
+				TTL:     &ttl64,
+				TOS:     &tos0,
+				Label:   &label1234,
+				Port:    &port7000,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ifIndex := uint32(2600 + i)
+
+			if err := setupInterface(conn, tt.linkName, ifIndex, 0, tt.driver); err != nil {
+				t.Fatalf("failed to setup geneve interface: %v", err)
+			}
+			defer conn.Link.Delete(ifIndex)
+
+			msg, err := getInterface(conn, ifIndex)
+			if err != nil {
+				t.Fatalf("failed to get geneve interface: %v", err)
+			}
+
+			if msg.Attributes == nil || msg.Attributes.Info == nil || msg.Attributes.Info.Data == nil {
+				t.Fatal("interface missing link info data")
+			}
+
+			if _, ok := msg.Attributes.Info.Data.(*Geneve); !ok {
+				t.Fatalf("expected *Geneve, got %T", msg.Attributes.Info.Data)
+			}
+
+			if diff := cmp.Diff(tt.expected, geneveT(msg.Attributes.Info.Data)); diff != "" {
+				t.Fatalf("unexpected geneve config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/mdlayher/netlink"
+)
+
+func TestGeneveEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		geneve *Geneve
+	}{
+		{
+			name: "minimal configuration",
+			geneve: &Geneve{
+				ID:     ptr(uint32(100)),
+				Remote: net.ParseIP("192.0.2.1").To4(),
+			},
+		},
+		{
+			name: "full IPv4 configuration",
+			geneve: &Geneve{
+				ID:         ptr(uint32(100)),
+				Remote:     net.ParseIP("192.0.2.1").To4(),
+				TTL:        ptr(uint8(64)),
+				TTLInherit: ptr(false),
+				TOS:        ptr(uint8(1)),
+				DF:         ptr(GeneveDFInherit),
+				Port:       ptr(uint16(6081)),
+				UDPCsum:    ptr(true),
+			},
+		},
+		{
+			name: "IPv6 configuration",
+			geneve: &Geneve{
+				ID:             ptr(uint32(200)),
+				Remote6:        net.ParseIP("2001:db8::1"),
+				Label:          ptr(uint32(0x12345)),
+				UDPZeroCsum6Tx: ptr(true),
+				UDPZeroCsum6Rx: ptr(false),
+			},
+		},
+		{
+			name: "metadata",
+			geneve: &Geneve{
+				CollectMetadata:   ptr(true),
+				InnerProtoInherit: ptr(true),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.geneve.Verify(&rtnetlink.LinkMessage{}); err != nil {
+				t.Fatalf("failed to verify: %v", err)
+			}
+
+			// Encode
+			ae := netlink.NewAttributeEncoder()
+			if err := tt.geneve.Encode(ae); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			encoded, err := ae.Encode()
+			if err != nil {
+				t.Fatalf("failed to encode attributes: %v", err)
+			}
+
+			// Decode
+			decoded := &Geneve{}
+			ad, err := netlink.NewAttributeDecoder(encoded)
+			if err != nil {
+				t.Fatalf("failed to create attribute decoder: %v", err)
+			}
+
+			if err := decoded.Decode(ad); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.geneve, decoded); diff != "" {
+				t.Fatalf("unexpected geneve config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGeneveDFModeString(t *testing.T) {
+	tests := []struct {
+		mode GeneveDFMode
+		want string
+	}{
+		{GeneveDFUnset, "unset"},
+		{GeneveDFSet, "set"},
+		{GeneveDFInherit, "inherit"},
+		{GeneveDFMode(99), "unknown GeneveDFMode value (99)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.mode.String(); got != tt.want {
+				t.Errorf("GeneveDFMode.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneveVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		geneve  *Geneve
+		wantErr string
+	}{
+		{
+			name: "IPv4 and IPv6 remote",
+			geneve: &Geneve{
+				Remote:  net.ParseIP("192.0.2.1"),
+				Remote6: net.ParseIP("2001:db8::1"),
+			},
+			wantErr: "remote and remote6 are mutually exclusive",
+		},
+		{
+			name: "invalid IPv4 remote",
+			geneve: &Geneve{
+				Remote: net.ParseIP("2001:db8::1"), // IPv6 address for IPv4 field
+			},
+			wantErr: "remote must be an IPv4 address",
+		},
+		{
+			name: "invalid IPv6 remote",
+			geneve: &Geneve{
+				Remote6: net.ParseIP("192.0.2.1"), // IPv4 address for IPv6 field
+			},
+			wantErr: "remote6 must be an IPv6 address",
+		},
+		{
+			name: "invalid VNI",
+			geneve: &Geneve{
+				ID: ptr(uint32(1 << 24)),
+			},
+			wantErr: "invalid VNI 16777216, must be at most 16777215",
+		},
+		{
+			name: "invalid label",
+			geneve: &Geneve{
+				Remote6: net.ParseIP("2001:db8::1"),
+				Label:   ptr(uint32(1 << 20)),
+			},
+			wantErr: "invalid label 1048576, must be at most 1048575",
+		},
+		{
+			name: "label with IPv4 remote",
+			geneve: &Geneve{
+				Remote: net.ParseIP("192.0.2.1"),
+				Label:  ptr(uint32(1)),
+			},
+			wantErr: "label requires an IPv6 remote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.geneve.Verify(&rtnetlink.LinkMessage{})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestGeneveKind(t *testing.T) {
+	g := &Geneve{}
+	if kind := g.Kind(); kind != "geneve" {
+		t.Errorf("expected kind %q, got %q", "geneve", kind)
+	}
+}
+
+func TestGeneveNew(t *testing.T) {
+	g := &Geneve{}
+	newG := g.New()
+	if _, ok := newG.(*Geneve); !ok {
+		t.Errorf("expected *Geneve, got %T", newG)
+	}
+}
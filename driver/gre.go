@@ -0,0 +1,571 @@
+package driver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// GreFlag represents the flags of the GRE header used by a tunnel.
+type GreFlag uint16
+
+// GRE flags.
+const (
+	GreFlagCsum    GreFlag = 0x8000
+	GreFlagRouting GreFlag = 0x4000
+	GreFlagKey     GreFlag = 0x2000
+	GreFlagSeq     GreFlag = 0x1000
+)
+
+// TunnelEncapType represents the type of the UDP encapsulation of a tunnel.
+type TunnelEncapType uint16
+
+// Tunnel encapsulation types.
+const (
+	TunnelEncapNone TunnelEncapType = unix.TUNNEL_ENCAP_NONE
+	TunnelEncapFou  TunnelEncapType = unix.TUNNEL_ENCAP_FOU
+	TunnelEncapGue  TunnelEncapType = unix.TUNNEL_ENCAP_GUE
+	TunnelEncapMpls TunnelEncapType = unix.TUNNEL_ENCAP_MPLS
+)
+
+// String returns a string representation of the TunnelEncapType.
+func (t TunnelEncapType) String() string {
+	switch t {
+	case TunnelEncapNone:
+		return "none"
+	case TunnelEncapFou:
+		return "fou"
+	case TunnelEncapGue:
+		return "gue"
+	case TunnelEncapMpls:
+		return "mpls"
+	default:
+		return fmt.Sprintf("unknown TunnelEncapType value (%d)", t)
+	}
+}
+
+// TunnelEncapFlag represents the flags of the UDP encapsulation of a tunnel.
+type TunnelEncapFlag uint16
+
+// Tunnel encapsulation flags.
+const (
+	TunnelEncapFlagCsum    TunnelEncapFlag = unix.TUNNEL_ENCAP_FLAG_CSUM
+	TunnelEncapFlagCsum6   TunnelEncapFlag = unix.TUNNEL_ENCAP_FLAG_CSUM6
+	TunnelEncapFlagRemCsum TunnelEncapFlag = unix.TUNNEL_ENCAP_FLAG_REMCSUM
+)
+
+// TunnelEncap specifies the UDP encapsulation (FOU or GUE) of a tunnel.
+type TunnelEncap struct {
+	// Type specifies the encapsulation type.
+	Type TunnelEncapType
+
+	// Flags specifies the encapsulation flags.
+	Flags TunnelEncapFlag
+
+	// SourcePort specifies the UDP source port, 0 lets the kernel choose one.
+	SourcePort uint16
+
+	// DestinationPort specifies the UDP destination port.
+	DestinationPort uint16
+}
+
+// Ip6TunnelFlag represents the flags of an IPv6 tunnel.
+type Ip6TunnelFlag uint32
+
+// IPv6 tunnel flags.
+const (
+	Ip6TunnelFlagIgnEncapLimit    Ip6TunnelFlag = unix.IP6_TNL_F_IGN_ENCAP_LIMIT
+	Ip6TunnelFlagUseOrigTclass    Ip6TunnelFlag = unix.IP6_TNL_F_USE_ORIG_TCLASS
+	Ip6TunnelFlagUseOrigFlowlabel Ip6TunnelFlag = unix.IP6_TNL_F_USE_ORIG_FLOWLABEL
+	Ip6TunnelFlagMip6Dev          Ip6TunnelFlag = unix.IP6_TNL_F_MIP6_DEV
+	Ip6TunnelFlagRcvDscpCopy      Ip6TunnelFlag = unix.IP6_TNL_F_RCV_DSCP_COPY
+	Ip6TunnelFlagUseOrigFwmark    Ip6TunnelFlag = unix.IP6_TNL_F_USE_ORIG_FWMARK
+	Ip6TunnelFlagAllowLocalRemote Ip6TunnelFlag = unix.IP6_TNL_F_ALLOW_LOCAL_REMOTE
+)
+
+// ErspanDir represents the direction of the traffic mirrored by an ERSPAN
+// version 2 tunnel.
+type ErspanDir uint8
+
+// ERSPAN directions.
+const (
+	ErspanDirIngress ErspanDir = 0x0
+	ErspanDirEgress  ErspanDir = 0x1
+)
+
+// String returns a string representation of the ErspanDir.
+func (d ErspanDir) String() string {
+	switch d {
+	case ErspanDirIngress:
+		return "ingress"
+	case ErspanDirEgress:
+		return "egress"
+	default:
+		return fmt.Sprintf("unknown ErspanDir value (%d)", d)
+	}
+}
+
+// Gre represents a GRE tunnel device configuration. Gretap, Ip6Gre, Ip6Gretap,
+// Erspan and Ip6Erspan share it for the other GRE kinds, of which the ip6
+// kinds take IPv6 Local and Remote addresses.
+type Gre struct {
+	// Link specifies the physical device to use for tunnel endpoint
+	// communication.
+	Link *uint32
+
+	// IFlags specifies the GRE flags expected on received packets. A key is
+	// only used if the flags include GreFlagKey.
+	IFlags *GreFlag
+
+	// OFlags specifies the GRE flags set on transmitted packets. A key is
+	// only used if the flags include GreFlagKey.
+	OFlags *GreFlag
+
+	// IKey specifies the key expected on received packets.
+	IKey *uint32
+
+	// OKey specifies the key set on transmitted packets.
+	OKey *uint32
+
+	// Local specifies the source address of the tunnel.
+	Local net.IP
+
+	// Remote specifies the destination address of the tunnel.
+	Remote net.IP
+
+	// TTL specifies the TTL (hop limit for the ip6 kinds) of transmitted
+	// packets, 0 inherits it from the inner packet.
+	TTL *uint8
+
+	// TOS specifies the TOS of transmitted packets (gre and gretap only).
+	TOS *uint8
+
+	// PMTUDisc enables path MTU discovery (gre and gretap only).
+	PMTUDisc *bool
+
+	// IgnoreDF ignores the DF flag of inner packets (gre and gretap only).
+	IgnoreDF *bool
+
+	// EncapLimit specifies the tunnel encapsulation limit (ip6 kinds only).
+	EncapLimit *uint8
+
+	// FlowInfo specifies the flow label and traffic class of transmitted
+	// packets (ip6 kinds only).
+	FlowInfo *uint32
+
+	// Flags specifies the IPv6 tunnel flags (ip6 kinds only).
+	Flags *Ip6TunnelFlag
+
+	// FwMark specifies the firewall mark of transmitted packets.
+	FwMark *uint32
+
+	// Encap specifies the UDP encapsulation of transmitted packets.
+	Encap *TunnelEncap
+
+	// CollectMetadata enables metadata collection mode, in which the
+	// tunnel parameters are taken from the metadata of each packet.
+	CollectMetadata *bool
+
+	// ErspanIndex specifies the ERSPAN version 1 index.
+	ErspanIndex *uint32
+
+	// ErspanVer specifies the ERSPAN version (1 or 2).
+	ErspanVer *uint8
+
+	// ErspanDir specifies the ERSPAN version 2 direction.
+	ErspanDir *ErspanDir
+
+	// ErspanHwid specifies the ERSPAN version 2 hardware ID.
+	ErspanHwid *uint16
+}
+
+var _ rtnetlink.LinkDriver = &Gre{}
+
+// New creates a new Gre instance.
+func (g *Gre) New() rtnetlink.LinkDriver {
+	return &Gre{}
+}
+
+// Kind returns the GRE interface kind.
+func (*Gre) Kind() string {
+	return "gre"
+}
+
+// Encode encodes the GRE configuration into netlink attributes.
+func (g *Gre) Encode(ae *netlink.AttributeEncoder) error {
+	return g.encode(ae, false)
+}
+
+// Decode decodes netlink attributes into the GRE configuration.
+func (g *Gre) Decode(ad *netlink.AttributeDecoder) error {
+	return g.decode(ad)
+}
+
+// encode encodes the configuration with IPv4 or, if ip6 is set, IPv6
+// addresses.
+func (g *Gre) encode(ae *netlink.AttributeEncoder, ip6 bool) error {
+	if g.Link != nil {
+		ae.Uint32(unix.IFLA_GRE_LINK, *g.Link)
+	}
+	if g.IFlags != nil {
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, uint16(*g.IFlags))
+		ae.Bytes(unix.IFLA_GRE_IFLAGS, buf)
+	}
+	if g.OFlags != nil {
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, uint16(*g.OFlags))
+		ae.Bytes(unix.IFLA_GRE_OFLAGS, buf)
+	}
+	if g.IKey != nil {
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, *g.IKey)
+		ae.Bytes(unix.IFLA_GRE_IKEY, buf)
+	}
+	if g.OKey != nil {
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, *g.OKey)
+		ae.Bytes(unix.IFLA_GRE_OKEY, buf)
+	}
+	if g.Local != nil {
+		ip, err := tunnelIP("local", g.Local, ip6)
+		if err != nil {
+			return err
+		}
+		ae.Bytes(unix.IFLA_GRE_LOCAL, ip)
+	}
+	if g.Remote != nil {
+		ip, err := tunnelIP("remote", g.Remote, ip6)
+		if err != nil {
+			return err
+		}
+		ae.Bytes(unix.IFLA_GRE_REMOTE, ip)
+	}
+	if g.TTL != nil {
+		ae.Uint8(unix.IFLA_GRE_TTL, *g.TTL)
+	}
+	if g.TOS != nil {
+		ae.Uint8(unix.IFLA_GRE_TOS, *g.TOS)
+	}
+	if g.PMTUDisc != nil {
+		var val uint8
+		if *g.PMTUDisc {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_GRE_PMTUDISC, val)
+	}
+	if g.IgnoreDF != nil {
+		var val uint8
+		if *g.IgnoreDF {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_GRE_IGNORE_DF, val)
+	}
+	if g.EncapLimit != nil {
+		ae.Uint8(unix.IFLA_GRE_ENCAP_LIMIT, *g.EncapLimit)
+	}
+	if g.FlowInfo != nil {
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, *g.FlowInfo)
+		ae.Bytes(unix.IFLA_GRE_FLOWINFO, buf)
+	}
+	if g.Flags != nil {
+		ae.Uint32(unix.IFLA_GRE_FLAGS, uint32(*g.Flags))
+	}
+	if g.FwMark != nil {
+		ae.Uint32(unix.IFLA_GRE_FWMARK, *g.FwMark)
+	}
+	if g.Encap != nil {
+		g.Encap.encode(ae, unix.IFLA_GRE_ENCAP_TYPE, unix.IFLA_GRE_ENCAP_FLAGS,
+			unix.IFLA_GRE_ENCAP_SPORT, unix.IFLA_GRE_ENCAP_DPORT)
+	}
+	if g.CollectMetadata != nil {
+		if *g.CollectMetadata {
+			ae.Flag(unix.IFLA_GRE_COLLECT_METADATA, true)
+		}
+	}
+	if g.ErspanIndex != nil {
+		ae.Uint32(unix.IFLA_GRE_ERSPAN_INDEX, *g.ErspanIndex)
+	}
+	if g.ErspanVer != nil {
+		ae.Uint8(unix.IFLA_GRE_ERSPAN_VER, *g.ErspanVer)
+	}
+	if g.ErspanDir != nil {
+		ae.Uint8(unix.IFLA_GRE_ERSPAN_DIR, uint8(*g.ErspanDir))
+	}
+	if g.ErspanHwid != nil {
+		ae.Uint16(unix.IFLA_GRE_ERSPAN_HWID, *g.ErspanHwid)
+	}
+
+	return nil
+}
+
+// decode decodes the configuration, whose addresses are IPv4 or IPv6 as
+// reported by the kernel.
+func (g *Gre) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_GRE_LINK:
+			val := ad.Uint32()
+			g.Link = &val
+		case unix.IFLA_GRE_IFLAGS:
+			if buf := ad.Bytes(); len(buf) >= 2 {
+				val := GreFlag(binary.BigEndian.Uint16(buf))
+				g.IFlags = &val
+			}
+		case unix.IFLA_GRE_OFLAGS:
+			if buf := ad.Bytes(); len(buf) >= 2 {
+				val := GreFlag(binary.BigEndian.Uint16(buf))
+				g.OFlags = &val
+			}
+		case unix.IFLA_GRE_IKEY:
+			if buf := ad.Bytes(); len(buf) >= 4 {
+				val := binary.BigEndian.Uint32(buf)
+				g.IKey = &val
+			}
+		case unix.IFLA_GRE_OKEY:
+			if buf := ad.Bytes(); len(buf) >= 4 {
+				val := binary.BigEndian.Uint32(buf)
+				g.OKey = &val
+			}
+		case unix.IFLA_GRE_LOCAL:
+			g.Local = net.IP(ad.Bytes())
+		case unix.IFLA_GRE_REMOTE:
+			g.Remote = net.IP(ad.Bytes())
+		case unix.IFLA_GRE_TTL:
+			val := ad.Uint8()
+			g.TTL = &val
+		case unix.IFLA_GRE_TOS:
+			val := ad.Uint8()
+			g.TOS = &val
+		case unix.IFLA_GRE_PMTUDISC:
+			val := ad.Uint8() != 0
+			g.PMTUDisc = &val
+		case unix.IFLA_GRE_IGNORE_DF:
+			val := ad.Uint8() != 0
+			g.IgnoreDF = &val
+		case unix.IFLA_GRE_ENCAP_LIMIT:
+			val := ad.Uint8()
+			g.EncapLimit = &val
+		case unix.IFLA_GRE_FLOWINFO:
+			if buf := ad.Bytes(); len(buf) >= 4 {
+				val := binary.BigEndian.Uint32(buf)
+				g.FlowInfo = &val
+			}
+		case unix.IFLA_GRE_FLAGS:
+			val := Ip6TunnelFlag(ad.Uint32())
+			g.Flags = &val
+		case unix.IFLA_GRE_FWMARK:
+			val := ad.Uint32()
+			g.FwMark = &val
+		case unix.IFLA_GRE_ENCAP_TYPE, unix.IFLA_GRE_ENCAP_FLAGS,
+			unix.IFLA_GRE_ENCAP_SPORT, unix.IFLA_GRE_ENCAP_DPORT:
+			if g.Encap == nil {
+				g.Encap = &TunnelEncap{}
+			}
+			g.Encap.decode(ad, unix.IFLA_GRE_ENCAP_TYPE, unix.IFLA_GRE_ENCAP_FLAGS,
+				unix.IFLA_GRE_ENCAP_SPORT, unix.IFLA_GRE_ENCAP_DPORT)
+		case unix.IFLA_GRE_COLLECT_METADATA:
+			val := true
+			g.CollectMetadata = &val
+		case unix.IFLA_GRE_ERSPAN_INDEX:
+			val := ad.Uint32()
+			g.ErspanIndex = &val
+		case unix.IFLA_GRE_ERSPAN_VER:
+			val := ad.Uint8()
+			g.ErspanVer = &val
+		case unix.IFLA_GRE_ERSPAN_DIR:
+			val := ErspanDir(ad.Uint8())
+			g.ErspanDir = &val
+		case unix.IFLA_GRE_ERSPAN_HWID:
+			val := ad.Uint16()
+			g.ErspanHwid = &val
+		}
+	}
+
+	return ad.Err()
+}
+
+// encode encodes the encapsulation with the given attribute types, which
+// differ between the tunnel kinds.
+func (e *TunnelEncap) encode(ae *netlink.AttributeEncoder, typ, flags, sport, dport uint16) {
+	ae.Uint16(typ, uint16(e.Type))
+	ae.Uint16(flags, uint16(e.Flags))
+
+	// Ports are in network byte order.
+	buf := make([]byte, 2)
+	binary.BigEndian.PutUint16(buf, e.SourcePort)
+	ae.Bytes(sport, buf)
+
+	buf = make([]byte, 2)
+	binary.BigEndian.PutUint16(buf, e.DestinationPort)
+	ae.Bytes(dport, buf)
+}
+
+// decode decodes the encapsulation attribute at the current position of ad,
+// given the attribute types of the tunnel kind.
+func (e *TunnelEncap) decode(ad *netlink.AttributeDecoder, typ, flags, sport, dport uint16) {
+	switch ad.Type() {
+	case typ:
+		e.Type = TunnelEncapType(ad.Uint16())
+	case flags:
+		e.Flags = TunnelEncapFlag(ad.Uint16())
+	case sport:
+		if buf := ad.Bytes(); len(buf) >= 2 {
+			e.SourcePort = binary.BigEndian.Uint16(buf)
+		}
+	case dport:
+		if buf := ad.Bytes(); len(buf) >= 2 {
+			e.DestinationPort = binary.BigEndian.Uint16(buf)
+		}
+	}
+}
+
+// tunnelIP returns the tunnel endpoint address ip of the field name as an
+// IPv4 or, if ip6 is set, IPv6 address.
+func tunnelIP(name string, ip net.IP, ip6 bool) (net.IP, error) {
+	if ip6 {
+		if ip.To4() != nil || ip.To16() == nil {
+			return nil, fmt.Errorf("%s must be an IPv6 address", name)
+		}
+		return ip.To16(), nil
+	}
+
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return nil, fmt.Errorf("%s must be an IPv4 address", name)
+	}
+	return ip4, nil
+}
+
+// Gretap represents a GRE tunnel device carrying ethernet frames.
+type Gretap Gre
+
+var _ rtnetlink.LinkDriver = &Gretap{}
+
+// New creates a new Gretap instance.
+func (g *Gretap) New() rtnetlink.LinkDriver {
+	return &Gretap{}
+}
+
+// Kind returns the GRETAP interface kind.
+func (*Gretap) Kind() string {
+	return "gretap"
+}
+
+// Encode encodes the GRETAP configuration into netlink attributes.
+func (g *Gretap) Encode(ae *netlink.AttributeEncoder) error {
+	return (*Gre)(g).encode(ae, false)
+}
+
+// Decode decodes netlink attributes into the GRETAP configuration.
+func (g *Gretap) Decode(ad *netlink.AttributeDecoder) error {
+	return (*Gre)(g).decode(ad)
+}
+
+// Ip6Gre represents a GRE over IPv6 tunnel device.
+type Ip6Gre Gre
+
+var _ rtnetlink.LinkDriver = &Ip6Gre{}
+
+// New creates a new Ip6Gre instance.
+func (g *Ip6Gre) New() rtnetlink.LinkDriver {
+	return &Ip6Gre{}
+}
+
+// Kind returns the IP6GRE interface kind.
+func (*Ip6Gre) Kind() string {
+	return "ip6gre"
+}
+
+// Encode encodes the IP6GRE configuration into netlink attributes.
+func (g *Ip6Gre) Encode(ae *netlink.AttributeEncoder) error {
+	return (*Gre)(g).encode(ae, true)
+}
+
+// Decode decodes netlink attributes into the IP6GRE configuration.
+func (g *Ip6Gre) Decode(ad *netlink.AttributeDecoder) error {
+	return (*Gre)(g).decode(ad)
+}
+
+// Ip6Gretap represents a GRE over IPv6 tunnel device carrying ethernet
+// frames.
+type Ip6Gretap Gre
+
+var _ rtnetlink.LinkDriver = &Ip6Gretap{}
+
+// New creates a new Ip6Gretap instance.
+func (g *Ip6Gretap) New() rtnetlink.LinkDriver {
+	return &Ip6Gretap{}
+}
+
+// Kind returns the IP6GRETAP interface kind.
+func (*Ip6Gretap) Kind() string {
+	return "ip6gretap"
+}
+
+// Encode encodes the IP6GRETAP configuration into netlink attributes.
+func (g *Ip6Gretap) Encode(ae *netlink.AttributeEncoder) error {
+	return (*Gre)(g).encode(ae, true)
+}
+
+// Decode decodes netlink attributes into the IP6GRETAP configuration.
+func (g *Ip6Gretap) Decode(ad *netlink.AttributeDecoder) error {
+	return (*Gre)(g).decode(ad)
+}
+
+// Erspan represents an ERSPAN tunnel device, which mirrors ethernet frames
+// over GRE.
+type Erspan Gre
+
+var _ rtnetlink.LinkDriver = &Erspan{}
+
+// New creates a new Erspan instance.
+func (g *Erspan) New() rtnetlink.LinkDriver {
+	return &Erspan{}
+}
+
+// Kind returns the ERSPAN interface kind.
+func (*Erspan) Kind() string {
+	return "erspan"
+}
+
+// Encode encodes the ERSPAN configuration into netlink attributes.
+func (g *Erspan) Encode(ae *netlink.AttributeEncoder) error {
+	return (*Gre)(g).encode(ae, false)
+}
+
+// Decode decodes netlink attributes into the ERSPAN configuration.
+func (g *Erspan) Decode(ad *netlink.AttributeDecoder) error {
+	return (*Gre)(g).decode(ad)
+}
+
+// Ip6Erspan represents an ERSPAN over IPv6 tunnel device.
+type Ip6Erspan Gre
+
+var _ rtnetlink.LinkDriver = &Ip6Erspan{}
+
+// New creates a new Ip6Erspan instance.
+func (g *Ip6Erspan) New() rtnetlink.LinkDriver {
+	return &Ip6Erspan{}
+}
+
+// Kind returns the IP6ERSPAN interface kind.
+func (*Ip6Erspan) Kind() string {
+	return "ip6erspan"
+}
+
+// Encode encodes the IP6ERSPAN configuration into netlink attributes.
+func (g *Ip6Erspan) Encode(ae *netlink.AttributeEncoder) error {
+	return (*Gre)(g).encode(ae, true)
+}
+
+// Decode decodes netlink attributes into the IP6ERSPAN configuration.
+func (g *Ip6Erspan) Decode(ad *netlink.AttributeDecoder) error {
+	return (*Gre)(g).decode(ad)
+}
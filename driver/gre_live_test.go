@@ -0,0 +1,131 @@
+//go:build integration
+// +build integration
+
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/mdlayher/netlink"
+)
+
+// greT returns the configured fields of the Gre shared by all GRE kinds.
+func greT(g *Gre) *Gre {
+	return &Gre{
+		IFlags: g.IFlags,
+		OFlags: g.OFlags,
+		IKey:   g.IKey,
+		OKey:   g.OKey,
+		Local:  g.Local,
+		Remote: g.Remote,
+		TTL:    g.TTL,
+	}
+}
+
+func TestGre(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	var (
+		key100   uint32 = 100
+		key200   uint32 = 200
+		ttl64    uint8  = 64
+		keyFlags        = GreFlagKey
+	)
+
+	ipv4 := Gre{
+		IFlags: &keyFlags,
+		OFlags: &keyFlags,
+		IKey:   &key100,
+		OKey:   &key200,
+		Local:  net.ParseIP("192.0.2.1").To4(),
+		Remote: net.ParseIP("198.51.100.1").To4(),
+		TTL:    &ttl64,
+	}
+	ipv6 := Gre{
+		IFlags: &keyFlags,
+		OFlags: &keyFlags,
+		IKey:   &key100,
+		OKey:   &key200,
+		Local:  net.ParseIP("2001:db8::1"),
+		Remote: net.ParseIP("2001:db8::2"),
+		TTL:    &ttl64,
+	}
+
+	tests := []struct {
+		name     string
+		linkName string
+		driver   rtnetlink.LinkDriver
+		expected *Gre
+	}{
+		{
+			name:     "gre",
+			linkName: "gre1",
+			driver:   func() *Gre { g := ipv4; return &g }(),
+			expected: &ipv4,
+		},
+		{
+			name:     "gretap",
+			linkName: "gretap1",
+			driver:   func() *Gretap { g := Gretap(ipv4); return &g }(),
+			expected: &ipv4,
+		},
+		{
+			name:     "ip6gre",
+			linkName: "ip6gre1",
+			driver:   func() *Ip6Gre { g := Ip6Gre(ipv6); return &g }(),
+			expected: &ipv6,
+		},
+		{
+			name:     "ip6gretap",
+			linkName: "ip6gretap1",
+			driver:   func() *Ip6Gretap { g := Ip6Gretap(ipv6); return &g }(),
+			expected: &ipv6,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ifIndex := uint32(2400 + i)
+
+			if err := setupInterface(conn, tt.linkName, ifIndex, 0, tt.driver); err != nil {
+				t.Fatalf("failed to setup %s interface: %v", tt.name, err)
+			}
+			defer conn.Link.Delete(ifIndex)
+
+			msg, err := getInterface(conn, ifIndex)
+			if err != nil {
+				t.Fatalf("failed to get %s interface: %v", tt.name, err)
+			}
+
+			if msg.Attributes == nil || msg.Attributes.Info == nil || msg.Attributes.Info.Data == nil {
+				t.Fatal("interface missing link info data")
+			}
+
+			var gre *Gre
+			switch data := msg.Attributes.Info.Data.(type) {
+			case *Gre:
+				gre = data
+			case *Gretap:
+				gre = (*Gre)(data)
+			case *Ip6Gre:
+				gre = (*Gre)(data)
+			case *Ip6Gretap:
+				gre = (*Gre)(data)
+			default:
+				t.Fatalf("unexpected link info data %T", data)
+			}
+
+			if diff := cmp.Diff(tt.expected, greT(gre)); diff != "" {
+				t.Fatalf("unexpected %s config (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package driver
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestGreEncodeDecode(t *testing.T) {
+	var (
+		link2     uint32 = 2
+		key100    uint32 = 100
+		key200    uint32 = 200
+		ttl64     uint8  = 64
+		tos0      uint8  = 0
+		limit4    uint8  = 4
+		flowinfo  uint32 = 0x12345
+		fwmark7   uint32 = 7
+		index9    uint32 = 9
+		ver2      uint8  = 2
+		hwid3     uint16 = 3
+		keyFlags         = GreFlagKey | GreFlagCsum
+		ip6Flags         = Ip6TunnelFlagIgnEncapLimit | Ip6TunnelFlagUseOrigTclass
+		dirEgress        = ErspanDirEgress
+		trueVal          = true
+		falseVal         = false
+	)
+
+	tests := []struct {
+		name    string
+		in      rtnetlink.LinkDriver
+		decoded rtnetlink.LinkDriver
+	}{
+		{
+			name: "gre",
+			in: &Gre{
+				Link:     &link2,
+				IFlags:   &keyFlags,
+				OFlags:   &keyFlags,
+				IKey:     &key100,
+				OKey:     &key200,
+				Local:    net.ParseIP("192.0.2.1"),
+				Remote:   net.ParseIP("198.51.100.1"),
+				TTL:      &ttl64,
+				TOS:      &tos0,
+				PMTUDisc: &trueVal,
+				IgnoreDF: &falseVal,
+				FwMark:   &fwmark7,
+				Encap: &TunnelEncap{
+					Type:            TunnelEncapFou,
+					Flags:           TunnelEncapFlagCsum,
+					DestinationPort: 5555,
+				},
+			},
+			decoded: &Gre{},
+		},
+		{
+			name: "gretap collect metadata",
+			in: &Gretap{
+				CollectMetadata: &trueVal,
+			},
+			decoded: &Gretap{},
+		},
+		{
+			name: "ip6gre",
+			in: &Ip6Gre{
+				Local:      net.ParseIP("2001:db8::1"),
+				Remote:     net.ParseIP("2001:db8::2"),
+				TTL:        &ttl64,
+				EncapLimit: &limit4,
+				FlowInfo:   &flowinfo,
+				Flags:      &ip6Flags,
+			},
+			decoded: &Ip6Gre{},
+		},
+		{
+			name: "ip6gretap",
+			in: &Ip6Gretap{
+				IFlags: &keyFlags,
+				IKey:   &key100,
+				Remote: net.ParseIP("2001:db8::2"),
+			},
+			decoded: &Ip6Gretap{},
+		},
+		{
+			name: "erspan",
+			in: &Erspan{
+				Remote:      net.ParseIP("198.51.100.1"),
+				ErspanIndex: &index9,
+				ErspanVer:   &ver2,
+				ErspanDir:   &dirEgress,
+				ErspanHwid:  &hwid3,
+			},
+			decoded: &Erspan{},
+		},
+		{
+			name: "ip6erspan",
+			in: &Ip6Erspan{
+				Remote:    net.ParseIP("2001:db8::2"),
+				ErspanVer: &ver2,
+			},
+			decoded: &Ip6Erspan{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae := netlink.NewAttributeEncoder()
+			if err := tt.in.Encode(ae); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			b, err := ae.Encode()
+			if err != nil {
+				t.Fatalf("failed to encode attributes: %v", err)
+			}
+
+			ad, err := netlink.NewAttributeDecoder(b)
+			if err != nil {
+				t.Fatalf("failed to create decoder: %v", err)
+			}
+
+			if err := tt.decoded.Decode(ad); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			// Addresses are decoded in their wire format.
+			if diff := cmp.Diff(tt.in, tt.decoded, cmp.Comparer(func(a, b net.IP) bool {
+				return a.Equal(b)
+			})); diff != "" {
+				t.Fatalf("unexpected configuration (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGreEncodeNetworkByteOrder(t *testing.T) {
+	var (
+		flags = GreFlagKey
+		key   = uint32(0x01020304)
+	)
+
+	ae := netlink.NewAttributeEncoder()
+	g := &Gre{
+		IFlags: &flags,
+		IKey:   &key,
+		Encap:  &TunnelEncap{Type: TunnelEncapGue, SourcePort: 0x1234, DestinationPort: 0x5678},
+	}
+	if err := g.Encode(ae); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	b, err := ae.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode attributes: %v", err)
+	}
+
+	ad, err := netlink.NewAttributeDecoder(b)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	want := map[uint16][]byte{
+		unix.IFLA_GRE_IFLAGS:      {0x20, 0x00},
+		unix.IFLA_GRE_IKEY:        {0x01, 0x02, 0x03, 0x04},
+		unix.IFLA_GRE_ENCAP_SPORT: {0x12, 0x34},
+		unix.IFLA_GRE_ENCAP_DPORT: {0x56, 0x78},
+	}
+	for ad.Next() {
+		if w, ok := want[ad.Type()]; ok && !bytes.Equal(w, ad.Bytes()) {
+			t.Errorf("unexpected attribute %d: want [%# x], got [%# x]", ad.Type(), w, ad.Bytes())
+		}
+	}
+}
+
+func TestGreEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		gre     rtnetlink.LinkDriver
+		wantErr string
+	}{
+		{
+			name:    "IPv6 local for gre",
+			gre:     &Gre{Local: net.ParseIP("2001:db8::1")},
+			wantErr: "local must be an IPv4 address",
+		},
+		{
+			name:    "IPv6 remote for gretap",
+			gre:     &Gretap{Remote: net.ParseIP("2001:db8::1")},
+			wantErr: "remote must be an IPv4 address",
+		},
+		{
+			name:    "IPv4 local for ip6gre",
+			gre:     &Ip6Gre{Local: net.ParseIP("192.0.2.1")},
+			wantErr: "local must be an IPv6 address",
+		},
+		{
+			name:    "IPv4 remote for ip6gretap",
+			gre:     &Ip6Gretap{Remote: net.ParseIP("192.0.2.1")},
+			wantErr: "remote must be an IPv6 address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae := netlink.NewAttributeEncoder()
+			err := tt.gre.Encode(ae)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestGreKind(t *testing.T) {
+	tests := []struct {
+		driver rtnetlink.LinkDriver
+		kind   string
+	}{
+		{&Gre{}, "gre"},
+		{&Gretap{}, "gretap"},
+		{&Ip6Gre{}, "ip6gre"},
+		{&Ip6Gretap{}, "ip6gretap"},
+		{&Erspan{}, "erspan"},
+		{&Ip6Erspan{}, "ip6erspan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if got := tt.driver.Kind(); got != tt.kind {
+				t.Errorf("expected kind %q, got %q", tt.kind, got)
+			}
+			if got := tt.driver.New().Kind(); got != tt.kind {
+				t.Errorf("expected New to return kind %q, got %q", tt.kind, got)
+			}
+		})
+	}
+}
+
+func TestTunnelEncapTypeString(t *testing.T) {
+	tests := []struct {
+		typ  TunnelEncapType
+		want string
+	}{
+		{TunnelEncapNone, "none"},
+		{TunnelEncapFou, "fou"},
+		{TunnelEncapGue, "gue"},
+		{TunnelEncapMpls, "mpls"},
+		{TunnelEncapType(99), "unknown TunnelEncapType value (99)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.typ.String(); got != tt.want {
+				t.Errorf("TunnelEncapType.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
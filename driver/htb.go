@@ -0,0 +1,250 @@
+package driver
+
+import (
+	"math"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+)
+
+const (
+	// sizeofHtbGlob is the size of struct tc_htb_glob.
+	sizeofHtbGlob = 20
+
+	// sizeofHtbOpt is the size of struct tc_htb_opt.
+	sizeofHtbOpt = 2*sizeofRatespec + 20
+
+	// htbHz and htbMtu are the timer frequency and MTU used by tc to
+	// calculate the default burst of a class as rate/HZ + MTU bytes.
+	htbHz  = 1000
+	htbMtu = 1600
+)
+
+// Htb represents a hierarchical token bucket queueing discipline configuration.
+type Htb struct {
+	// Rate2Quantum is the divisor used to calculate the quantum of classes
+	// from their rate. Defaults to 10.
+	Rate2Quantum *uint32
+
+	// DefaultClass is the minor number of the class unclassified traffic is
+	// sent to.
+	DefaultClass *uint32
+
+	// DirectPackets is the number of packets sent directly, without a class.
+	// It is only reported by the kernel.
+	DirectPackets *uint32
+
+	// DirectQlen is the queue length of the direct queue.
+	DirectQlen *uint32
+
+	// Offload requests hardware offload of the queueing discipline.
+	Offload bool
+}
+
+var _ rtnetlink.TcDriver = &Htb{}
+
+// New creates a new Htb instance.
+func (h *Htb) New() rtnetlink.TcDriver {
+	return &Htb{}
+}
+
+// Kind returns the HTB queueing discipline kind.
+func (h *Htb) Kind() string {
+	return "htb"
+}
+
+// Encode encodes the HTB configuration into netlink attributes.
+func (h *Htb) Encode(ae *netlink.AttributeEncoder) error {
+	// The kernel requires TCA_HTB_INIT to create the queueing discipline.
+	b := make([]byte, sizeofHtbGlob)
+	nlenc.PutUint32(b[0:4], unix.TC_HTB_PROTOVER)
+	r2q := uint32(10)
+	if h.Rate2Quantum != nil {
+		r2q = *h.Rate2Quantum
+	}
+	nlenc.PutUint32(b[4:8], r2q)
+	if h.DefaultClass != nil {
+		nlenc.PutUint32(b[8:12], *h.DefaultClass)
+	}
+	ae.Bytes(unix.TCA_HTB_INIT, b)
+
+	if h.DirectQlen != nil {
+		ae.Uint32(unix.TCA_HTB_DIRECT_QLEN, *h.DirectQlen)
+	}
+
+	if h.Offload {
+		ae.Flag(unix.TCA_HTB_OFFLOAD, true)
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the HTB configuration.
+func (h *Htb) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_HTB_INIT:
+			b := ad.Bytes()
+			if len(b) < sizeofHtbGlob {
+				return errInvalidTcOptions
+			}
+			r2q := nlenc.Uint32(b[4:8])
+			h.Rate2Quantum = &r2q
+			defcls := nlenc.Uint32(b[8:12])
+			h.DefaultClass = &defcls
+			direct := nlenc.Uint32(b[16:20])
+			h.DirectPackets = &direct
+		case unix.TCA_HTB_DIRECT_QLEN:
+			qlen := ad.Uint32()
+			h.DirectQlen = &qlen
+		case unix.TCA_HTB_OFFLOAD:
+			h.Offload = true
+		}
+	}
+
+	return ad.Err()
+}
+
+// HtbClass represents a hierarchical token bucket class configuration.
+type HtbClass struct {
+	// Rate is the guaranteed rate in bytes per second.
+	Rate *uint64
+
+	// Ceil is the maximum rate in bytes per second. Defaults to Rate.
+	Ceil *uint64
+
+	// Buffer is the burst at Rate in ticks of 64 nanoseconds. Defaults to
+	// the time needed to send Rate/1000 + 1600 bytes at Rate, like tc.
+	Buffer *uint32
+
+	// Cbuffer is the burst at Ceil in ticks of 64 nanoseconds. Defaults to
+	// the time needed to send Ceil/1000 + 1600 bytes at Ceil, like tc.
+	Cbuffer *uint32
+
+	// Quantum is the number of bytes served before moving to the next class.
+	// It is calculated from the rate when not set.
+	Quantum *uint32
+
+	// Prio is the priority of the class, lower values are served first.
+	Prio *uint32
+
+	// Level is the level of the class in the tree.
+	// It is only reported by the kernel.
+	Level *uint32
+}
+
+var _ rtnetlink.TcClassDriver = &HtbClass{}
+
+// New creates a new HtbClass instance.
+func (h *HtbClass) New() rtnetlink.TcDriver {
+	return &HtbClass{}
+}
+
+// Kind returns the HTB class kind.
+func (h *HtbClass) Kind() string {
+	return "htb"
+}
+
+// Class marks the driver as a class driver.
+func (h *HtbClass) Class() {}
+
+// Encode encodes the HTB class configuration into netlink attributes.
+func (h *HtbClass) Encode(ae *netlink.AttributeEncoder) error {
+	var rate uint64
+	if h.Rate != nil {
+		rate = *h.Rate
+	}
+	ceil := rate
+	if h.Ceil != nil {
+		ceil = *h.Ceil
+	}
+
+	b := make([]byte, sizeofHtbOpt)
+	putRatespec(b[0:12], rate)
+	putRatespec(b[12:24], ceil)
+	// A zero burst would throttle every packet.
+	buffer := htbBurst(rate)
+	if h.Buffer != nil {
+		buffer = *h.Buffer
+	}
+	nlenc.PutUint32(b[24:28], buffer)
+	cbuffer := htbBurst(ceil)
+	if h.Cbuffer != nil {
+		cbuffer = *h.Cbuffer
+	}
+	nlenc.PutUint32(b[28:32], cbuffer)
+	if h.Quantum != nil {
+		nlenc.PutUint32(b[32:36], *h.Quantum)
+	}
+	if h.Level != nil {
+		nlenc.PutUint32(b[36:40], *h.Level)
+	}
+	if h.Prio != nil {
+		nlenc.PutUint32(b[40:44], *h.Prio)
+	}
+	ae.Bytes(unix.TCA_HTB_PARMS, b)
+
+	if rate > math.MaxUint32 {
+		ae.Uint64(unix.TCA_HTB_RATE64, rate)
+	}
+
+	if ceil > math.MaxUint32 {
+		ae.Uint64(unix.TCA_HTB_CEIL64, ceil)
+	}
+
+	return nil
+}
+
+// htbBurst returns the default burst at rate in ticks of 64 nanoseconds.
+func htbBurst(rate uint64) uint32 {
+	if rate == 0 {
+		return 0
+	}
+
+	size := rate/htbHz + htbMtu
+	ticks := float64(size) * 1e9 / float64(rate) / 64
+	return uint32(min(ticks, math.MaxUint32))
+}
+
+// Decode decodes netlink attributes into the HTB class configuration.
+func (h *HtbClass) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_HTB_PARMS:
+			b := ad.Bytes()
+			if len(b) < sizeofHtbOpt {
+				return errInvalidTcOptions
+			}
+			// The 64 bit attributes follow and override the clamped rates.
+			if h.Rate == nil {
+				rate := ratespecRate(b[0:12])
+				h.Rate = &rate
+			}
+			if h.Ceil == nil {
+				ceil := ratespecRate(b[12:24])
+				h.Ceil = &ceil
+			}
+			buffer := nlenc.Uint32(b[24:28])
+			h.Buffer = &buffer
+			cbuffer := nlenc.Uint32(b[28:32])
+			h.Cbuffer = &cbuffer
+			quantum := nlenc.Uint32(b[32:36])
+			h.Quantum = &quantum
+			level := nlenc.Uint32(b[36:40])
+			h.Level = &level
+			prio := nlenc.Uint32(b[40:44])
+			h.Prio = &prio
+		case unix.TCA_HTB_RATE64:
+			rate := ad.Uint64()
+			h.Rate = &rate
+		case unix.TCA_HTB_CEIL64:
+			ceil := ad.Uint64()
+			h.Ceil = &ceil
+		}
+	}
+
+	return ad.Err()
+}
@@ -0,0 +1,554 @@
+package driver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// Ipip represents an IPv4 over IPv4 tunnel device configuration.
+type Ipip struct {
+	// Link specifies the physical device to use for tunnel endpoint
+	// communication.
+	Link *uint32
+
+	// Local specifies the IPv4 source address of the tunnel.
+	Local net.IP
+
+	// Remote specifies the IPv4 destination address of the tunnel.
+	Remote net.IP
+
+	// TTL specifies the TTL of transmitted packets, 0 inherits it from the
+	// inner packet.
+	TTL *uint8
+
+	// TOS specifies the TOS of transmitted packets.
+	TOS *uint8
+
+	// Proto specifies the inner protocol, unix.IPPROTO_IPIP, unix.IPPROTO_MPLS
+	// or 0 for both.
+	Proto *uint8
+
+	// PMTUDisc enables path MTU discovery.
+	PMTUDisc *bool
+
+	// FwMark specifies the firewall mark of transmitted packets.
+	FwMark *uint32
+
+	// Encap specifies the UDP encapsulation of transmitted packets.
+	Encap *TunnelEncap
+
+	// CollectMetadata enables metadata collection mode, in which the
+	// tunnel parameters are taken from the metadata of each packet.
+	CollectMetadata *bool
+}
+
+var _ rtnetlink.LinkDriverVerifier = &Ipip{}
+
+// New creates a new Ipip instance.
+func (i *Ipip) New() rtnetlink.LinkDriver {
+	return &Ipip{}
+}
+
+// Kind returns the IPIP interface kind.
+func (*Ipip) Kind() string {
+	return "ipip"
+}
+
+// Verify checks that the addresses of the tunnel are IPv4 and that its inner
+// protocol is supported.
+func (i *Ipip) Verify(*rtnetlink.LinkMessage) error {
+	return verifyIPTunnel(i.Kind(), i.Local, i.Remote, false, i.Proto,
+		unix.IPPROTO_IPIP, unix.IPPROTO_MPLS)
+}
+
+// Encode encodes the IPIP configuration into netlink attributes.
+func (i *Ipip) Encode(ae *netlink.AttributeEncoder) error {
+	if i.Link != nil {
+		ae.Uint32(unix.IFLA_IPTUN_LINK, *i.Link)
+	}
+	if err := encodeIPTunnelAddrs(ae, i.Local, i.Remote, false); err != nil {
+		return err
+	}
+	if i.TTL != nil {
+		ae.Uint8(unix.IFLA_IPTUN_TTL, *i.TTL)
+	}
+	if i.TOS != nil {
+		ae.Uint8(unix.IFLA_IPTUN_TOS, *i.TOS)
+	}
+	if i.Proto != nil {
+		ae.Uint8(unix.IFLA_IPTUN_PROTO, *i.Proto)
+	}
+	if i.PMTUDisc != nil {
+		var val uint8
+		if *i.PMTUDisc {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_IPTUN_PMTUDISC, val)
+	}
+	if i.FwMark != nil {
+		ae.Uint32(unix.IFLA_IPTUN_FWMARK, *i.FwMark)
+	}
+	if i.Encap != nil {
+		i.Encap.encode(ae, unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+			unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT)
+	}
+	if i.CollectMetadata != nil {
+		if *i.CollectMetadata {
+			ae.Flag(unix.IFLA_IPTUN_COLLECT_METADATA, true)
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the IPIP configuration.
+func (i *Ipip) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_IPTUN_LINK:
+			val := ad.Uint32()
+			i.Link = &val
+		case unix.IFLA_IPTUN_LOCAL:
+			i.Local = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_REMOTE:
+			i.Remote = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_TTL:
+			val := ad.Uint8()
+			i.TTL = &val
+		case unix.IFLA_IPTUN_TOS:
+			val := ad.Uint8()
+			i.TOS = &val
+		case unix.IFLA_IPTUN_PROTO:
+			val := ad.Uint8()
+			i.Proto = &val
+		case unix.IFLA_IPTUN_PMTUDISC:
+			val := ad.Uint8() != 0
+			i.PMTUDisc = &val
+		case unix.IFLA_IPTUN_FWMARK:
+			val := ad.Uint32()
+			i.FwMark = &val
+		case unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+			unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT:
+			if i.Encap == nil {
+				i.Encap = &TunnelEncap{}
+			}
+			i.Encap.decode(ad, unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+				unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT)
+		case unix.IFLA_IPTUN_COLLECT_METADATA:
+			val := true
+			i.CollectMetadata = &val
+		}
+	}
+
+	return ad.Err()
+}
+
+// Sit6rd specifies the IPv6 rapid deployment (6rd) prefixes of a SIT tunnel.
+type Sit6rd struct {
+	// Prefix specifies the 6rd IPv6 prefix.
+	Prefix net.IP
+
+	// PrefixLen specifies the length of Prefix.
+	PrefixLen uint16
+
+	// RelayPrefix specifies the IPv4 prefix of the 6rd relays.
+	RelayPrefix net.IP
+
+	// RelayPrefixLen specifies the length of RelayPrefix.
+	RelayPrefixLen uint16
+}
+
+// Sit represents an IPv6 over IPv4 (6in4) tunnel device configuration.
+type Sit struct {
+	// Link specifies the physical device to use for tunnel endpoint
+	// communication.
+	Link *uint32
+
+	// Local specifies the IPv4 source address of the tunnel.
+	Local net.IP
+
+	// Remote specifies the IPv4 destination address of the tunnel.
+	Remote net.IP
+
+	// TTL specifies the TTL of transmitted packets, 0 inherits it from the
+	// inner packet.
+	TTL *uint8
+
+	// TOS specifies the TOS of transmitted packets.
+	TOS *uint8
+
+	// Proto specifies the inner protocol, unix.IPPROTO_IPV6,
+	// unix.IPPROTO_IPIP, unix.IPPROTO_MPLS or 0 for all of them.
+	Proto *uint8
+
+	// PMTUDisc enables path MTU discovery.
+	PMTUDisc *bool
+
+	// Isatap enables the ISATAP mode of the tunnel.
+	Isatap *bool
+
+	// Prefix6rd specifies the 6rd prefixes of the tunnel.
+	Prefix6rd *Sit6rd
+
+	// FwMark specifies the firewall mark of transmitted packets.
+	FwMark *uint32
+
+	// Encap specifies the UDP encapsulation of transmitted packets.
+	Encap *TunnelEncap
+
+	// CollectMetadata enables metadata collection mode, in which the
+	// tunnel parameters are taken from the metadata of each packet.
+	CollectMetadata *bool
+}
+
+var _ rtnetlink.LinkDriverVerifier = &Sit{}
+
+// New creates a new Sit instance.
+func (s *Sit) New() rtnetlink.LinkDriver {
+	return &Sit{}
+}
+
+// Kind returns the SIT interface kind.
+func (*Sit) Kind() string {
+	return "sit"
+}
+
+// Verify checks that the addresses of the tunnel are IPv4, that its inner
+// protocol is supported and that the 6rd prefixes are of the right families.
+func (s *Sit) Verify(*rtnetlink.LinkMessage) error {
+	err := verifyIPTunnel(s.Kind(), s.Local, s.Remote, false, s.Proto,
+		unix.IPPROTO_IPV6, unix.IPPROTO_IPIP, unix.IPPROTO_MPLS)
+	if err != nil {
+		return err
+	}
+
+	if p := s.Prefix6rd; p != nil {
+		if p.Prefix != nil && p.Prefix.To4() != nil {
+			return fmt.Errorf("6rd prefix must be an IPv6 address")
+		}
+		if p.PrefixLen > 8*net.IPv6len {
+			return fmt.Errorf("invalid 6rd prefix length %d", p.PrefixLen)
+		}
+		if p.RelayPrefix != nil && p.RelayPrefix.To4() == nil {
+			return fmt.Errorf("6rd relay prefix must be an IPv4 address")
+		}
+		if p.RelayPrefixLen > 8*net.IPv4len {
+			return fmt.Errorf("invalid 6rd relay prefix length %d", p.RelayPrefixLen)
+		}
+	}
+
+	return nil
+}
+
+// Encode encodes the SIT configuration into netlink attributes.
+func (s *Sit) Encode(ae *netlink.AttributeEncoder) error {
+	if s.Link != nil {
+		ae.Uint32(unix.IFLA_IPTUN_LINK, *s.Link)
+	}
+	if err := encodeIPTunnelAddrs(ae, s.Local, s.Remote, false); err != nil {
+		return err
+	}
+	if s.TTL != nil {
+		ae.Uint8(unix.IFLA_IPTUN_TTL, *s.TTL)
+	}
+	if s.TOS != nil {
+		ae.Uint8(unix.IFLA_IPTUN_TOS, *s.TOS)
+	}
+	if s.Proto != nil {
+		ae.Uint8(unix.IFLA_IPTUN_PROTO, *s.Proto)
+	}
+	if s.PMTUDisc != nil {
+		var val uint8
+		if *s.PMTUDisc {
+			val = 1
+		}
+		ae.Uint8(unix.IFLA_IPTUN_PMTUDISC, val)
+	}
+	if s.Isatap != nil {
+		var val uint16
+		if *s.Isatap {
+			val = unix.SIT_ISATAP
+		}
+		ae.Uint16(unix.IFLA_IPTUN_FLAGS, val)
+	}
+	if p := s.Prefix6rd; p != nil {
+		if p.Prefix != nil {
+			ae.Bytes(unix.IFLA_IPTUN_6RD_PREFIX, p.Prefix.To16())
+		}
+		if p.RelayPrefix != nil {
+			ae.Bytes(unix.IFLA_IPTUN_6RD_RELAY_PREFIX, p.RelayPrefix.To4())
+		}
+		ae.Uint16(unix.IFLA_IPTUN_6RD_PREFIXLEN, p.PrefixLen)
+		ae.Uint16(unix.IFLA_IPTUN_6RD_RELAY_PREFIXLEN, p.RelayPrefixLen)
+	}
+	if s.FwMark != nil {
+		ae.Uint32(unix.IFLA_IPTUN_FWMARK, *s.FwMark)
+	}
+	if s.Encap != nil {
+		s.Encap.encode(ae, unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+			unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT)
+	}
+	if s.CollectMetadata != nil {
+		if *s.CollectMetadata {
+			ae.Flag(unix.IFLA_IPTUN_COLLECT_METADATA, true)
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the SIT configuration.
+func (s *Sit) Decode(ad *netlink.AttributeDecoder) error {
+	prefix6rd := func() *Sit6rd {
+		if s.Prefix6rd == nil {
+			s.Prefix6rd = &Sit6rd{}
+		}
+		return s.Prefix6rd
+	}
+
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_IPTUN_LINK:
+			val := ad.Uint32()
+			s.Link = &val
+		case unix.IFLA_IPTUN_LOCAL:
+			s.Local = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_REMOTE:
+			s.Remote = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_TTL:
+			val := ad.Uint8()
+			s.TTL = &val
+		case unix.IFLA_IPTUN_TOS:
+			val := ad.Uint8()
+			s.TOS = &val
+		case unix.IFLA_IPTUN_PROTO:
+			val := ad.Uint8()
+			s.Proto = &val
+		case unix.IFLA_IPTUN_PMTUDISC:
+			val := ad.Uint8() != 0
+			s.PMTUDisc = &val
+		case unix.IFLA_IPTUN_FLAGS:
+			val := ad.Uint16()&unix.SIT_ISATAP != 0
+			s.Isatap = &val
+		case unix.IFLA_IPTUN_6RD_PREFIX:
+			prefix6rd().Prefix = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_6RD_RELAY_PREFIX:
+			prefix6rd().RelayPrefix = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_6RD_PREFIXLEN:
+			prefix6rd().PrefixLen = ad.Uint16()
+		case unix.IFLA_IPTUN_6RD_RELAY_PREFIXLEN:
+			prefix6rd().RelayPrefixLen = ad.Uint16()
+		case unix.IFLA_IPTUN_FWMARK:
+			val := ad.Uint32()
+			s.FwMark = &val
+		case unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+			unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT:
+			if s.Encap == nil {
+				s.Encap = &TunnelEncap{}
+			}
+			s.Encap.decode(ad, unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+				unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT)
+		case unix.IFLA_IPTUN_COLLECT_METADATA:
+			val := true
+			s.CollectMetadata = &val
+		}
+	}
+
+	return ad.Err()
+}
+
+// Ip6Tnl represents an IPv4 or IPv6 over IPv6 tunnel device configuration.
+type Ip6Tnl struct {
+	// Link specifies the physical device to use for tunnel endpoint
+	// communication.
+	Link *uint32
+
+	// Local specifies the IPv6 source address of the tunnel.
+	Local net.IP
+
+	// Remote specifies the IPv6 destination address of the tunnel.
+	Remote net.IP
+
+	// TTL specifies the hop limit of transmitted packets.
+	TTL *uint8
+
+	// EncapLimit specifies the tunnel encapsulation limit.
+	EncapLimit *uint8
+
+	// FlowInfo specifies the flow label and traffic class of transmitted
+	// packets.
+	FlowInfo *uint32
+
+	// Flags specifies the IPv6 tunnel flags.
+	Flags *Ip6TunnelFlag
+
+	// Proto specifies the inner protocol, unix.IPPROTO_IPV6,
+	// unix.IPPROTO_IPIP or 0 for both.
+	Proto *uint8
+
+	// FwMark specifies the firewall mark of transmitted packets.
+	FwMark *uint32
+
+	// Encap specifies the UDP encapsulation of transmitted packets.
+	Encap *TunnelEncap
+
+	// CollectMetadata enables metadata collection mode, in which the
+	// tunnel parameters are taken from the metadata of each packet.
+	CollectMetadata *bool
+}
+
+var _ rtnetlink.LinkDriverVerifier = &Ip6Tnl{}
+
+// New creates a new Ip6Tnl instance.
+func (i *Ip6Tnl) New() rtnetlink.LinkDriver {
+	return &Ip6Tnl{}
+}
+
+// Kind returns the IP6TNL interface kind.
+func (*Ip6Tnl) Kind() string {
+	return "ip6tnl"
+}
+
+// Verify checks that the addresses of the tunnel are IPv6 and that its inner
+// protocol is supported.
+func (i *Ip6Tnl) Verify(*rtnetlink.LinkMessage) error {
+	return verifyIPTunnel(i.Kind(), i.Local, i.Remote, true, i.Proto,
+		unix.IPPROTO_IPV6, unix.IPPROTO_IPIP)
+}
+
+// Encode encodes the IP6TNL configuration into netlink attributes.
+func (i *Ip6Tnl) Encode(ae *netlink.AttributeEncoder) error {
+	if i.Link != nil {
+		ae.Uint32(unix.IFLA_IPTUN_LINK, *i.Link)
+	}
+	if err := encodeIPTunnelAddrs(ae, i.Local, i.Remote, true); err != nil {
+		return err
+	}
+	if i.TTL != nil {
+		ae.Uint8(unix.IFLA_IPTUN_TTL, *i.TTL)
+	}
+	if i.EncapLimit != nil {
+		ae.Uint8(unix.IFLA_IPTUN_ENCAP_LIMIT, *i.EncapLimit)
+	}
+	if i.FlowInfo != nil {
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, *i.FlowInfo)
+		ae.Bytes(unix.IFLA_IPTUN_FLOWINFO, buf)
+	}
+	if i.Flags != nil {
+		ae.Uint32(unix.IFLA_IPTUN_FLAGS, uint32(*i.Flags))
+	}
+	if i.Proto != nil {
+		ae.Uint8(unix.IFLA_IPTUN_PROTO, *i.Proto)
+	}
+	if i.FwMark != nil {
+		ae.Uint32(unix.IFLA_IPTUN_FWMARK, *i.FwMark)
+	}
+	if i.Encap != nil {
+		i.Encap.encode(ae, unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+			unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT)
+	}
+	if i.CollectMetadata != nil {
+		if *i.CollectMetadata {
+			ae.Flag(unix.IFLA_IPTUN_COLLECT_METADATA, true)
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the IP6TNL configuration.
+func (i *Ip6Tnl) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_IPTUN_LINK:
+			val := ad.Uint32()
+			i.Link = &val
+		case unix.IFLA_IPTUN_LOCAL:
+			i.Local = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_REMOTE:
+			i.Remote = net.IP(ad.Bytes())
+		case unix.IFLA_IPTUN_TTL:
+			val := ad.Uint8()
+			i.TTL = &val
+		case unix.IFLA_IPTUN_ENCAP_LIMIT:
+			val := ad.Uint8()
+			i.EncapLimit = &val
+		case unix.IFLA_IPTUN_FLOWINFO:
+			if buf := ad.Bytes(); len(buf) >= 4 {
+				val := binary.BigEndian.Uint32(buf)
+				i.FlowInfo = &val
+			}
+		case unix.IFLA_IPTUN_FLAGS:
+			val := Ip6TunnelFlag(ad.Uint32())
+			i.Flags = &val
+		case unix.IFLA_IPTUN_PROTO:
+			val := ad.Uint8()
+			i.Proto = &val
+		case unix.IFLA_IPTUN_FWMARK:
+			val := ad.Uint32()
+			i.FwMark = &val
+		case unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+			unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT:
+			if i.Encap == nil {
+				i.Encap = &TunnelEncap{}
+			}
+			i.Encap.decode(ad, unix.IFLA_IPTUN_ENCAP_TYPE, unix.IFLA_IPTUN_ENCAP_FLAGS,
+				unix.IFLA_IPTUN_ENCAP_SPORT, unix.IFLA_IPTUN_ENCAP_DPORT)
+		case unix.IFLA_IPTUN_COLLECT_METADATA:
+			val := true
+			i.CollectMetadata = &val
+		}
+	}
+
+	return ad.Err()
+}
+
+// verifyIPTunnel checks that the local and remote addresses of the tunnel of
+// the given kind are IPv4 or, if ip6 is set, IPv6 addresses, and that proto
+// is one of protos or 0.
+func verifyIPTunnel(kind string, local, remote net.IP, ip6 bool, proto *uint8, protos ...uint8) error {
+	if local != nil {
+		if _, err := tunnelIP("local", local, ip6); err != nil {
+			return err
+		}
+	}
+	if remote != nil {
+		if _, err := tunnelIP("remote", remote, ip6); err != nil {
+			return err
+		}
+	}
+	if proto != nil && *proto != 0 && !slices.Contains(protos, *proto) {
+		return fmt.Errorf("protocol %d is not supported by %s tunnels", *proto, kind)
+	}
+
+	return nil
+}
+
+// encodeIPTunnelAddrs encodes the local and remote addresses of the tunnel
+// as IPv4 or, if ip6 is set, IPv6 addresses.
+func encodeIPTunnelAddrs(ae *netlink.AttributeEncoder, local, remote net.IP, ip6 bool) error {
+	if local != nil {
+		ip, err := tunnelIP("local", local, ip6)
+		if err != nil {
+			return err
+		}
+		ae.Bytes(unix.IFLA_IPTUN_LOCAL, ip)
+	}
+	if remote != nil {
+		ip, err := tunnelIP("remote", remote, ip6)
+		if err != nil {
+			return err
+		}
+		ae.Bytes(unix.IFLA_IPTUN_REMOTE, ip)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+//go:build integration
+// +build integration
+
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestIPTunnel(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	var (
+		local4  = net.ParseIP("192.0.2.1").To4()
+		remote4 = net.ParseIP("198.51.100.1").To4()
+		local6  = net.ParseIP("2001:db8::1")
+		remote6 = net.ParseIP("2001:db8::2")
+	)
+
+	tests := []struct {
+		name     string
+		linkName string
+		driver   rtnetlink.LinkDriver
+		// got returns the configured fields of the decoded driver.
+		got      func(rtnetlink.LinkDriver) rtnetlink.LinkDriver
+		expected rtnetlink.LinkDriver
+	}{
+		{
+			name:     "ipip",
+			linkName: "ipip1",
+			driver: &Ipip{
+				Local:  local4,
+				Remote: remote4,
+				TTL:    ptr(uint8(64)),
+				Proto:  ptr(uint8(unix.IPPROTO_IPIP)),
+			},
+			got: func(d rtnetlink.LinkDriver) rtnetlink.LinkDriver {
+				i := d.(*Ipip)
+				return &Ipip{Local: i.Local, Remote: i.Remote, TTL: i.TTL, Proto: i.Proto}
+			},
+			expected: &Ipip{
+				Local:  local4,
+				Remote: remote4,
+				TTL:    ptr(uint8(64)),
+				Proto:  ptr(uint8(unix.IPPROTO_IPIP)),
+			},
+		},
+		{
+			name:     "sit",
+			linkName: "sit1",
+			driver: &Sit{
+				Local:  local4,
+				Remote: remote4,
+				TTL:    ptr(uint8(64)),
+			},
+			got: func(d rtnetlink.LinkDriver) rtnetlink.LinkDriver {
+				s := d.(*Sit)
+				return &Sit{Local: s.Local, Remote: s.Remote, TTL: s.TTL, Isatap: s.Isatap}
+			},
+			expected: &Sit{
+				Local:  local4,
+				Remote: remote4,
+				TTL:    ptr(uint8(64)),
+				Isatap: ptr(false),
+			},
+		},
+		{
+			name:     "ip6tnl",
+			linkName: "ip6tnl1",
+			driver: &Ip6Tnl{
+				Local:      local6,
+				Remote:     remote6,
+				TTL:        ptr(uint8(64)),
+				EncapLimit: ptr(uint8(4)),
+				Proto:      ptr(uint8(unix.IPPROTO_IPV6)),
+			},
+			got: func(d rtnetlink.LinkDriver) rtnetlink.LinkDriver {
+				i := d.(*Ip6Tnl)
+				return &Ip6Tnl{Local: i.Local, Remote: i.Remote, TTL: i.TTL, EncapLimit: i.EncapLimit, Proto: i.Proto}
+			},
+			expected: &Ip6Tnl{
+				Local:      local6,
+				Remote:     remote6,
+				TTL:        ptr(uint8(64)),
+				EncapLimit: ptr(uint8(4)),
+				Proto:      ptr(uint8(unix.IPPROTO_IPV6)),
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ifIndex := uint32(2500 + i)
+
+			if err := setupInterface(conn, tt.linkName, ifIndex, 0, tt.driver); err != nil {
+				t.Fatalf("failed to setup %s interface: %v", tt.name, err)
+			}
+			defer conn.Link.Delete(ifIndex)
+
+			msg, err := getInterface(conn, ifIndex)
+			if err != nil {
+				t.Fatalf("failed to get %s interface: %v", tt.name, err)
+			}
+
+			if msg.Attributes == nil || msg.Attributes.Info == nil || msg.Attributes.Info.Data == nil {
+				t.Fatal("interface missing link info data")
+			}
+
+			if diff := cmp.Diff(tt.expected, tt.got(msg.Attributes.Info.Data)); diff != "" {
+				t.Fatalf("unexpected %s config (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+
+	t.Run("verify", func(t *testing.T) {
+		const ifIndex = 2510
+
+		err := setupInterface(conn, "ipip2", ifIndex, 0, &Ipip{Remote: remote6})
+		if err == nil {
+			conn.Link.Delete(ifIndex)
+			t.Fatal("expected an error creating an ipip tunnel with an IPv6 remote")
+		}
+	})
+}
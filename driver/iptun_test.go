@@ -0,0 +1,226 @@
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestIPTunnelEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      rtnetlink.LinkDriver
+		decoded rtnetlink.LinkDriver
+	}{
+		{
+			name: "ipip",
+			in: &Ipip{
+				Link:     ptr(uint32(2)),
+				Local:    net.ParseIP("192.0.2.1"),
+				Remote:   net.ParseIP("198.51.100.1"),
+				TTL:      ptr(uint8(64)),
+				TOS:      ptr(uint8(0x10)),
+				Proto:    ptr(uint8(unix.IPPROTO_IPIP)),
+				PMTUDisc: ptr(true),
+				FwMark:   ptr(uint32(7)),
+				Encap: &TunnelEncap{
+					Type:            TunnelEncapGue,
+					Flags:           TunnelEncapFlagCsum | TunnelEncapFlagRemCsum,
+					SourcePort:      1000,
+					DestinationPort: 5555,
+				},
+			},
+			decoded: &Ipip{},
+		},
+		{
+			name: "ipip collect metadata",
+			in: &Ipip{
+				CollectMetadata: ptr(true),
+			},
+			decoded: &Ipip{},
+		},
+		{
+			name: "sit 6rd",
+			in: &Sit{
+				Local:    net.ParseIP("192.0.2.1"),
+				TTL:      ptr(uint8(64)),
+				Proto:    ptr(uint8(unix.IPPROTO_IPV6)),
+				PMTUDisc: ptr(false),
+				Isatap:   ptr(false),
+				Prefix6rd: &Sit6rd{
+					Prefix:         net.ParseIP("2001:db8::"),
+					PrefixLen:      32,
+					RelayPrefix:    net.ParseIP("192.0.2.0"),
+					RelayPrefixLen: 24,
+				},
+			},
+			decoded: &Sit{},
+		},
+		{
+			name: "sit isatap",
+			in: &Sit{
+				Local:  net.ParseIP("192.0.2.1"),
+				Isatap: ptr(true),
+			},
+			decoded: &Sit{},
+		},
+		{
+			name: "ip6tnl",
+			in: &Ip6Tnl{
+				Local:      net.ParseIP("2001:db8::1"),
+				Remote:     net.ParseIP("2001:db8::2"),
+				TTL:        ptr(uint8(64)),
+				EncapLimit: ptr(uint8(4)),
+				FlowInfo:   ptr(uint32(0x12345)),
+				Flags:      ptr(Ip6TunnelFlagIgnEncapLimit | Ip6TunnelFlagRcvDscpCopy),
+				Proto:      ptr(uint8(unix.IPPROTO_IPIP)),
+				FwMark:     ptr(uint32(7)),
+			},
+			decoded: &Ip6Tnl{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae := netlink.NewAttributeEncoder()
+			if err := tt.in.Encode(ae); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			b, err := ae.Encode()
+			if err != nil {
+				t.Fatalf("failed to encode attributes: %v", err)
+			}
+
+			ad, err := netlink.NewAttributeDecoder(b)
+			if err != nil {
+				t.Fatalf("failed to create decoder: %v", err)
+			}
+
+			if err := tt.decoded.Decode(ad); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			// Addresses are decoded in their wire format.
+			if diff := cmp.Diff(tt.in, tt.decoded, cmp.Comparer(func(a, b net.IP) bool {
+				return a.Equal(b)
+			})); diff != "" {
+				t.Fatalf("unexpected configuration (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIPTunnelVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  rtnetlink.LinkDriverVerifier
+		wantErr string
+	}{
+		{
+			name:   "ipip",
+			driver: &Ipip{Local: net.ParseIP("192.0.2.1"), Proto: ptr(uint8(unix.IPPROTO_MPLS))},
+		},
+		{
+			name:    "ipip IPv6 remote",
+			driver:  &Ipip{Remote: net.ParseIP("2001:db8::1")},
+			wantErr: "remote must be an IPv4 address",
+		},
+		{
+			name:    "ipip IPv6 protocol",
+			driver:  &Ipip{Proto: ptr(uint8(unix.IPPROTO_IPV6))},
+			wantErr: "protocol 41 is not supported by ipip tunnels",
+		},
+		{
+			name: "sit",
+			driver: &Sit{
+				Local: net.ParseIP("192.0.2.1"),
+				Prefix6rd: &Sit6rd{
+					Prefix:         net.ParseIP("2001:db8::"),
+					PrefixLen:      32,
+					RelayPrefix:    net.ParseIP("192.0.2.0"),
+					RelayPrefixLen: 24,
+				},
+			},
+		},
+		{
+			name:    "sit IPv6 local",
+			driver:  &Sit{Local: net.ParseIP("2001:db8::1")},
+			wantErr: "local must be an IPv4 address",
+		},
+		{
+			name:    "sit IPv4 6rd prefix",
+			driver:  &Sit{Prefix6rd: &Sit6rd{Prefix: net.ParseIP("192.0.2.0")}},
+			wantErr: "6rd prefix must be an IPv6 address",
+		},
+		{
+			name:    "sit IPv6 6rd relay prefix",
+			driver:  &Sit{Prefix6rd: &Sit6rd{RelayPrefix: net.ParseIP("2001:db8::")}},
+			wantErr: "6rd relay prefix must be an IPv4 address",
+		},
+		{
+			name:    "sit 6rd relay prefix length",
+			driver:  &Sit{Prefix6rd: &Sit6rd{RelayPrefixLen: 33}},
+			wantErr: "invalid 6rd relay prefix length 33",
+		},
+		{
+			name:   "ip6tnl",
+			driver: &Ip6Tnl{Local: net.ParseIP("2001:db8::1"), Remote: net.ParseIP("2001:db8::2")},
+		},
+		{
+			name:    "ip6tnl IPv4 local",
+			driver:  &Ip6Tnl{Local: net.ParseIP("192.0.2.1")},
+			wantErr: "local must be an IPv6 address",
+		},
+		{
+			name:    "ip6tnl MPLS protocol",
+			driver:  &Ip6Tnl{Proto: ptr(uint8(unix.IPPROTO_MPLS))},
+			wantErr: "protocol 137 is not supported by ip6tnl tunnels",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.driver.Verify(&rtnetlink.LinkMessage{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("failed to verify: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestIPTunnelKind(t *testing.T) {
+	tests := []struct {
+		driver rtnetlink.LinkDriver
+		kind   string
+	}{
+		{&Ipip{}, "ipip"},
+		{&Sit{}, "sit"},
+		{&Ip6Tnl{}, "ip6tnl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if got := tt.driver.Kind(); got != tt.kind {
+				t.Errorf("expected kind %q, got %q", tt.kind, got)
+			}
+			if got := tt.driver.New().Kind(); got != tt.kind {
+				t.Errorf("expected New to return kind %q, got %q", tt.kind, got)
+			}
+		})
+	}
+}
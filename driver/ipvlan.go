@@ -0,0 +1,160 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// IpvlanMode represents the IPVLAN operating mode.
+type IpvlanMode uint16
+
+// IPVLAN modes.
+const (
+	IpvlanModeL2  IpvlanMode = 0x0
+	IpvlanModeL3  IpvlanMode = 0x1
+	IpvlanModeL3S IpvlanMode = 0x2
+)
+
+// String returns a string representation of the IpvlanMode.
+func (m IpvlanMode) String() string {
+	switch m {
+	case IpvlanModeL2:
+		return "l2"
+	case IpvlanModeL3:
+		return "l3"
+	case IpvlanModeL3S:
+		return "l3s"
+	default:
+		return fmt.Sprintf("unknown IpvlanMode value (%d)", m)
+	}
+}
+
+// IpvlanFlag represents IPVLAN flags.
+type IpvlanFlag uint16
+
+// IPVLAN flags. Bridge is the default and has no bit of its own.
+const (
+	IpvlanFlagBridge  IpvlanFlag = 0x0
+	IpvlanFlagPrivate IpvlanFlag = 0x1
+	IpvlanFlagVEPA    IpvlanFlag = 0x2
+)
+
+// String returns a string representation of the IpvlanFlag.
+func (f IpvlanFlag) String() string {
+	switch f {
+	case IpvlanFlagBridge:
+		return "bridge"
+	case IpvlanFlagPrivate:
+		return "private"
+	case IpvlanFlagVEPA:
+		return "vepa"
+	default:
+		return fmt.Sprintf("unknown IpvlanFlag value (%d)", f)
+	}
+}
+
+// Ipvlan represents an IPVLAN device configuration.
+type Ipvlan struct {
+	// Mode specifies the IPVLAN mode (l2, l3, l3s).
+	Mode *IpvlanMode
+
+	// Flags specifies the IPVLAN port mode (bridge, private, vepa).
+	Flags *IpvlanFlag
+}
+
+var _ rtnetlink.LinkDriverVerifier = &Ipvlan{}
+
+// New creates a new Ipvlan instance.
+func (i *Ipvlan) New() rtnetlink.LinkDriver {
+	return &Ipvlan{}
+}
+
+// Kind returns the IPVLAN interface kind.
+func (i *Ipvlan) Kind() string {
+	return "ipvlan"
+}
+
+// Verify checks that a new interface has a parent link, set through
+// LinkAttributes.Type, and that private and vepa are not combined. A message
+// with an Index may also change an existing interface, which needs no parent
+// link, so for those the parent is left to the kernel to check.
+func (i *Ipvlan) Verify(msg *rtnetlink.LinkMessage) error {
+	return verifyIpvlan(i.Kind(), i, msg)
+}
+
+// Encode encodes the IPVLAN configuration into netlink attributes.
+func (i *Ipvlan) Encode(ae *netlink.AttributeEncoder) error {
+	if i.Mode != nil {
+		ae.Uint16(unix.IFLA_IPVLAN_MODE, uint16(*i.Mode))
+	}
+
+	if i.Flags != nil {
+		ae.Uint16(unix.IFLA_IPVLAN_FLAGS, uint16(*i.Flags))
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the IPVLAN configuration.
+func (i *Ipvlan) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_IPVLAN_MODE:
+			mode := IpvlanMode(ad.Uint16())
+			i.Mode = &mode
+		case unix.IFLA_IPVLAN_FLAGS:
+			flags := IpvlanFlag(ad.Uint16())
+			i.Flags = &flags
+		}
+	}
+
+	return ad.Err()
+}
+
+func verifyIpvlan(kind string, i *Ipvlan, msg *rtnetlink.LinkMessage) error {
+	if msg.Index == 0 && (msg.Attributes == nil || msg.Attributes.Type == 0) {
+		return fmt.Errorf("%s requires a parent link", kind)
+	}
+	if i.Flags != nil && *i.Flags&IpvlanFlagPrivate != 0 && *i.Flags&IpvlanFlagVEPA != 0 {
+		return errors.New("private and vepa flags are mutually exclusive")
+	}
+	return nil
+}
+
+// Ipvtap represents an IPVTAP device configuration, an IPVLAN device with a
+// tap character device attached.
+type Ipvtap Ipvlan
+
+var _ rtnetlink.LinkDriverVerifier = &Ipvtap{}
+
+// New creates a new Ipvtap instance.
+func (i *Ipvtap) New() rtnetlink.LinkDriver {
+	return &Ipvtap{}
+}
+
+// Kind returns the IPVTAP interface kind.
+func (i *Ipvtap) Kind() string {
+	return "ipvtap"
+}
+
+// Verify checks that a new interface has a parent link, set through
+// LinkAttributes.Type, and that private and vepa are not combined. See
+// Ipvlan.Verify.
+func (i *Ipvtap) Verify(msg *rtnetlink.LinkMessage) error {
+	return verifyIpvlan(i.Kind(), (*Ipvlan)(i), msg)
+}
+
+// Encode encodes the IPVTAP configuration into netlink attributes.
+func (i *Ipvtap) Encode(ae *netlink.AttributeEncoder) error {
+	return (*Ipvlan)(i).Encode(ae)
+}
+
+// Decode decodes netlink attributes into the IPVTAP configuration.
+func (i *Ipvtap) Decode(ad *netlink.AttributeDecoder) error {
+	return (*Ipvlan)(i).Decode(ad)
+}
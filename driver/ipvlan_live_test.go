@@ -0,0 +1,135 @@
+//go:build integration
+// +build integration
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func ipvlanT(d rtnetlink.LinkDriver) *Ipvlan {
+	switch i := d.(type) {
+	case *Ipvlan:
+		return i
+	case *Ipvtap:
+		return (*Ipvlan)(i)
+	default:
+		return nil
+	}
+}
+
+func TestIpvlanModes(t *testing.T) {
+	connNS, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer connNS.Close()
+
+	// Create parent interface in netns
+	const parentIndex = 2800
+	if err := setupInterface(connNS, "ipvpar0", parentIndex, 0, &rtnetlink.LinkData{Name: "dummy"}); err != nil {
+		t.Fatalf("failed to create parent interface: %v", err)
+	}
+	defer connNS.Link.Delete(parentIndex)
+
+	tests := []struct {
+		name   string
+		index  uint32
+		driver rtnetlink.LinkDriver
+		mode   IpvlanMode
+		flags  IpvlanFlag
+	}{
+		{
+			name:   "l2 mode",
+			index:  2801,
+			driver: &Ipvlan{Mode: ptr(IpvlanModeL2)},
+			mode:   IpvlanModeL2,
+			flags:  IpvlanFlagBridge,
+		},
+		{
+			name:   "l3 mode with private flag",
+			index:  2802,
+			driver: &Ipvlan{Mode: ptr(IpvlanModeL3), Flags: ptr(IpvlanFlagPrivate)},
+			mode:   IpvlanModeL3,
+			flags:  IpvlanFlagPrivate,
+		},
+		{
+			name:   "l3s mode with vepa flag",
+			index:  2803,
+			driver: &Ipvlan{Mode: ptr(IpvlanModeL3S), Flags: ptr(IpvlanFlagVEPA)},
+			mode:   IpvlanModeL3S,
+			flags:  IpvlanFlagVEPA,
+		},
+		{
+			name:   "ipvtap in l2 mode",
+			index:  2804,
+			driver: &Ipvtap{Mode: ptr(IpvlanModeL2)},
+			mode:   IpvlanModeL2,
+			flags:  IpvlanFlagBridge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := setupInterface(connNS, "ipvln", tt.index, parentIndex, tt.driver); err != nil {
+				t.Fatalf("failed to create %s interface: %v", tt.driver.Kind(), err)
+			}
+			defer connNS.Link.Delete(tt.index)
+
+			got, err := getInterface(connNS, tt.index)
+			if err != nil {
+				t.Fatalf("failed to get interface: %v", err)
+			}
+
+			gotIpvlan := ipvlanT(got.Attributes.Info.Data)
+			if gotIpvlan == nil {
+				t.Fatalf("expected %s driver, got %T", tt.driver.Kind(), got.Attributes.Info.Data)
+			}
+
+			if gotIpvlan.Mode == nil {
+				t.Fatal("expected Mode, got nil")
+			}
+
+			if *gotIpvlan.Mode != tt.mode {
+				t.Errorf("expected mode %v (%s), got %v (%s)", tt.mode, tt.mode.String(), *gotIpvlan.Mode, gotIpvlan.Mode.String())
+			}
+
+			if gotIpvlan.Flags == nil {
+				t.Fatal("expected Flags, got nil")
+			}
+
+			if *gotIpvlan.Flags != tt.flags {
+				t.Errorf("expected flags %v (%s), got %v (%s)", tt.flags, tt.flags.String(), *gotIpvlan.Flags, gotIpvlan.Flags.String())
+			}
+		})
+	}
+}
+
+func TestIpvlanWithoutParent(t *testing.T) {
+	connNS, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer connNS.Close()
+
+	driver := &Ipvlan{Mode: ptr(IpvlanModeL3)}
+	err = connNS.Link.New(&rtnetlink.LinkMessage{
+		Family: unix.AF_UNSPEC,
+		Attributes: &rtnetlink.LinkAttributes{
+			Name: "ipvlnnp",
+			Info: &rtnetlink.LinkInfo{Kind: driver.Kind(), Data: driver},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error creating ipvlan without a parent link")
+	}
+
+	if want := "ipvlan requires a parent link"; err.Error() != want {
+		t.Fatalf("expected error %q, got %q", want, err.Error())
+	}
+}
@@ -0,0 +1,161 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/mdlayher/netlink"
+)
+
+func TestIpvlanEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		ipvlan *Ipvlan
+	}{
+		{
+			name:   "empty",
+			ipvlan: &Ipvlan{},
+		},
+		{
+			name: "l2 mode",
+			ipvlan: &Ipvlan{
+				Mode: ptr(IpvlanModeL2),
+			},
+		},
+		{
+			name: "l3 mode with private flag",
+			ipvlan: &Ipvlan{
+				Mode:  ptr(IpvlanModeL3),
+				Flags: ptr(IpvlanFlagPrivate),
+			},
+		},
+		{
+			name: "l3s mode with vepa flag",
+			ipvlan: &Ipvlan{
+				Mode:  ptr(IpvlanModeL3S),
+				Flags: ptr(IpvlanFlagVEPA),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, driver := range []rtnetlink.LinkDriver{tt.ipvlan, (*Ipvtap)(tt.ipvlan)} {
+				ae := netlink.NewAttributeEncoder()
+				if err := driver.Encode(ae); err != nil {
+					t.Fatalf("failed to encode: %v", err)
+				}
+
+				b, err := ae.Encode()
+				if err != nil {
+					t.Fatalf("failed to encode attributes: %v", err)
+				}
+
+				ad, err := netlink.NewAttributeDecoder(b)
+				if err != nil {
+					t.Fatalf("failed to create decoder: %v", err)
+				}
+
+				decoded := driver.New()
+				if err := decoded.Decode(ad); err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+
+				if diff := cmp.Diff(driver, decoded); diff != "" {
+					t.Fatalf("unexpected %s config (-want +got):\n%s", driver.Kind(), diff)
+				}
+			}
+		})
+	}
+}
+
+func TestIpvlanVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  rtnetlink.LinkDriverVerifier
+		msg     *rtnetlink.LinkMessage
+		wantErr string
+	}{
+		{
+			name:   "ipvlan with parent",
+			driver: &Ipvlan{Mode: ptr(IpvlanModeL3)},
+			msg:    &rtnetlink.LinkMessage{Attributes: &rtnetlink.LinkAttributes{Type: 1}},
+		},
+		{
+			name:   "change of existing ipvlan",
+			driver: &Ipvlan{Mode: ptr(IpvlanModeL3S), Flags: ptr(IpvlanFlagPrivate)},
+			msg: &rtnetlink.LinkMessage{
+				Index:      5,
+				Attributes: &rtnetlink.LinkAttributes{Info: &rtnetlink.LinkInfo{Kind: "ipvlan"}},
+			},
+		},
+		{
+			name:    "ipvlan without attributes",
+			driver:  &Ipvlan{},
+			msg:     &rtnetlink.LinkMessage{},
+			wantErr: "ipvlan requires a parent link",
+		},
+		{
+			name:    "ipvtap without parent",
+			driver:  &Ipvtap{},
+			msg:     &rtnetlink.LinkMessage{Attributes: &rtnetlink.LinkAttributes{}},
+			wantErr: "ipvtap requires a parent link",
+		},
+		{
+			name:    "private and vepa",
+			driver:  &Ipvlan{Flags: ptr(IpvlanFlagPrivate | IpvlanFlagVEPA)},
+			msg:     &rtnetlink.LinkMessage{Attributes: &rtnetlink.LinkAttributes{Type: 1}},
+			wantErr: "private and vepa flags are mutually exclusive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.driver.Verify(tt.msg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("failed to verify: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestIpvlanString(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{IpvlanModeL2.String(), "l2"},
+		{IpvlanModeL3.String(), "l3"},
+		{IpvlanModeL3S.String(), "l3s"},
+		{IpvlanMode(99).String(), "unknown IpvlanMode value (99)"},
+		{IpvlanFlagBridge.String(), "bridge"},
+		{IpvlanFlagPrivate.String(), "private"},
+		{IpvlanFlagVEPA.String(), "vepa"},
+		{IpvlanFlag(99).String(), "unknown IpvlanFlag value (99)"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, tt.got)
+		}
+	}
+}
+
+func TestIpvlanKind(t *testing.T) {
+	if kind := (&Ipvlan{}).Kind(); kind != "ipvlan" {
+		t.Errorf("expected kind %q, got %q", "ipvlan", kind)
+	}
+	if kind := (&Ipvtap{}).Kind(); kind != "ipvtap" {
+		t.Errorf("expected kind %q, got %q", "ipvtap", kind)
+	}
+}
@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// Matchall represents a matchall classifier (filter) configuration, which
+// matches every packet.
+type Matchall struct {
+	// ClassID is the class selected by the filter.
+	ClassID *uint32
+
+	// Flags specifies generic classifier flags (skip hw, skip sw).
+	Flags *TcClsFlag
+}
+
+var _ rtnetlink.TcFilterDriver = &Matchall{}
+
+// New creates a new Matchall instance.
+func (m *Matchall) New() rtnetlink.TcDriver {
+	return &Matchall{}
+}
+
+// Kind returns the matchall classifier kind.
+func (m *Matchall) Kind() string {
+	return "matchall"
+}
+
+// Filter marks the driver as a filter driver.
+func (m *Matchall) Filter() {}
+
+// Encode encodes the matchall classifier configuration into netlink attributes.
+func (m *Matchall) Encode(ae *netlink.AttributeEncoder) error {
+	if m.ClassID != nil {
+		ae.Uint32(unix.TCA_MATCHALL_CLASSID, *m.ClassID)
+	}
+
+	if m.Flags != nil {
+		ae.Uint32(unix.TCA_MATCHALL_FLAGS, uint32(*m.Flags))
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the matchall classifier configuration.
+func (m *Matchall) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_MATCHALL_CLASSID:
+			classID := ad.Uint32()
+			m.ClassID = &classID
+		case unix.TCA_MATCHALL_FLAGS:
+			flags := TcClsFlag(ad.Uint32())
+			m.Flags = &flags
+		}
+	}
+
+	return ad.Err()
+}
@@ -0,0 +1,274 @@
+package driver
+
+import (
+	"math"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+)
+
+const (
+	// sizeofNetemQopt is the size of struct tc_netem_qopt.
+	sizeofNetemQopt = 24
+
+	// sizeofNetemRate is the size of struct tc_netem_rate.
+	sizeofNetemRate = 16
+)
+
+// Netem represents a network emulator queueing discipline configuration.
+//
+// Probabilities are scaled to the uint32 range, math.MaxUint32 is 100%.
+type Netem struct {
+	// Limit is the maximum number of queued packets. Defaults to 1000.
+	Limit *uint32
+
+	// Latency is the added delay in nanoseconds.
+	Latency *int64
+
+	// Jitter is the random variation of Latency in nanoseconds.
+	Jitter *int64
+
+	// Loss is the probability of dropping a packet.
+	Loss *uint32
+
+	// Gap reorders every Gap-th packet, which is sent without Latency.
+	Gap *uint32
+
+	// Duplicate is the probability of duplicating a packet.
+	Duplicate *uint32
+
+	// Correlation contains the correlations of the random delay, loss and
+	// duplication with the previous packet.
+	Correlation *NetemCorrelation
+
+	// Reorder is the probability of sending a packet without Latency.
+	Reorder *NetemProbability
+
+	// Corrupt is the probability of corrupting a packet.
+	Corrupt *NetemProbability
+
+	// Rate limits the rate packets are sent at.
+	Rate *NetemRate
+
+	// ECN marks packets with ECN instead of dropping them when set to 1.
+	ECN *uint32
+}
+
+// NetemCorrelation contains the correlations of the random netem decisions
+// with the previous packet.
+type NetemCorrelation struct {
+	Delay     uint32
+	Loss      uint32
+	Duplicate uint32
+}
+
+// NetemProbability is a netem probability and its correlation with the
+// previous packet.
+type NetemProbability struct {
+	Probability uint32
+	Correlation uint32
+}
+
+// NetemRate is a netem rate limit.
+type NetemRate struct {
+	// Rate in bytes per second
+	Rate uint64
+
+	// Overhead added to every packet in bytes
+	PacketOverhead int32
+
+	// Size of link layer cells in bytes
+	CellSize uint32
+
+	// Overhead added to every cell in bytes
+	CellOverhead int32
+}
+
+var _ rtnetlink.TcRawDriver = &Netem{}
+
+// New creates a new Netem instance.
+func (n *Netem) New() rtnetlink.TcDriver {
+	return &Netem{}
+}
+
+// Kind returns the netem queueing discipline kind.
+func (n *Netem) Kind() string {
+	return "netem"
+}
+
+// MarshalBinary encodes the netem configuration into the TCA_OPTIONS payload.
+// Netem options are a struct tc_netem_qopt followed by netlink attributes.
+func (n *Netem) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofNetemQopt)
+	limit := uint32(1000)
+	if n.Limit != nil {
+		limit = *n.Limit
+	}
+	nlenc.PutUint32(b[4:8], limit)
+	if n.Loss != nil {
+		nlenc.PutUint32(b[8:12], *n.Loss)
+	}
+	if n.Gap != nil {
+		nlenc.PutUint32(b[12:16], *n.Gap)
+	}
+	if n.Duplicate != nil {
+		nlenc.PutUint32(b[16:20], *n.Duplicate)
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	if err := n.Encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary decodes the TCA_OPTIONS payload into the netem configuration.
+func (n *Netem) UnmarshalBinary(b []byte) error {
+	if len(b) < sizeofNetemQopt {
+		return errInvalidTcOptions
+	}
+
+	// The latency and jitter of the struct are in ticks and clamped, the
+	// kernel always reports them in nanoseconds as attributes as well.
+	limit := nlenc.Uint32(b[4:8])
+	n.Limit = &limit
+	loss := nlenc.Uint32(b[8:12])
+	n.Loss = &loss
+	gap := nlenc.Uint32(b[12:16])
+	n.Gap = &gap
+	duplicate := nlenc.Uint32(b[16:20])
+	n.Duplicate = &duplicate
+
+	ad, err := netlink.NewAttributeDecoder(b[sizeofNetemQopt:])
+	if err != nil {
+		return err
+	}
+
+	return n.Decode(ad)
+}
+
+// Encode encodes the netem attributes following the struct tc_netem_qopt.
+func (n *Netem) Encode(ae *netlink.AttributeEncoder) error {
+	if n.Correlation != nil {
+		b := make([]byte, 12)
+		nlenc.PutUint32(b[0:4], n.Correlation.Delay)
+		nlenc.PutUint32(b[4:8], n.Correlation.Loss)
+		nlenc.PutUint32(b[8:12], n.Correlation.Duplicate)
+		ae.Bytes(unix.TCA_NETEM_CORR, b)
+	}
+
+	if n.Reorder != nil {
+		ae.Bytes(unix.TCA_NETEM_REORDER, n.Reorder.encode())
+	}
+
+	if n.Corrupt != nil {
+		ae.Bytes(unix.TCA_NETEM_CORRUPT, n.Corrupt.encode())
+	}
+
+	if n.Rate != nil {
+		b := make([]byte, sizeofNetemRate)
+		nlenc.PutUint32(b[0:4], uint32(min(n.Rate.Rate, math.MaxUint32)))
+		nlenc.PutInt32(b[4:8], n.Rate.PacketOverhead)
+		nlenc.PutUint32(b[8:12], n.Rate.CellSize)
+		nlenc.PutInt32(b[12:16], n.Rate.CellOverhead)
+		ae.Bytes(unix.TCA_NETEM_RATE, b)
+
+		if n.Rate.Rate > math.MaxUint32 {
+			ae.Uint64(unix.TCA_NETEM_RATE64, n.Rate.Rate)
+		}
+	}
+
+	if n.ECN != nil {
+		ae.Uint32(unix.TCA_NETEM_ECN, *n.ECN)
+	}
+
+	if n.Latency != nil {
+		ae.Int64(unix.TCA_NETEM_LATENCY64, *n.Latency)
+	}
+
+	if n.Jitter != nil {
+		ae.Int64(unix.TCA_NETEM_JITTER64, *n.Jitter)
+	}
+
+	return nil
+}
+
+// Decode decodes the netem attributes following the struct tc_netem_qopt.
+func (n *Netem) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_NETEM_CORR:
+			b := ad.Bytes()
+			if len(b) < 12 {
+				return errInvalidTcOptions
+			}
+			n.Correlation = &NetemCorrelation{
+				Delay:     nlenc.Uint32(b[0:4]),
+				Loss:      nlenc.Uint32(b[4:8]),
+				Duplicate: nlenc.Uint32(b[8:12]),
+			}
+		case unix.TCA_NETEM_REORDER:
+			n.Reorder = &NetemProbability{}
+			ad.Do(n.Reorder.decode)
+		case unix.TCA_NETEM_CORRUPT:
+			n.Corrupt = &NetemProbability{}
+			ad.Do(n.Corrupt.decode)
+		case unix.TCA_NETEM_RATE:
+			b := ad.Bytes()
+			if len(b) < sizeofNetemRate {
+				return errInvalidTcOptions
+			}
+			if n.Rate == nil {
+				n.Rate = &NetemRate{}
+			}
+			// TCA_NETEM_RATE64 follows and overrides the clamped rate.
+			if n.Rate.Rate == 0 {
+				n.Rate.Rate = uint64(nlenc.Uint32(b[0:4]))
+			}
+			n.Rate.PacketOverhead = nlenc.Int32(b[4:8])
+			n.Rate.CellSize = nlenc.Uint32(b[8:12])
+			n.Rate.CellOverhead = nlenc.Int32(b[12:16])
+		case unix.TCA_NETEM_RATE64:
+			if n.Rate == nil {
+				n.Rate = &NetemRate{}
+			}
+			n.Rate.Rate = ad.Uint64()
+		case unix.TCA_NETEM_ECN:
+			ecn := ad.Uint32()
+			n.ECN = &ecn
+		case unix.TCA_NETEM_LATENCY64:
+			latency := ad.Int64()
+			n.Latency = &latency
+		case unix.TCA_NETEM_JITTER64:
+			jitter := ad.Int64()
+			n.Jitter = &jitter
+		}
+	}
+
+	return ad.Err()
+}
+
+func (p *NetemProbability) encode() []byte {
+	b := make([]byte, 8)
+	nlenc.PutUint32(b[0:4], p.Probability)
+	nlenc.PutUint32(b[4:8], p.Correlation)
+	return b
+}
+
+func (p *NetemProbability) decode(b []byte) error {
+	if len(b) < 8 {
+		return errInvalidTcOptions
+	}
+	p.Probability = nlenc.Uint32(b[0:4])
+	p.Correlation = nlenc.Uint32(b[4:8])
+	return nil
+}
@@ -53,11 +53,38 @@ const (
 	NetkitPolicyDrop NetkitPolicy = unix.NETKIT_DROP
 )
 
+// NetkitScrub specifies how much of the packet metadata is scrubbed when
+// crossing the netkit pair
+type NetkitScrub uint32
+
+func (n NetkitScrub) String() string {
+	switch n {
+	case NetkitScrubNone:
+		return "none"
+	case NetkitScrubDefault:
+		return "default"
+	default:
+		return fmt.Sprintf("unknown NetkitScrub value (%d)", n)
+	}
+}
+
+const (
+	// Packet metadata such as the mark and priority is kept
+	NetkitScrubNone NetkitScrub = unix.NETKIT_SCRUB_NONE
+
+	// Packet metadata is scrubbed as on veth, this is the default
+	NetkitScrubDefault NetkitScrub = unix.NETKIT_SCRUB_DEFAULT
+)
+
 // Netkit implements LinkDriverVerifier for the netkit driver
 type Netkit struct {
 	Mode       *NetkitMode            // Specifies driver operation mode
 	Policy     *NetkitPolicy          // Specifies default policy
 	PeerPolicy *NetkitPolicy          // Specifies default peer policy
+	Scrub      *NetkitScrub           // Specifies scrubbing of packets sent by the primary
+	PeerScrub  *NetkitScrub           // Specifies scrubbing of packets sent by the peer
+	Headroom   *uint16                // Specifies needed headroom, can only be set on creation
+	Tailroom   *uint16                // Specifies needed tailroom, can only be set on creation
 	Primary    bool                   // Shows primary link
 	PeerInfo   *rtnetlink.LinkMessage // Specifies peer link information
 }
@@ -89,6 +116,18 @@ func (n *Netkit) Decode(ad *netlink.AttributeDecoder) error {
 			n.PeerPolicy = &v
 		case unix.IFLA_NETKIT_PRIMARY:
 			n.Primary = ad.Uint8() != 0
+		case unix.IFLA_NETKIT_SCRUB:
+			v := NetkitScrub(ad.Uint32())
+			n.Scrub = &v
+		case unix.IFLA_NETKIT_PEER_SCRUB:
+			v := NetkitScrub(ad.Uint32())
+			n.PeerScrub = &v
+		case unix.IFLA_NETKIT_HEADROOM:
+			v := ad.Uint16()
+			n.Headroom = &v
+		case unix.IFLA_NETKIT_TAILROOM:
+			v := ad.Uint16()
+			n.Tailroom = &v
 		}
 	}
 	return nil
@@ -104,6 +143,18 @@ func (n *Netkit) Encode(ae *netlink.AttributeEncoder) error {
 	if n.PeerPolicy != nil {
 		ae.Int32(unix.IFLA_NETKIT_PEER_POLICY, int32(*n.PeerPolicy))
 	}
+	if n.Scrub != nil {
+		ae.Uint32(unix.IFLA_NETKIT_SCRUB, uint32(*n.Scrub))
+	}
+	if n.PeerScrub != nil {
+		ae.Uint32(unix.IFLA_NETKIT_PEER_SCRUB, uint32(*n.PeerScrub))
+	}
+	if n.Headroom != nil {
+		ae.Uint16(unix.IFLA_NETKIT_HEADROOM, *n.Headroom)
+	}
+	if n.Tailroom != nil {
+		ae.Uint16(unix.IFLA_NETKIT_TAILROOM, *n.Tailroom)
+	}
 	if n.PeerInfo != nil {
 		b, err := n.PeerInfo.MarshalBinary()
 		if err != nil {
@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+)
+
+// NetkitAttach specifies the device of a netkit pair an eBPF program is attached to
+type NetkitAttach uint8
+
+func (n NetkitAttach) String() string {
+	switch n {
+	case NetkitAttachPrimary:
+		return "primary"
+	case NetkitAttachPeer:
+		return "peer"
+	default:
+		return fmt.Sprintf("unknown NetkitAttach value (%d)", n)
+	}
+}
+
+const (
+	// Program runs for packets sent by the primary device
+	NetkitAttachPrimary NetkitAttach = iota
+
+	// Program runs for packets sent by the peer device
+	NetkitAttachPeer
+)
+
+func (n NetkitAttach) attachType() (ebpf.AttachType, error) {
+	switch n {
+	case NetkitAttachPrimary:
+		return ebpf.AttachNetkitPrimary, nil
+	case NetkitAttachPeer:
+		return ebpf.AttachNetkitPeer, nil
+	default:
+		return 0, fmt.Errorf("invalid netkit attach point: %s", n)
+	}
+}
+
+// AttachNetkitProgram attaches an eBPF program of type ebpf.SchedCLS to the
+// primary or peer device of the netkit pair whose primary device has the given
+// index. Programs of both devices are attached through the primary device,
+// which has to be in the network namespace of the caller.
+//
+// The optional anchor positions the program relative to already attached
+// programs, it is appended when nil. The program stays attached until the
+// returned link is closed, unless the link is pinned.
+func AttachNetkitProgram(index uint32, prog *ebpf.Program, attach NetkitAttach, anchor link.Anchor) (link.Link, error) {
+	typ, err := attach.attachType()
+	if err != nil {
+		return nil, err
+	}
+
+	return link.AttachNetkit(link.NetkitOptions{
+		Interface: int(index),
+		Program:   prog,
+		Attach:    typ,
+		Anchor:    anchor,
+	})
+}
+
+// NetkitPrograms returns the IDs of the eBPF programs attached to the primary
+// or peer device of the netkit pair whose primary device has the given index,
+// in the order they run.
+func NetkitPrograms(index uint32, attach NetkitAttach) ([]ebpf.ProgramID, error) {
+	typ, err := attach.attachType()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := link.QueryPrograms(link.QueryOptions{
+		Target: int(index),
+		Attach: typ,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]ebpf.ProgramID, 0, len(res.Programs))
+	for _, p := range res.Programs {
+		ids = append(ids, p.ID)
+	}
+
+	return ids, nil
+}
@@ -6,7 +6,10 @@ package driver
 import (
 	"testing"
 
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/jsimonetti/rtnetlink/v2"
 	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
 	"github.com/mdlayher/netlink"
@@ -121,6 +124,9 @@ func TestNetkit(t *testing.T) {
 		},
 	}
 
+	// Newer kernels always report scrubbing and head/tailroom, see TestNetkitScrub.
+	ignoreRoom := cmpopts.IgnoreFields(Netkit{}, "Scrub", "PeerScrub", "Headroom", "Tailroom")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if err := setupInterface(conn, tt.linkName, ifIndex, 0, tt.driver); err != nil {
@@ -132,7 +138,7 @@ func TestNetkit(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to get primary netkit interface: %v", err)
 			}
-			if diff := cmp.Diff(tt.primary, msg.Attributes.Info.Data); diff != "" {
+			if diff := cmp.Diff(tt.primary, msg.Attributes.Info.Data, ignoreRoom); diff != "" {
 				t.Error(diff)
 			}
 
@@ -140,9 +146,119 @@ func TestNetkit(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to get peer netkit interface: %v", err)
 			}
-			if diff := cmp.Diff(tt.peer, msg.Attributes.Info.Data); diff != "" {
+			if diff := cmp.Diff(tt.peer, msg.Attributes.Info.Data, ignoreRoom); diff != "" {
 				t.Error(diff)
 			}
 		})
 	}
 }
+
+func TestNetkitScrub(t *testing.T) {
+	testutils.SkipOnOldKernel(t, "6.14", "netkit headroom and tailroom")
+
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	const (
+		ifIndex     = 1021
+		ifPeerIndex = 1022
+	)
+
+	driver := &Netkit{
+		Scrub:     ptr(NetkitScrubNone),
+		PeerScrub: ptr(NetkitScrubDefault),
+		Headroom:  ptr(uint16(64)),
+		Tailroom:  ptr(uint16(32)),
+		PeerInfo:  &rtnetlink.LinkMessage{Index: ifPeerIndex},
+	}
+	if err := setupInterface(conn, "nkp", ifIndex, 0, driver); err != nil {
+		t.Fatalf("failed to setup netkit interface: %v", err)
+	}
+	defer conn.Link.Delete(ifIndex)
+
+	msg, err := getInterface(conn, ifIndex)
+	if err != nil {
+		t.Fatalf("failed to get primary netkit interface: %v", err)
+	}
+	got := msg.Attributes.Info.Data.(*Netkit)
+	if diff := cmp.Diff(driver.Scrub, got.Scrub); diff != "" {
+		t.Errorf("unexpected scrub (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(driver.PeerScrub, got.PeerScrub); diff != "" {
+		t.Errorf("unexpected peer scrub (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(driver.Headroom, got.Headroom); diff != "" {
+		t.Errorf("unexpected headroom (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(driver.Tailroom, got.Tailroom); diff != "" {
+		t.Errorf("unexpected tailroom (-want +got):\n%s", diff)
+	}
+}
+
+func TestNetkitProgram(t *testing.T) {
+	testutils.SkipOnOldKernel(t, "6.7", "netkit support")
+
+	conn, err := rtnetlink.Dial(nil)
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	const (
+		ifIndex     = 1031
+		ifPeerIndex = 1032
+	)
+
+	driver := &Netkit{PeerInfo: &rtnetlink.LinkMessage{Index: ifPeerIndex}}
+	if err := setupInterface(conn, "nkp", ifIndex, 0, driver); err != nil {
+		t.Fatalf("failed to setup netkit interface: %v", err)
+	}
+	defer conn.Link.Delete(ifIndex)
+
+	// Return NETKIT_PASS.
+	prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+		Type: ebpf.SchedCLS,
+		Instructions: asm.Instructions{
+			asm.LoadImm(asm.R0, 0, asm.DWord),
+			asm.Return(),
+		},
+		License: "MIT",
+	})
+	if err != nil {
+		t.Fatalf("failed to load bpf program: %v", err)
+	}
+	defer prog.Close()
+
+	info, err := prog.Info()
+	if err != nil {
+		t.Fatalf("failed to get program info: %v", err)
+	}
+	id, _ := info.ID()
+
+	for _, attach := range []NetkitAttach{NetkitAttachPrimary, NetkitAttachPeer} {
+		t.Run(attach.String(), func(t *testing.T) {
+			l, err := AttachNetkitProgram(ifIndex, prog, attach, nil)
+			if err != nil {
+				t.Fatalf("failed to attach program: %v", err)
+			}
+
+			ids, err := NetkitPrograms(ifIndex, attach)
+			if err != nil {
+				t.Fatalf("failed to query programs: %v", err)
+			}
+			if diff := cmp.Diff([]ebpf.ProgramID{id}, ids); diff != "" {
+				t.Fatalf("unexpected programs (-want +got):\n%s", diff)
+			}
+
+			if err := l.Close(); err != nil {
+				t.Fatalf("failed to detach program: %v", err)
+			}
+			if ids, err = NetkitPrograms(ifIndex, attach); err != nil || len(ids) != 0 {
+				t.Fatalf("expected no programs, got %v: %v", ids, err)
+			}
+		})
+	}
+}
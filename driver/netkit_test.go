@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mdlayher/netlink"
+)
+
+func TestNetkitEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		netkit *Netkit
+	}{
+		{
+			name:   "minimal configuration",
+			netkit: &Netkit{},
+		},
+		{
+			name: "full configuration",
+			netkit: &Netkit{
+				Mode:       ptr(NetkitModeL2),
+				Policy:     ptr(NetkitPolicyPass),
+				PeerPolicy: ptr(NetkitPolicyDrop),
+				Scrub:      ptr(NetkitScrubNone),
+				PeerScrub:  ptr(NetkitScrubDefault),
+				Headroom:   ptr(uint16(64)),
+				Tailroom:   ptr(uint16(32)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Encode
+			ae := netlink.NewAttributeEncoder()
+			if err := tt.netkit.Encode(ae); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+			b, err := ae.Encode()
+			if err != nil {
+				t.Fatalf("failed to encode attributes: %v", err)
+			}
+
+			// Decode
+			ad, err := netlink.NewAttributeDecoder(b)
+			if err != nil {
+				t.Fatalf("failed to create decoder: %v", err)
+			}
+
+			decoded := &Netkit{}
+			if err := decoded.Decode(ad); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			// Compare
+			if diff := cmp.Diff(tt.netkit, decoded); diff != "" {
+				t.Fatalf("unexpected netkit (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package driver
+
+import (
+	"math"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+)
+
+// sizeofTbfQopt is the size of struct tc_tbf_qopt.
+const sizeofTbfQopt = 2*sizeofRatespec + 12
+
+// Tbf represents a token bucket filter queueing discipline configuration.
+type Tbf struct {
+	// Rate is the rate in bytes per second.
+	Rate *uint64
+
+	// PeakRate is the maximum rate in bytes per second.
+	PeakRate *uint64
+
+	// Limit is the number of bytes that can be queued.
+	Limit *uint32
+
+	// Buffer is the size of the bucket in ticks of 64 nanoseconds.
+	// It is overridden by Burst.
+	Buffer *uint32
+
+	// Mtu is the size of the peak rate bucket in ticks of 64 nanoseconds.
+	// It is overridden by PeakBurst.
+	Mtu *uint32
+
+	// Burst is the size of the bucket in bytes.
+	// It is not reported by the kernel, see Buffer.
+	Burst *uint32
+
+	// PeakBurst is the size of the peak rate bucket in bytes.
+	// It is not reported by the kernel, see Mtu.
+	PeakBurst *uint32
+}
+
+var _ rtnetlink.TcDriver = &Tbf{}
+
+// New creates a new Tbf instance.
+func (t *Tbf) New() rtnetlink.TcDriver {
+	return &Tbf{}
+}
+
+// Kind returns the TBF queueing discipline kind.
+func (t *Tbf) Kind() string {
+	return "tbf"
+}
+
+// Encode encodes the TBF configuration into netlink attributes.
+func (t *Tbf) Encode(ae *netlink.AttributeEncoder) error {
+	b := make([]byte, sizeofTbfQopt)
+	if t.Rate != nil {
+		putRatespec(b[0:12], *t.Rate)
+	}
+	if t.PeakRate != nil {
+		putRatespec(b[12:24], *t.PeakRate)
+	}
+	if t.Limit != nil {
+		nlenc.PutUint32(b[24:28], *t.Limit)
+	}
+	if t.Buffer != nil {
+		nlenc.PutUint32(b[28:32], *t.Buffer)
+	}
+	if t.Mtu != nil {
+		nlenc.PutUint32(b[32:36], *t.Mtu)
+	}
+	ae.Bytes(unix.TCA_TBF_PARMS, b)
+
+	if t.Rate != nil && *t.Rate > math.MaxUint32 {
+		ae.Uint64(unix.TCA_TBF_RATE64, *t.Rate)
+	}
+
+	if t.PeakRate != nil && *t.PeakRate > math.MaxUint32 {
+		ae.Uint64(unix.TCA_TBF_PRATE64, *t.PeakRate)
+	}
+
+	if t.Burst != nil {
+		ae.Uint32(unix.TCA_TBF_BURST, *t.Burst)
+	}
+
+	if t.PeakBurst != nil {
+		ae.Uint32(unix.TCA_TBF_PBURST, *t.PeakBurst)
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the TBF configuration.
+func (t *Tbf) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_TBF_PARMS:
+			b := ad.Bytes()
+			if len(b) < sizeofTbfQopt {
+				return errInvalidTcOptions
+			}
+			// The 64 bit attributes follow and override the clamped rates.
+			if t.Rate == nil {
+				rate := ratespecRate(b[0:12])
+				t.Rate = &rate
+			}
+			if t.PeakRate == nil && ratespecRate(b[12:24]) != 0 {
+				prate := ratespecRate(b[12:24])
+				t.PeakRate = &prate
+			}
+			limit := nlenc.Uint32(b[24:28])
+			t.Limit = &limit
+			buffer := nlenc.Uint32(b[28:32])
+			t.Buffer = &buffer
+			mtu := nlenc.Uint32(b[32:36])
+			t.Mtu = &mtu
+		case unix.TCA_TBF_RATE64:
+			rate := ad.Uint64()
+			t.Rate = &rate
+		case unix.TCA_TBF_PRATE64:
+			prate := ad.Uint64()
+			t.PeakRate = &prate
+		case unix.TCA_TBF_BURST:
+			burst := ad.Uint32()
+			t.Burst = &burst
+		case unix.TCA_TBF_PBURST:
+			pburst := ad.Uint32()
+			t.PeakBurst = &pburst
+		}
+	}
+
+	return ad.Err()
+}
@@ -0,0 +1,31 @@
+package driver
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink/nlenc"
+)
+
+// errInvalidTcOptions is returned when the options of a traffic control
+// object are too short to contain the expected structure.
+var errInvalidTcOptions = errors.New("traffic control options are invalid or too short")
+
+// sizeofRatespec is the size of struct tc_ratespec.
+const sizeofRatespec = 12
+
+// putRatespec encodes rate (in bytes per second) as a struct tc_ratespec into b.
+// The link layer is set to ethernet so the kernel computes transmission times
+// itself and no rate table has to be sent along. Rates which do not fit into
+// 32 bits are clamped and have to be sent in a separate 64 bit attribute.
+func putRatespec(b []byte, rate uint64) {
+	b[1] = unix.TC_LINKLAYER_ETHERNET
+	nlenc.PutUint32(b[8:12], uint32(min(rate, math.MaxUint32)))
+}
+
+// ratespecRate returns the rate of the struct tc_ratespec in b.
+func ratespecRate(b []byte) uint64 {
+	return uint64(nlenc.Uint32(b[8:12]))
+}
@@ -0,0 +1,303 @@
+//go:build integration
+// +build integration
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestTcHtb(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	// lo is always index 1 in a fresh network namespace.
+	const lo = 1
+
+	qdisc := &rtnetlink.TcMessage{
+		Index:  lo,
+		Handle: rtnetlink.TcHandle(1, 0),
+		Parent: rtnetlink.TcHandleRoot,
+		Attributes: &rtnetlink.TcAttributes{
+			Kind:    "htb",
+			Options: &Htb{DefaultClass: ptr(uint32(0x10))},
+		},
+	}
+	if err := conn.Qdisc.Add(qdisc); err != nil {
+		t.Fatalf("failed to add htb qdisc: %v", err)
+	}
+
+	class := &rtnetlink.TcMessage{
+		Index:  lo,
+		Handle: rtnetlink.TcHandle(1, 0x10),
+		Parent: rtnetlink.TcHandle(1, 0),
+		Attributes: &rtnetlink.TcAttributes{
+			Kind: "htb",
+			Options: &HtbClass{
+				Rate:    ptr(uint64(125000)),
+				Ceil:    ptr(uint64(10000000000)),
+				Buffer:  ptr(uint32(200000)),
+				Cbuffer: ptr(uint32(2000)),
+				Prio:    ptr(uint32(1)),
+			},
+		},
+	}
+	if err := conn.Class.Add(class); err != nil {
+		t.Fatalf("failed to add htb class: %v", err)
+	}
+
+	qdiscs, err := conn.Qdisc.List()
+	if err != nil {
+		t.Fatalf("failed to list qdiscs: %v", err)
+	}
+	var htb *Htb
+	for _, q := range qdiscs {
+		if q.Index == lo && q.Handle == qdisc.Handle {
+			htb, _ = q.Attributes.Options.(*Htb)
+		}
+	}
+	if htb == nil {
+		t.Fatalf("htb qdisc not found in %v", qdiscs)
+	}
+	if want, got := uint32(0x10), *htb.DefaultClass; want != got {
+		t.Fatalf("unexpected default class: want %#x, got %#x", want, got)
+	}
+
+	classes, err := conn.Class.List(lo)
+	if err != nil {
+		t.Fatalf("failed to list classes: %v", err)
+	}
+	var got *HtbClass
+	for _, c := range classes {
+		if c.Handle == class.Handle {
+			got, _ = c.Attributes.Options.(*HtbClass)
+			if c.Attributes.Stats == nil {
+				t.Fatal("expected class statistics")
+			}
+		}
+	}
+	if got == nil {
+		t.Fatalf("htb class not found in %v", classes)
+	}
+	want := class.Attributes.Options.(*HtbClass)
+	if diff := cmp.Diff(want.Rate, got.Rate); diff != "" {
+		t.Fatalf("unexpected rate (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.Ceil, got.Ceil); diff != "" {
+		t.Fatalf("unexpected ceil (-want +got):\n%s", diff)
+	}
+
+	// A class without bursts gets the default bursts of tc.
+	defaults := &rtnetlink.TcMessage{
+		Index:  lo,
+		Handle: rtnetlink.TcHandle(1, 0x20),
+		Parent: rtnetlink.TcHandle(1, 0),
+		Attributes: &rtnetlink.TcAttributes{
+			Kind:    "htb",
+			Options: &HtbClass{Rate: ptr(uint64(125000))},
+		},
+	}
+	if err := conn.Class.Add(defaults); err != nil {
+		t.Fatalf("failed to add htb class: %v", err)
+	}
+	classes, err = conn.Class.List(lo)
+	if err != nil {
+		t.Fatalf("failed to list classes: %v", err)
+	}
+	for _, c := range classes {
+		if c.Handle != defaults.Handle {
+			continue
+		}
+		got, _ := c.Attributes.Options.(*HtbClass)
+		if got == nil || got.Buffer == nil || *got.Buffer == 0 || got.Cbuffer == nil || *got.Cbuffer == 0 {
+			t.Fatalf("expected default bursts: %+v", got)
+		}
+	}
+	if err := conn.Class.Delete(defaults); err != nil {
+		t.Fatalf("failed to delete htb class: %v", err)
+	}
+
+	if err := conn.Class.Delete(class); err != nil {
+		t.Fatalf("failed to delete htb class: %v", err)
+	}
+	if err := conn.Qdisc.Delete(qdisc); err != nil {
+		t.Fatalf("failed to delete htb qdisc: %v", err)
+	}
+}
+
+func TestTcClsact(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	const lo = 1
+
+	qdisc := &rtnetlink.TcMessage{
+		Index:  lo,
+		Handle: rtnetlink.TcHandle(0xffff, 0),
+		Parent: rtnetlink.TcHandleClsact,
+		Attributes: &rtnetlink.TcAttributes{
+			Kind:    "clsact",
+			Options: &Clsact{},
+		},
+	}
+	if err := conn.Qdisc.Replace(qdisc); err != nil {
+		t.Fatalf("failed to add clsact qdisc: %v", err)
+	}
+
+	// Return TC_ACT_OK in direct-action mode.
+	prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+		Type: ebpf.SchedCLS,
+		Instructions: asm.Instructions{
+			asm.LoadImm(asm.R0, 0, asm.DWord),
+			asm.Return(),
+		},
+		License: "MIT",
+	})
+	if err != nil {
+		t.Fatalf("failed to load bpf program: %v", err)
+	}
+	defer prog.Close()
+
+	ingress := rtnetlink.TcHandle(0xffff, rtnetlink.TcHandleMinIngress)
+	filters := []*rtnetlink.TcMessage{
+		{
+			Index:  lo,
+			Handle: 1,
+			Parent: ingress,
+			Info:   rtnetlink.TcFilterInfo(1, unix.ETH_P_ALL),
+			Attributes: &rtnetlink.TcAttributes{
+				Kind: "bpf",
+				Options: &Bpf{
+					FD:    ptr(uint32(prog.FD())),
+					Name:  ptr("pass"),
+					Flags: ptr(BpfFlagActDirect),
+				},
+			},
+		},
+		{
+			Index:  lo,
+			Parent: ingress,
+			Info:   rtnetlink.TcFilterInfo(2, unix.ETH_P_IP),
+			Attributes: &rtnetlink.TcAttributes{
+				Kind: "u32",
+				Options: &U32{
+					ClassID: ptr(uint32(0x10010)),
+					Sel: &U32Sel{
+						Flags: U32SelFlagTerminal,
+						Keys: []U32Key{
+							// UDP destination port 53, assuming no IP options
+							{Mask: 0x0000ffff, Val: 53, Off: 20},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, f := range filters {
+		if err := conn.Filter.Add(f); err != nil {
+			t.Fatalf("failed to add %s filter: %v", f.Attributes.Kind, err)
+		}
+	}
+
+	list, err := conn.Filter.List(lo, ingress)
+	if err != nil {
+		t.Fatalf("failed to list filters: %v", err)
+	}
+
+	var bpf *Bpf
+	var u32 *U32
+	for _, f := range list {
+		switch o := f.Attributes.Options.(type) {
+		case *Bpf:
+			bpf = o
+		case *U32:
+			// u32 reports its hash tables as filters without a selector.
+			if o.Sel != nil {
+				u32 = o
+			}
+		}
+	}
+	if bpf == nil || *bpf.Name != "pass" || *bpf.Flags != BpfFlagActDirect || bpf.ID == nil {
+		t.Fatalf("unexpected bpf filter: %#v", bpf)
+	}
+	if u32 == nil || *u32.ClassID != 0x10010 {
+		t.Fatalf("unexpected u32 filter: %#v", u32)
+	}
+	if diff := cmp.Diff(filters[1].Attributes.Options.(*U32).Sel.Keys, u32.Sel.Keys); diff != "" {
+		t.Fatalf("unexpected u32 keys (-want +got):\n%s", diff)
+	}
+
+	// Deleting without handle and info removes all filters of the parent.
+	if err := conn.Filter.Delete(&rtnetlink.TcMessage{Index: lo, Parent: ingress}); err != nil {
+		t.Fatalf("failed to delete filters: %v", err)
+	}
+	if list, err = conn.Filter.List(lo, ingress); err != nil || len(list) != 0 {
+		t.Fatalf("expected no filters, got %d: %v", len(list), err)
+	}
+
+	if err := conn.Qdisc.Delete(qdisc); err != nil {
+		t.Fatalf("failed to delete clsact qdisc: %v", err)
+	}
+}
+
+func TestTcTbf(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	const lo = 1
+
+	qdisc := &rtnetlink.TcMessage{
+		Index:  lo,
+		Handle: rtnetlink.TcHandle(1, 0),
+		Parent: rtnetlink.TcHandleRoot,
+		Attributes: &rtnetlink.TcAttributes{
+			Kind: "tbf",
+			Options: &Tbf{
+				Rate:  ptr(uint64(10000000000)),
+				Limit: ptr(uint32(30000)),
+				Burst: ptr(uint32(10000)),
+			},
+		},
+	}
+	if err := conn.Qdisc.Replace(qdisc); err != nil {
+		t.Fatalf("failed to add tbf qdisc: %v", err)
+	}
+
+	qdiscs, err := conn.Qdisc.List()
+	if err != nil {
+		t.Fatalf("failed to list qdiscs: %v", err)
+	}
+	var got *Tbf
+	for _, q := range qdiscs {
+		if q.Index == lo && q.Handle == qdisc.Handle {
+			got, _ = q.Attributes.Options.(*Tbf)
+		}
+	}
+	if got == nil {
+		t.Fatalf("tbf qdisc not found in %v", qdiscs)
+	}
+	if *got.Rate != 10000000000 || *got.Limit != 30000 || *got.Buffer == 0 {
+		t.Fatalf("unexpected tbf qdisc: %#v", got)
+	}
+
+	if err := conn.Qdisc.Delete(qdisc); err != nil {
+		t.Fatalf("failed to delete tbf qdisc: %v", err)
+	}
+}
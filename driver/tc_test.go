@@ -0,0 +1,284 @@
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestTcDriverEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver rtnetlink.TcDriver
+	}{
+		{
+			name: "htb",
+			driver: &Htb{
+				Rate2Quantum:  ptr(uint32(10)),
+				DefaultClass:  ptr(uint32(0x10)),
+				DirectPackets: ptr(uint32(0)),
+				DirectQlen:    ptr(uint32(1000)),
+			},
+		},
+		{
+			name: "htb class",
+			driver: &HtbClass{
+				Rate:    ptr(uint64(125000)),
+				Ceil:    ptr(uint64(10000000000)),
+				Buffer:  ptr(uint32(200000)),
+				Cbuffer: ptr(uint32(1600)),
+				Quantum: ptr(uint32(1500)),
+				Prio:    ptr(uint32(1)),
+				Level:   ptr(uint32(0)),
+			},
+		},
+		{
+			name: "fq",
+			driver: &Fq{
+				PacketLimit:     ptr(uint32(10000)),
+				FlowPacketLimit: ptr(uint32(100)),
+				Quantum:         ptr(uint32(3028)),
+				InitialQuantum:  ptr(uint32(15140)),
+				RateEnable:      ptr(uint32(1)),
+				FlowMaxRate:     ptr(uint32(1000000)),
+				BucketsLog:      ptr(uint32(10)),
+				CEThreshold:     ptr(uint32(1000)),
+				Horizon:         ptr(uint32(10000000)),
+				HorizonDrop:     ptr(uint8(1)),
+			},
+		},
+		{
+			name: "fq_codel",
+			driver: &FqCodel{
+				Target:        ptr(uint32(5000)),
+				Limit:         ptr(uint32(10240)),
+				Interval:      ptr(uint32(100000)),
+				ECN:           ptr(uint32(1)),
+				Flows:         ptr(uint32(1024)),
+				Quantum:       ptr(uint32(1514)),
+				DropBatchSize: ptr(uint32(64)),
+				MemoryLimit:   ptr(uint32(33554432)),
+			},
+		},
+		{
+			name: "tbf",
+			driver: &Tbf{
+				Rate:      ptr(uint64(10000000000)),
+				PeakRate:  ptr(uint64(250000)),
+				Limit:     ptr(uint32(30000)),
+				Buffer:    ptr(uint32(1000)),
+				Mtu:       ptr(uint32(100)),
+				Burst:     ptr(uint32(10000)),
+				PeakBurst: ptr(uint32(1514)),
+			},
+		},
+		{
+			name:   "clsact",
+			driver: &Clsact{},
+		},
+		{
+			name:   "ingress",
+			driver: &Ingress{},
+		},
+		{
+			name: "bpf",
+			driver: &Bpf{
+				FD:       ptr(uint32(10)),
+				Name:     ptr("prog"),
+				ClassID:  ptr(uint32(0x10010)),
+				Flags:    ptr(BpfFlagActDirect),
+				FlagsGen: ptr(TcClsFlagSkipHw),
+			},
+		},
+		{
+			name: "u32",
+			driver: &U32{
+				ClassID: ptr(uint32(0x10010)),
+				Sel: &U32Sel{
+					Flags: U32SelFlagTerminal,
+					Hmask: 0xff000000,
+					Keys: []U32Key{
+						{Mask: 0xffffff00, Val: 0xc0000200, Off: 16},
+						{Mask: 0x0000ffff, Val: 0x00000050, Off: 20, Offmask: 0},
+					},
+				},
+				Indev: ptr("eth0"),
+				Flags: ptr(TcClsFlagSkipHw),
+			},
+		},
+		{
+			name: "flower",
+			driver: &Flower{
+				ClassID:    ptr(uint32(0x10010)),
+				EthDst:     net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				EthDstMask: net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+				EthType:    ptr(uint16(unix.ETH_P_IP)),
+				IPProto:    ptr(uint8(6)),
+				Src:        net.IPv4(192, 0, 2, 0).To4(),
+				SrcMask:    net.CIDRMask(24, 32),
+				Dst:        net.ParseIP("2001:db8::1"),
+				DstMask:    net.CIDRMask(64, 128),
+				TCPDst:     ptr(uint16(443)),
+				VlanID:     ptr(uint16(100)),
+				VlanPrio:   ptr(uint8(3)),
+				Flags:      ptr(TcClsFlagSkipHw),
+			},
+		},
+		{
+			name: "matchall",
+			driver: &Matchall{
+				ClassID: ptr(uint32(0x10010)),
+				Flags:   ptr(TcClsFlagSkipSw),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Encode
+			ae := netlink.NewAttributeEncoder()
+			if err := tt.driver.Encode(ae); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+			b, err := ae.Encode()
+			if err != nil {
+				t.Fatalf("failed to encode attributes: %v", err)
+			}
+
+			// Decode
+			ad, err := netlink.NewAttributeDecoder(b)
+			if err != nil {
+				t.Fatalf("failed to create decoder: %v", err)
+			}
+
+			decoded := tt.driver.New()
+			if err := decoded.Decode(ad); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			// Compare
+			if diff := cmp.Diff(tt.driver, decoded); diff != "" {
+				t.Fatalf("unexpected %s (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestNetemMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		netem *Netem
+		want  *Netem
+	}{
+		{
+			name:  "defaults",
+			netem: &Netem{},
+			want: &Netem{
+				Limit:     ptr(uint32(1000)),
+				Loss:      ptr(uint32(0)),
+				Gap:       ptr(uint32(0)),
+				Duplicate: ptr(uint32(0)),
+			},
+		},
+		{
+			name: "full configuration",
+			netem: &Netem{
+				Limit:       ptr(uint32(100)),
+				Latency:     ptr(int64(100000000)),
+				Jitter:      ptr(int64(10000000)),
+				Loss:        ptr(uint32(42949673)),
+				Gap:         ptr(uint32(5)),
+				Duplicate:   ptr(uint32(4294967)),
+				Correlation: &NetemCorrelation{Delay: 1, Loss: 2, Duplicate: 3},
+				Reorder:     &NetemProbability{Probability: 4, Correlation: 5},
+				Corrupt:     &NetemProbability{Probability: 6, Correlation: 7},
+				Rate:        &NetemRate{Rate: 10000000000, PacketOverhead: -4, CellSize: 64, CellOverhead: 8},
+				ECN:         ptr(uint32(1)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.netem.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			decoded := &Netem{}
+			if err := decoded.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			want := tt.want
+			if want == nil {
+				want = tt.netem
+			}
+			if diff := cmp.Diff(want, decoded); diff != "" {
+				t.Fatalf("unexpected netem (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid length", func(t *testing.T) {
+		if err := (&Netem{}).UnmarshalBinary([]byte{0x00, 0x01}); err != errInvalidTcOptions {
+			t.Fatalf("expected errInvalidTcOptions, got: %v", err)
+		}
+	})
+}
+
+func TestHtbClassDefaultBurst(t *testing.T) {
+	class := &HtbClass{
+		Rate: ptr(uint64(125000)),
+		Ceil: ptr(uint64(10000000000)),
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	if err := class.Encode(ae); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	b, err := ae.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode attributes: %v", err)
+	}
+	ad, err := netlink.NewAttributeDecoder(b)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	got := &HtbClass{}
+	if err := got.Decode(ad); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	// Rate/1000 + 1600 bytes at the rate, in ticks of 64 nanoseconds.
+	if diff := cmp.Diff(ptr(uint32(215625)), got.Buffer); diff != "" {
+		t.Fatalf("unexpected buffer (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(ptr(uint32(15627)), got.Cbuffer); diff != "" {
+		t.Fatalf("unexpected cbuffer (-want +got):\n%s", diff)
+	}
+}
+
+func TestTcDriverKind(t *testing.T) {
+	for want, drv := range map[string]rtnetlink.TcDriver{
+		"htb":      &HtbClass{},
+		"fq":       &Fq{},
+		"fq_codel": &FqCodel{},
+		"tbf":      &Tbf{},
+		"clsact":   &Clsact{},
+		"ingress":  &Ingress{},
+		"netem":    &Netem{},
+		"bpf":      &Bpf{},
+		"u32":      &U32{},
+		"flower":   &Flower{},
+		"matchall": &Matchall{},
+	} {
+		if got := drv.Kind(); got != want {
+			t.Fatalf("unexpected Kind:\n got: %q\nwant: %q", got, want)
+		}
+	}
+}
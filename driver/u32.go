@@ -0,0 +1,209 @@
+package driver
+
+import (
+	"encoding/binary"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+)
+
+const (
+	// sizeofU32Sel is the size of struct tc_u32_sel without keys.
+	sizeofU32Sel = 16
+
+	// sizeofU32Key is the size of struct tc_u32_key.
+	sizeofU32Key = 16
+)
+
+// U32SelFlag represents u32 selector flags.
+type U32SelFlag uint8
+
+// U32 selector flags.
+const (
+	U32SelFlagTerminal  U32SelFlag = unix.TC_U32_TERMINAL
+	U32SelFlagOffset    U32SelFlag = unix.TC_U32_OFFSET
+	U32SelFlagVarOffset U32SelFlag = unix.TC_U32_VAROFFSET
+	U32SelFlagEat       U32SelFlag = unix.TC_U32_EAT
+)
+
+// U32 represents a u32 classifier (filter) configuration.
+type U32 struct {
+	// ClassID is the class selected by the filter.
+	ClassID *uint32
+
+	// Hash is the handle of the hash table the filter is inserted in.
+	Hash *uint32
+
+	// Link is the handle of the hash table matching packets continue in.
+	Link *uint32
+
+	// Divisor creates a hash table with the number of buckets instead of a
+	// filter.
+	Divisor *uint32
+
+	// Sel is the selector matching packets.
+	Sel *U32Sel
+
+	// Indev limits the filter to packets received on the named interface.
+	Indev *string
+
+	// Flags specifies generic classifier flags (skip hw, skip sw).
+	Flags *TcClsFlag
+}
+
+// U32Sel is a u32 selector. The masks and values are in host byte order.
+type U32Sel struct {
+	Flags    U32SelFlag
+	Offshift uint8
+	Offmask  uint16
+	Off      uint16
+	Offoff   int16
+	Hoff     int16
+	Hmask    uint32
+	Keys     []U32Key
+}
+
+// U32Key matches the 32 bits at Off with Mask against Val. The mask and value
+// are in host byte order.
+type U32Key struct {
+	Mask    uint32
+	Val     uint32
+	Off     int32
+	Offmask int32
+}
+
+var _ rtnetlink.TcFilterDriver = &U32{}
+
+// New creates a new U32 instance.
+func (u *U32) New() rtnetlink.TcDriver {
+	return &U32{}
+}
+
+// Kind returns the u32 classifier kind.
+func (u *U32) Kind() string {
+	return "u32"
+}
+
+// Filter marks the driver as a filter driver.
+func (u *U32) Filter() {}
+
+// Encode encodes the u32 classifier configuration into netlink attributes.
+func (u *U32) Encode(ae *netlink.AttributeEncoder) error {
+	if u.ClassID != nil {
+		ae.Uint32(unix.TCA_U32_CLASSID, *u.ClassID)
+	}
+
+	if u.Hash != nil {
+		ae.Uint32(unix.TCA_U32_HASH, *u.Hash)
+	}
+
+	if u.Link != nil {
+		ae.Uint32(unix.TCA_U32_LINK, *u.Link)
+	}
+
+	if u.Divisor != nil {
+		ae.Uint32(unix.TCA_U32_DIVISOR, *u.Divisor)
+	}
+
+	if u.Sel != nil {
+		ae.Bytes(unix.TCA_U32_SEL, u.Sel.encode())
+	}
+
+	if u.Indev != nil {
+		ae.String(unix.TCA_U32_INDEV, *u.Indev)
+	}
+
+	if u.Flags != nil {
+		ae.Uint32(unix.TCA_U32_FLAGS, uint32(*u.Flags))
+	}
+
+	return nil
+}
+
+// Decode decodes netlink attributes into the u32 classifier configuration.
+func (u *U32) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_U32_CLASSID:
+			classID := ad.Uint32()
+			u.ClassID = &classID
+		case unix.TCA_U32_HASH:
+			hash := ad.Uint32()
+			u.Hash = &hash
+		case unix.TCA_U32_LINK:
+			link := ad.Uint32()
+			u.Link = &link
+		case unix.TCA_U32_DIVISOR:
+			divisor := ad.Uint32()
+			u.Divisor = &divisor
+		case unix.TCA_U32_SEL:
+			u.Sel = &U32Sel{}
+			ad.Do(u.Sel.decode)
+		case unix.TCA_U32_INDEV:
+			indev := ad.String()
+			u.Indev = &indev
+		case unix.TCA_U32_FLAGS:
+			flags := TcClsFlag(ad.Uint32())
+			u.Flags = &flags
+		}
+	}
+
+	return ad.Err()
+}
+
+func (s *U32Sel) encode() []byte {
+	b := make([]byte, sizeofU32Sel+len(s.Keys)*sizeofU32Key)
+	b[0] = uint8(s.Flags)
+	b[1] = s.Offshift
+	b[2] = uint8(len(s.Keys))
+	binary.BigEndian.PutUint16(b[4:6], s.Offmask)
+	nlenc.PutUint16(b[6:8], s.Off)
+	nlenc.PutUint16(b[8:10], uint16(s.Offoff))
+	nlenc.PutUint16(b[10:12], uint16(s.Hoff))
+	binary.BigEndian.PutUint32(b[12:16], s.Hmask)
+
+	for i, k := range s.Keys {
+		kb := b[sizeofU32Sel+i*sizeofU32Key:]
+		binary.BigEndian.PutUint32(kb[0:4], k.Mask)
+		binary.BigEndian.PutUint32(kb[4:8], k.Val)
+		nlenc.PutInt32(kb[8:12], k.Off)
+		nlenc.PutInt32(kb[12:16], k.Offmask)
+	}
+
+	return b
+}
+
+func (s *U32Sel) decode(b []byte) error {
+	if len(b) < sizeofU32Sel {
+		return errInvalidTcOptions
+	}
+
+	nkeys := int(b[2])
+	if len(b) < sizeofU32Sel+nkeys*sizeofU32Key {
+		return errInvalidTcOptions
+	}
+
+	s.Flags = U32SelFlag(b[0])
+	s.Offshift = b[1]
+	s.Offmask = binary.BigEndian.Uint16(b[4:6])
+	s.Off = nlenc.Uint16(b[6:8])
+	s.Offoff = int16(nlenc.Uint16(b[8:10]))
+	s.Hoff = int16(nlenc.Uint16(b[10:12]))
+	s.Hmask = binary.BigEndian.Uint32(b[12:16])
+
+	s.Keys = nil
+	for i := 0; i < nkeys; i++ {
+		kb := b[sizeofU32Sel+i*sizeofU32Key:]
+		s.Keys = append(s.Keys, U32Key{
+			Mask:    binary.BigEndian.Uint32(kb[0:4]),
+			Val:     binary.BigEndian.Uint32(kb[4:8]),
+			Off:     nlenc.Int32(kb[8:12]),
+			Offmask: nlenc.Int32(kb[12:16]),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// Vrf represents a VRF (Virtual Routing and Forwarding) device configuration.
+//
+// Whether a routing table can only be used by a single VRF is not a link
+// attribute but a setting of the network namespace, see SetVrfStrictMode.
+type Vrf struct {
+	// Table specifies the routing table of the VRF, required on creation.
+	Table *uint32
+}
+
+var _ rtnetlink.LinkDriver = &Vrf{}
+
+// New creates a new Vrf instance.
+func (v *Vrf) New() rtnetlink.LinkDriver {
+	return &Vrf{}
+}
+
+// Kind returns the VRF interface kind.
+func (*Vrf) Kind() string {
+	return "vrf"
+}
+
+// Encode encodes the VRF configuration into netlink attributes.
+func (v *Vrf) Encode(ae *netlink.AttributeEncoder) error {
+	if v.Table != nil {
+		ae.Uint32(unix.IFLA_VRF_TABLE, *v.Table)
+	}
+	return nil
+}
+
+// Decode decodes netlink attributes into the VRF configuration.
+func (v *Vrf) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_VRF_TABLE:
+			val := ad.Uint32()
+			v.Table = &val
+		}
+	}
+	return nil
+}
+
+// VrfPort implements LinkSlaveDriver interface for vrf driver
+type VrfPort struct {
+	// Table is the routing table of the VRF the interface is enslaved to.
+	// It is reported by the kernel and can't be changed.
+	Table *uint32
+}
+
+var _ rtnetlink.LinkSlaveDriver = &VrfPort{}
+
+// New creates a new VrfPort instance.
+func (v *VrfPort) New() rtnetlink.LinkDriver {
+	return &VrfPort{}
+}
+
+// Slave marks VrfPort as a slave driver.
+func (v *VrfPort) Slave() {}
+
+// Encode encodes nothing, as the kernel doesn't accept VRF port attributes.
+func (v *VrfPort) Encode(ae *netlink.AttributeEncoder) error {
+	return nil
+}
+
+// Decode decodes netlink attributes into the VRF port configuration.
+func (v *VrfPort) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_VRF_PORT_TABLE:
+			val := ad.Uint32()
+			v.Table = &val
+		}
+	}
+	return nil
+}
+
+// Kind returns the VRF interface kind.
+func (*VrfPort) Kind() string {
+	return "vrf"
+}
+
+// VrfTable returns the routing table of the VRF device with index vrfIndex.
+func VrfTable(conn *rtnetlink.Conn, vrfIndex uint32) (uint32, error) {
+	return VrfTableContext(context.Background(), conn, vrfIndex)
+}
+
+// VrfTableContext is like VrfTable, but takes a context. See Conn.ExecuteContext.
+func VrfTableContext(ctx context.Context, conn *rtnetlink.Conn, vrfIndex uint32) (uint32, error) {
+	link, err := conn.Link.GetContext(ctx, vrfIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	var vrf *Vrf
+	if link.Attributes != nil && link.Attributes.Info != nil {
+		vrf, _ = link.Attributes.Info.Data.(*Vrf)
+	}
+	if vrf == nil || vrf.Table == nil {
+		return 0, fmt.Errorf("interface %d is not a vrf", vrfIndex)
+	}
+
+	return *vrf.Table, nil
+}
+
+// SetVrfMaster enslaves the interface with index ifIndex to the VRF device
+// with index vrfIndex using LinkService.SetMaster, after checking that it is
+// a VRF. Use LinkService.RemoveMaster to release the interface again.
+func SetVrfMaster(conn *rtnetlink.Conn, ifIndex, vrfIndex uint32) error {
+	return SetVrfMasterContext(context.Background(), conn, ifIndex, vrfIndex)
+}
+
+// SetVrfMasterContext is like SetVrfMaster, but takes a context. See Conn.ExecuteContext.
+func SetVrfMasterContext(ctx context.Context, conn *rtnetlink.Conn, ifIndex, vrfIndex uint32) error {
+	if _, err := VrfTableContext(ctx, conn, vrfIndex); err != nil {
+		return err
+	}
+
+	return conn.Link.SetMasterContext(ctx, ifIndex, vrfIndex, nil)
+}
+
+// ListVrfRoutes lists the routes of the given family, or of all families if
+// family is 0, in the routing table of the VRF device with index vrfIndex
+// using RouteService.ListMatch.
+func ListVrfRoutes(conn *rtnetlink.Conn, vrfIndex uint32, family uint8) ([]rtnetlink.RouteMessage, error) {
+	return ListVrfRoutesContext(context.Background(), conn, vrfIndex, family)
+}
+
+// ListVrfRoutesContext is like ListVrfRoutes, but takes a context. See Conn.ExecuteContext.
+func ListVrfRoutesContext(ctx context.Context, conn *rtnetlink.Conn, vrfIndex uint32, family uint8) ([]rtnetlink.RouteMessage, error) {
+	table, err := VrfTableContext(ctx, conn, vrfIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.Route.ListMatchContext(ctx, &rtnetlink.RouteMessage{
+		Family:     family,
+		Attributes: rtnetlink.RouteAttributes{Table: table},
+	})
+}
+
+// vrfStrictModePath is the net.vrf.strict_mode sysctl, which is present once
+// the vrf module is loaded.
+var vrfStrictModePath = "/proc/sys/net/vrf/strict_mode"
+
+// VrfStrictMode reports whether VRF strict mode is enabled in the network
+// namespace of the calling thread.
+func VrfStrictMode() (bool, error) {
+	b, err := os.ReadFile(vrfStrictModePath)
+	if err != nil {
+		return false, err
+	}
+
+	switch v := string(bytes.TrimSpace(b)); v {
+	case "0":
+		return false, nil
+	case "1":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unexpected vrf strict mode value %q", v)
+	}
+}
+
+// SetVrfStrictMode enables or disables VRF strict mode in the network
+// namespace of the calling thread, using the net.vrf.strict_mode sysctl. In
+// strict mode a routing table can only be used by a single VRF, and enabling
+// it fails while a table is shared. Use netns.NetNS.Do to change it in
+// another network namespace.
+func SetVrfStrictMode(enabled bool) error {
+	v := "0"
+	if enabled {
+		v = "1"
+	}
+
+	return os.WriteFile(vrfStrictModePath, []byte(v), 0o644)
+}
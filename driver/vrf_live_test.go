@@ -0,0 +1,161 @@
+//go:build integration
+// +build integration
+
+package driver
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/jsimonetti/rtnetlink/v2/netns"
+	"github.com/mdlayher/netlink"
+)
+
+func TestVrf(t *testing.T) {
+	conn, err := rtnetlink.Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	const (
+		vrfIndex    = 2700
+		portIndex   = 2701
+		bridgeIndex = 2702
+		table       = 100
+	)
+
+	if err := setupInterface(conn, "vrf0", vrfIndex, 0, &Vrf{Table: ptr(uint32(table))}); err != nil {
+		t.Fatalf("failed to setup vrf interface: %v", err)
+	}
+	defer conn.Link.Delete(vrfIndex)
+
+	if err := setupInterface(conn, "vrfport0", portIndex, 0, &Bridge{}); err != nil {
+		t.Fatalf("failed to setup port interface: %v", err)
+	}
+	defer conn.Link.Delete(portIndex)
+
+	if err := setupInterface(conn, "vrfbr0", bridgeIndex, 0, &Bridge{}); err != nil {
+		t.Fatalf("failed to setup bridge interface: %v", err)
+	}
+	defer conn.Link.Delete(bridgeIndex)
+
+	got, err := VrfTable(conn, vrfIndex)
+	if err != nil {
+		t.Fatalf("failed to get vrf table: %v", err)
+	}
+	if got != table {
+		t.Fatalf("unexpected vrf table: want %d, got %d", table, got)
+	}
+
+	if err := SetVrfMaster(conn, portIndex, bridgeIndex); err == nil {
+		t.Fatal("expected an error enslaving an interface to a bridge as vrf")
+	}
+
+	if err := SetVrfMaster(conn, portIndex, vrfIndex); err != nil {
+		t.Fatalf("failed to enslave interface to vrf: %v", err)
+	}
+
+	msg, err := getInterface(conn, portIndex)
+	if err != nil {
+		t.Fatalf("failed to get port interface: %v", err)
+	}
+	if msg.Attributes == nil || msg.Attributes.Info == nil {
+		t.Fatal("port interface missing link info")
+	}
+	port, ok := msg.Attributes.Info.SlaveData.(*VrfPort)
+	if !ok {
+		t.Fatalf("expected *VrfPort, got %T", msg.Attributes.Info.SlaveData)
+	}
+	if port.Table == nil || *port.Table != table {
+		t.Fatalf("unexpected vrf port table: %v", port.Table)
+	}
+
+	dst := net.IPv4(192, 0, 2, 0).To4()
+	err = conn.Route.Add(&rtnetlink.RouteMessage{
+		Family:    unix.AF_INET,
+		DstLength: 24,
+		Table:     table,
+		Protocol:  unix.RTPROT_STATIC,
+		Scope:     unix.RT_SCOPE_LINK,
+		Type:      unix.RTN_UNICAST,
+		Attributes: rtnetlink.RouteAttributes{
+			Dst:      dst,
+			OutIface: portIndex,
+			Table:    table,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to add route to vrf table: %v", err)
+	}
+
+	routes, err := ListVrfRoutes(conn, vrfIndex, unix.AF_INET)
+	if err != nil {
+		t.Fatalf("failed to list vrf routes: %v", err)
+	}
+
+	var found bool
+	for _, r := range routes {
+		if r.Attributes.Table != table {
+			t.Fatalf("unexpected route from table %d", r.Attributes.Table)
+		}
+		if r.Attributes.Dst.Equal(dst) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected route to %v in vrf routes: %+v", dst, routes)
+	}
+
+	if err := conn.Link.RemoveMaster(portIndex); err != nil {
+		t.Fatalf("failed to release interface from vrf: %v", err)
+	}
+}
+
+func TestVrfStrictMode(t *testing.T) {
+	ns, err := netns.OpenPath(fmt.Sprintf("/proc/self/fd/%d", testutils.NetNS(t)))
+	if err != nil {
+		t.Fatalf("failed to open netns: %v", err)
+	}
+	defer ns.Close()
+
+	conn, err := ns.Dial(nil)
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket to netns: %v", err)
+	}
+	defer conn.Close()
+
+	const table = 100
+
+	// Creating a VRF loads the vrf module, which registers the sysctl.
+	if err := setupInterface(conn, "vrf0", 2710, 0, &Vrf{Table: ptr(uint32(table))}); err != nil {
+		t.Fatalf("failed to setup vrf interface: %v", err)
+	}
+	defer conn.Link.Delete(2710)
+
+	err = ns.Do(func() error {
+		if enabled, err := VrfStrictMode(); err != nil || enabled {
+			return fmt.Errorf("expected strict mode to be disabled: %v, %v", enabled, err)
+		}
+		if err := SetVrfStrictMode(true); err != nil {
+			return fmt.Errorf("failed to enable strict mode: %v", err)
+		}
+		if enabled, err := VrfStrictMode(); err != nil || !enabled {
+			return fmt.Errorf("expected strict mode to be enabled: %v, %v", enabled, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// In strict mode a table can't be shared by VRFs.
+	if err := setupInterface(conn, "vrf1", 2711, 0, &Vrf{Table: ptr(uint32(table))}); err == nil {
+		conn.Link.Delete(2711)
+		t.Fatal("expected an error creating a second vrf using the same table")
+	}
+}
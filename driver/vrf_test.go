@@ -0,0 +1,139 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestVrfEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		vrf  *Vrf
+	}{
+		{
+			name: "empty",
+			vrf:  &Vrf{},
+		},
+		{
+			name: "table",
+			vrf:  &Vrf{Table: ptr(uint32(100))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae := netlink.NewAttributeEncoder()
+			if err := tt.vrf.Encode(ae); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			b, err := ae.Encode()
+			if err != nil {
+				t.Fatalf("failed to encode attributes: %v", err)
+			}
+
+			ad, err := netlink.NewAttributeDecoder(b)
+			if err != nil {
+				t.Fatalf("failed to create decoder: %v", err)
+			}
+
+			decoded := &Vrf{}
+			if err := decoded.Decode(ad); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.vrf, decoded); diff != "" {
+				t.Fatalf("unexpected vrf config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVrfPortDecode(t *testing.T) {
+	ae := netlink.NewAttributeEncoder()
+	ae.Uint32(unix.IFLA_VRF_PORT_TABLE, 100)
+	b, err := ae.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode attributes: %v", err)
+	}
+
+	ad, err := netlink.NewAttributeDecoder(b)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	port := &VrfPort{}
+	if err := port.Decode(ad); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if diff := cmp.Diff(&VrfPort{Table: ptr(uint32(100))}, port); diff != "" {
+		t.Fatalf("unexpected vrf port config (-want +got):\n%s", diff)
+	}
+
+	// The table of a port is read-only.
+	ae = netlink.NewAttributeEncoder()
+	if err := port.Encode(ae); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if b, _ := ae.Encode(); len(b) != 0 {
+		t.Fatalf("expected no attributes, got [%# x]", b)
+	}
+}
+
+func TestVrfKind(t *testing.T) {
+	tests := []struct {
+		driver rtnetlink.LinkDriver
+		slave  bool
+	}{
+		{driver: &Vrf{}},
+		{driver: &VrfPort{}, slave: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.driver.Kind(); got != "vrf" {
+			t.Errorf("expected kind %q, got %q", "vrf", got)
+		}
+		if _, ok := tt.driver.New().(rtnetlink.LinkSlaveDriver); ok != tt.slave {
+			t.Errorf("expected %T to be a slave driver: %v", tt.driver, tt.slave)
+		}
+	}
+}
+
+func TestVrfStrictModeSysctl(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strict_mode")
+	if err := os.WriteFile(path, []byte("0\n"), 0o600); err != nil {
+		t.Fatalf("failed to write sysctl: %v", err)
+	}
+
+	old := vrfStrictModePath
+	vrfStrictModePath = path
+	defer func() { vrfStrictModePath = old }()
+
+	for _, want := range []bool{false, true, false} {
+		if err := SetVrfStrictMode(want); err != nil {
+			t.Fatalf("failed to set strict mode: %v", err)
+		}
+
+		got, err := VrfStrictMode()
+		if err != nil {
+			t.Fatalf("failed to get strict mode: %v", err)
+		}
+		if got != want {
+			t.Fatalf("unexpected strict mode: want %v, got %v", want, got)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("2\n"), 0o600); err != nil {
+		t.Fatalf("failed to write sysctl: %v", err)
+	}
+	if _, err := VrfStrictMode(); err == nil {
+		t.Fatal("expected an error for an unexpected value")
+	}
+}
@@ -0,0 +1,586 @@
+package rtnetlink
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// An OpError is an error returned by the kernel in reply to a request made by
+// Conn.Execute and the services built on top of it.
+//
+// Conn enables extended acknowledgements, so on kernels which support them an
+// OpError also carries a description of the problem and, if the kernel points
+// at one, the offending attribute of the request.
+//
+// OpError unwraps to the *netlink.OpError it was created from, so errors.Is
+// can be used to check for a specific error number, such as unix.EEXIST.
+type OpError struct {
+	// Op is the name of the request type, such as "RTM_NEWLINK".
+	Op string
+
+	// Err is the error number returned by the kernel.
+	Err syscall.Errno
+
+	// Message is the extended acknowledgement message of the kernel, if any.
+	Message string
+
+	// Offset is the byte offset of the offending attribute from the start of
+	// the request, including the netlink header. It is zero if the kernel did
+	// not report an attribute.
+	Offset int
+
+	// Attribute is the name of the attribute at Offset, for example
+	// "IFLA_LINKINFO/IFLA_INFO_KIND". Attributes with an unknown name are
+	// given by their type number. Attribute is empty if Offset does not point
+	// to an attribute of the request.
+	Attribute string
+
+	err *netlink.OpError
+}
+
+// Error implements error.
+func (e *OpError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "rtnetlink %s: %v", e.Op, e.Err)
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+	switch {
+	case e.Attribute != "":
+		fmt.Fprintf(&sb, " (attribute %s)", e.Attribute)
+	case e.Offset != 0:
+		fmt.Fprintf(&sb, " (offset %d)", e.Offset)
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the underlying *netlink.OpError.
+func (e *OpError) Unwrap() error {
+	return e.err
+}
+
+// newOpError wraps an error returned by the kernel in reply to the request
+// req into an *OpError. Other errors, such as those of the socket itself, are
+// returned unchanged.
+func newOpError(req netlink.Message, err error) error {
+	var nerr *netlink.OpError
+	if !errors.As(err, &nerr) {
+		return err
+	}
+
+	// Errors of system calls are wrapped in an *os.SyscallError, the kernel
+	// replies with a bare error number.
+	errno, ok := nerr.Err.(syscall.Errno)
+	if !ok {
+		return err
+	}
+
+	return &OpError{
+		Op:        opName(req.Header.Type),
+		Err:       errno,
+		Message:   nerr.Message,
+		Offset:    nerr.Offset,
+		Attribute: attributeName(req, nerr.Offset),
+		err:       nerr,
+	}
+}
+
+// opNames maps request types to their names.
+var opNames = map[netlink.HeaderType]string{
+	unix.RTM_NEWLINK:     "RTM_NEWLINK",
+	unix.RTM_DELLINK:     "RTM_DELLINK",
+	unix.RTM_GETLINK:     "RTM_GETLINK",
+	unix.RTM_SETLINK:     "RTM_SETLINK",
+	unix.RTM_NEWADDR:     "RTM_NEWADDR",
+	unix.RTM_DELADDR:     "RTM_DELADDR",
+	unix.RTM_GETADDR:     "RTM_GETADDR",
+	unix.RTM_NEWROUTE:    "RTM_NEWROUTE",
+	unix.RTM_DELROUTE:    "RTM_DELROUTE",
+	unix.RTM_GETROUTE:    "RTM_GETROUTE",
+	unix.RTM_NEWNEIGH:    "RTM_NEWNEIGH",
+	unix.RTM_DELNEIGH:    "RTM_DELNEIGH",
+	unix.RTM_GETNEIGH:    "RTM_GETNEIGH",
+	unix.RTM_NEWNEIGHTBL: "RTM_NEWNEIGHTBL",
+	unix.RTM_GETNEIGHTBL: "RTM_GETNEIGHTBL",
+	unix.RTM_SETNEIGHTBL: "RTM_SETNEIGHTBL",
+	unix.RTM_NEWRULE:     "RTM_NEWRULE",
+	unix.RTM_DELRULE:     "RTM_DELRULE",
+	unix.RTM_GETRULE:     "RTM_GETRULE",
+	unix.RTM_NEWQDISC:    "RTM_NEWQDISC",
+	unix.RTM_DELQDISC:    "RTM_DELQDISC",
+	unix.RTM_GETQDISC:    "RTM_GETQDISC",
+	unix.RTM_NEWTCLASS:   "RTM_NEWTCLASS",
+	unix.RTM_DELTCLASS:   "RTM_DELTCLASS",
+	unix.RTM_GETTCLASS:   "RTM_GETTCLASS",
+	unix.RTM_NEWTFILTER:  "RTM_NEWTFILTER",
+	unix.RTM_DELTFILTER:  "RTM_DELTFILTER",
+	unix.RTM_GETTFILTER:  "RTM_GETTFILTER",
+	unix.RTM_NEWNEXTHOP:  "RTM_NEWNEXTHOP",
+	unix.RTM_DELNEXTHOP:  "RTM_DELNEXTHOP",
+	unix.RTM_GETNEXTHOP:  "RTM_GETNEXTHOP",
+	unix.RTM_NEWNSID:     "RTM_NEWNSID",
+	unix.RTM_DELNSID:     "RTM_DELNSID",
+	unix.RTM_GETNSID:     "RTM_GETNSID",
+	unix.RTM_NEWNETCONF:  "RTM_NEWNETCONF",
+	unix.RTM_DELNETCONF:  "RTM_DELNETCONF",
+	unix.RTM_GETNETCONF:  "RTM_GETNETCONF",
+	unix.RTM_NEWSTATS:    "RTM_NEWSTATS",
+	unix.RTM_GETSTATS:    "RTM_GETSTATS",
+	unix.RTM_SETSTATS:    "RTM_SETSTATS",
+	unix.RTM_NEWVLAN:     "RTM_NEWVLAN",
+	unix.RTM_DELVLAN:     "RTM_DELVLAN",
+	unix.RTM_GETVLAN:     "RTM_GETVLAN",
+	unix.RTM_NEWMDB:      "RTM_NEWMDB",
+	unix.RTM_DELMDB:      "RTM_DELMDB",
+	unix.RTM_GETMDB:      "RTM_GETMDB",
+}
+
+func opName(t netlink.HeaderType) string {
+	if name, ok := opNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("message type %d", t)
+}
+
+// An attrTable names the attributes of a message or a nested attribute.
+type attrTable struct {
+	names  map[uint16]string
+	nested map[uint16]*attrTable
+}
+
+// opaque is used for nested attributes whose content depends on a driver,
+// their attributes are given by type number.
+var opaque = &attrTable{}
+
+var linkAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.IFLA_ADDRESS:        "IFLA_ADDRESS",
+		unix.IFLA_BROADCAST:      "IFLA_BROADCAST",
+		unix.IFLA_IFNAME:         "IFLA_IFNAME",
+		unix.IFLA_MTU:            "IFLA_MTU",
+		unix.IFLA_LINK:           "IFLA_LINK",
+		unix.IFLA_QDISC:          "IFLA_QDISC",
+		unix.IFLA_MASTER:         "IFLA_MASTER",
+		unix.IFLA_TXQLEN:         "IFLA_TXQLEN",
+		unix.IFLA_OPERSTATE:      "IFLA_OPERSTATE",
+		unix.IFLA_LINKMODE:       "IFLA_LINKMODE",
+		unix.IFLA_LINKINFO:       "IFLA_LINKINFO",
+		unix.IFLA_NET_NS_PID:     "IFLA_NET_NS_PID",
+		unix.IFLA_IFALIAS:        "IFLA_IFALIAS",
+		unix.IFLA_EXT_MASK:       "IFLA_EXT_MASK",
+		unix.IFLA_NET_NS_FD:      "IFLA_NET_NS_FD",
+		unix.IFLA_GROUP:          "IFLA_GROUP",
+		unix.IFLA_CARRIER:        "IFLA_CARRIER",
+		unix.IFLA_XDP:            "IFLA_XDP",
+		unix.IFLA_PROP_LIST:      "IFLA_PROP_LIST",
+		unix.IFLA_ALT_IFNAME:     "IFLA_ALT_IFNAME",
+		unix.IFLA_VFINFO_LIST:    "IFLA_VFINFO_LIST",
+		unix.IFLA_LINK_NETNSID:   "IFLA_LINK_NETNSID",
+		unix.IFLA_TARGET_NETNSID: "IFLA_TARGET_NETNSID",
+		unix.IFLA_AF_SPEC:        "IFLA_AF_SPEC",
+	},
+	nested: map[uint16]*attrTable{
+		unix.IFLA_LINKINFO: {
+			names: map[uint16]string{
+				unix.IFLA_INFO_KIND:       "IFLA_INFO_KIND",
+				unix.IFLA_INFO_DATA:       "IFLA_INFO_DATA",
+				unix.IFLA_INFO_SLAVE_KIND: "IFLA_INFO_SLAVE_KIND",
+				unix.IFLA_INFO_SLAVE_DATA: "IFLA_INFO_SLAVE_DATA",
+			},
+			nested: map[uint16]*attrTable{
+				unix.IFLA_INFO_DATA:       opaque,
+				unix.IFLA_INFO_SLAVE_DATA: opaque,
+			},
+		},
+		unix.IFLA_XDP: {
+			names: map[uint16]string{
+				unix.IFLA_XDP_FD:          "IFLA_XDP_FD",
+				unix.IFLA_XDP_ATTACHED:    "IFLA_XDP_ATTACHED",
+				unix.IFLA_XDP_FLAGS:       "IFLA_XDP_FLAGS",
+				unix.IFLA_XDP_PROG_ID:     "IFLA_XDP_PROG_ID",
+				unix.IFLA_XDP_EXPECTED_FD: "IFLA_XDP_EXPECTED_FD",
+			},
+		},
+		unix.IFLA_PROP_LIST: {
+			names: map[uint16]string{
+				unix.IFLA_ALT_IFNAME: "IFLA_ALT_IFNAME",
+			},
+		},
+	},
+}
+
+var addressAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.IFA_ADDRESS:     "IFA_ADDRESS",
+		unix.IFA_LOCAL:       "IFA_LOCAL",
+		unix.IFA_LABEL:       "IFA_LABEL",
+		unix.IFA_BROADCAST:   "IFA_BROADCAST",
+		unix.IFA_ANYCAST:     "IFA_ANYCAST",
+		unix.IFA_CACHEINFO:   "IFA_CACHEINFO",
+		unix.IFA_MULTICAST:   "IFA_MULTICAST",
+		unix.IFA_FLAGS:       "IFA_FLAGS",
+		unix.IFA_RT_PRIORITY: "IFA_RT_PRIORITY",
+	},
+}
+
+var routeAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.RTA_DST:        "RTA_DST",
+		unix.RTA_OIF:        "RTA_OIF",
+		unix.RTA_GATEWAY:    "RTA_GATEWAY",
+		unix.RTA_PRIORITY:   "RTA_PRIORITY",
+		unix.RTA_PREFSRC:    "RTA_PREFSRC",
+		unix.RTA_METRICS:    "RTA_METRICS",
+		unix.RTA_MULTIPATH:  "RTA_MULTIPATH",
+		unix.RTA_TABLE:      "RTA_TABLE",
+		unix.RTA_MARK:       "RTA_MARK",
+		unix.RTA_VIA:        "RTA_VIA",
+		unix.RTA_PREF:       "RTA_PREF",
+		unix.RTA_ENCAP_TYPE: "RTA_ENCAP_TYPE",
+		unix.RTA_ENCAP:      "RTA_ENCAP",
+		unix.RTA_EXPIRES:    "RTA_EXPIRES",
+		unix.RTA_NH_ID:      "RTA_NH_ID",
+	},
+	nested: map[uint16]*attrTable{
+		unix.RTA_METRICS: {
+			names: map[uint16]string{
+				unix.RTAX_MTU:      "RTAX_MTU",
+				unix.RTAX_ADVMSS:   "RTAX_ADVMSS",
+				unix.RTAX_INITCWND: "RTAX_INITCWND",
+				unix.RTAX_FEATURES: "RTAX_FEATURES",
+				unix.RTAX_INITRWND: "RTAX_INITRWND",
+			},
+		},
+	},
+}
+
+var neighAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.NDA_DST:           "NDA_DST",
+		unix.NDA_LLADDR:        "NDA_LLADDR",
+		unix.NDA_CACHEINFO:     "NDA_CACHEINFO",
+		unix.NDA_IFINDEX:       "NDA_IFINDEX",
+		unix.NDA_MASTER:        "NDA_MASTER",
+		unix.NDA_VLAN:          "NDA_VLAN",
+		unix.NDA_PORT:          "NDA_PORT",
+		unix.NDA_VNI:           "NDA_VNI",
+		unix.NDA_SRC_VNI:       "NDA_SRC_VNI",
+		unix.NDA_FDB_EXT_ATTRS: "NDA_FDB_EXT_ATTRS",
+	},
+	nested: map[uint16]*attrTable{
+		unix.NDA_FDB_EXT_ATTRS: {
+			names: map[uint16]string{
+				unix.NFEA_ACTIVITY_NOTIFY: "NFEA_ACTIVITY_NOTIFY",
+				unix.NFEA_DONT_REFRESH:    "NFEA_DONT_REFRESH",
+			},
+		},
+	},
+}
+
+var neighTableAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.NDTA_NAME:        "NDTA_NAME",
+		unix.NDTA_THRESH1:     "NDTA_THRESH1",
+		unix.NDTA_THRESH2:     "NDTA_THRESH2",
+		unix.NDTA_THRESH3:     "NDTA_THRESH3",
+		unix.NDTA_PARMS:       "NDTA_PARMS",
+		unix.NDTA_GC_INTERVAL: "NDTA_GC_INTERVAL",
+	},
+	nested: map[uint16]*attrTable{
+		unix.NDTA_PARMS: {
+			names: map[uint16]string{
+				unix.NDTPA_IFINDEX:                "NDTPA_IFINDEX",
+				unix.NDTPA_BASE_REACHABLE_TIME:    "NDTPA_BASE_REACHABLE_TIME",
+				unix.NDTPA_RETRANS_TIME:           "NDTPA_RETRANS_TIME",
+				unix.NDTPA_GC_STALETIME:           "NDTPA_GC_STALETIME",
+				unix.NDTPA_DELAY_PROBE_TIME:       "NDTPA_DELAY_PROBE_TIME",
+				unix.NDTPA_QUEUE_LEN:              "NDTPA_QUEUE_LEN",
+				unix.NDTPA_APP_PROBES:             "NDTPA_APP_PROBES",
+				unix.NDTPA_UCAST_PROBES:           "NDTPA_UCAST_PROBES",
+				unix.NDTPA_MCAST_PROBES:           "NDTPA_MCAST_PROBES",
+				unix.NDTPA_ANYCAST_DELAY:          "NDTPA_ANYCAST_DELAY",
+				unix.NDTPA_PROXY_DELAY:            "NDTPA_PROXY_DELAY",
+				unix.NDTPA_PROXY_QLEN:             "NDTPA_PROXY_QLEN",
+				unix.NDTPA_LOCKTIME:               "NDTPA_LOCKTIME",
+				unix.NDTPA_QUEUE_LENBYTES:         "NDTPA_QUEUE_LENBYTES",
+				unix.NDTPA_MCAST_REPROBES:         "NDTPA_MCAST_REPROBES",
+				unix.NDTPA_INTERVAL_PROBE_TIME_MS: "NDTPA_INTERVAL_PROBE_TIME_MS",
+			},
+		},
+	},
+}
+
+var ruleAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.FRA_DST:                "FRA_DST",
+		unix.FRA_SRC:                "FRA_SRC",
+		unix.FRA_IIFNAME:            "FRA_IIFNAME",
+		unix.FRA_GOTO:               "FRA_GOTO",
+		unix.FRA_PRIORITY:           "FRA_PRIORITY",
+		unix.FRA_FWMARK:             "FRA_FWMARK",
+		unix.FRA_FLOW:               "FRA_FLOW",
+		unix.FRA_TUN_ID:             "FRA_TUN_ID",
+		unix.FRA_SUPPRESS_IFGROUP:   "FRA_SUPPRESS_IFGROUP",
+		unix.FRA_SUPPRESS_PREFIXLEN: "FRA_SUPPRESS_PREFIXLEN",
+		unix.FRA_TABLE:              "FRA_TABLE",
+		unix.FRA_FWMASK:             "FRA_FWMASK",
+		unix.FRA_OIFNAME:            "FRA_OIFNAME",
+		unix.FRA_L3MDEV:             "FRA_L3MDEV",
+		unix.FRA_UID_RANGE:          "FRA_UID_RANGE",
+		unix.FRA_PROTOCOL:           "FRA_PROTOCOL",
+		unix.FRA_IP_PROTO:           "FRA_IP_PROTO",
+		unix.FRA_SPORT_RANGE:        "FRA_SPORT_RANGE",
+		unix.FRA_DPORT_RANGE:        "FRA_DPORT_RANGE",
+	},
+}
+
+var nexthopAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.NHA_ID:         "NHA_ID",
+		unix.NHA_GROUP:      "NHA_GROUP",
+		unix.NHA_GROUP_TYPE: "NHA_GROUP_TYPE",
+		unix.NHA_BLACKHOLE:  "NHA_BLACKHOLE",
+		unix.NHA_OIF:        "NHA_OIF",
+		unix.NHA_GATEWAY:    "NHA_GATEWAY",
+		unix.NHA_GROUPS:     "NHA_GROUPS",
+		unix.NHA_MASTER:     "NHA_MASTER",
+		unix.NHA_FDB:        "NHA_FDB",
+		unix.NHA_RES_GROUP:  "NHA_RES_GROUP",
+	},
+	nested: map[uint16]*attrTable{
+		unix.NHA_RES_GROUP: {
+			names: map[uint16]string{
+				unix.NHA_RES_GROUP_BUCKETS:          "NHA_RES_GROUP_BUCKETS",
+				unix.NHA_RES_GROUP_IDLE_TIMER:       "NHA_RES_GROUP_IDLE_TIMER",
+				unix.NHA_RES_GROUP_UNBALANCED_TIMER: "NHA_RES_GROUP_UNBALANCED_TIMER",
+			},
+		},
+	},
+}
+
+var nsidAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.NETNSA_NSID:        "NETNSA_NSID",
+		unix.NETNSA_PID:         "NETNSA_PID",
+		unix.NETNSA_FD:          "NETNSA_FD",
+		unix.NETNSA_TARGET_NSID: "NETNSA_TARGET_NSID",
+	},
+}
+
+var netconfAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.NETCONFA_IFINDEX:                     "NETCONFA_IFINDEX",
+		unix.NETCONFA_FORWARDING:                  "NETCONFA_FORWARDING",
+		unix.NETCONFA_RP_FILTER:                   "NETCONFA_RP_FILTER",
+		unix.NETCONFA_MC_FORWARDING:               "NETCONFA_MC_FORWARDING",
+		unix.NETCONFA_PROXY_NEIGH:                 "NETCONFA_PROXY_NEIGH",
+		unix.NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN: "NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN",
+		unix.NETCONFA_INPUT:                       "NETCONFA_INPUT",
+		unix.NETCONFA_BC_FORWARDING:               "NETCONFA_BC_FORWARDING",
+	},
+}
+
+// statsAttrs covers the attributes of RTM_GETSTATS and RTM_SETSTATS requests,
+// which differ from those of the replies.
+var statsAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.IFLA_STATS_GET_FILTERS:                 "IFLA_STATS_GET_FILTERS",
+		unix.IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS: "IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS",
+	},
+	nested: map[uint16]*attrTable{
+		unix.IFLA_STATS_GET_FILTERS: {
+			names: map[uint16]string{
+				unix.IFLA_STATS_LINK_OFFLOAD_XSTATS: "IFLA_STATS_LINK_OFFLOAD_XSTATS",
+			},
+		},
+	},
+}
+
+var bridgeVlanAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.BRIDGE_VLANDB_ENTRY: "BRIDGE_VLANDB_ENTRY",
+	},
+	nested: map[uint16]*attrTable{
+		unix.BRIDGE_VLANDB_ENTRY: {
+			names: map[uint16]string{
+				unix.BRIDGE_VLANDB_ENTRY_INFO:             "BRIDGE_VLANDB_ENTRY_INFO",
+				unix.BRIDGE_VLANDB_ENTRY_RANGE:            "BRIDGE_VLANDB_ENTRY_RANGE",
+				unix.BRIDGE_VLANDB_ENTRY_STATE:            "BRIDGE_VLANDB_ENTRY_STATE",
+				unix.BRIDGE_VLANDB_ENTRY_TUNNEL_INFO:      "BRIDGE_VLANDB_ENTRY_TUNNEL_INFO",
+				unix.BRIDGE_VLANDB_ENTRY_MCAST_ROUTER:     "BRIDGE_VLANDB_ENTRY_MCAST_ROUTER",
+				unix.BRIDGE_VLANDB_ENTRY_MCAST_MAX_GROUPS: "BRIDGE_VLANDB_ENTRY_MCAST_MAX_GROUPS",
+				unix.BRIDGE_VLANDB_ENTRY_NEIGH_SUPPRESS:   "BRIDGE_VLANDB_ENTRY_NEIGH_SUPPRESS",
+			},
+			nested: map[uint16]*attrTable{
+				unix.BRIDGE_VLANDB_ENTRY_TUNNEL_INFO: {
+					names: map[uint16]string{
+						unix.BRIDGE_VLANDB_TINFO_ID:  "BRIDGE_VLANDB_TINFO_ID",
+						unix.BRIDGE_VLANDB_TINFO_CMD: "BRIDGE_VLANDB_TINFO_CMD",
+					},
+				},
+			},
+		},
+	},
+}
+
+var mdbAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.MDBA_SET_ENTRY:       "MDBA_SET_ENTRY",
+		unix.MDBA_SET_ENTRY_ATTRS: "MDBA_SET_ENTRY_ATTRS",
+	},
+	nested: map[uint16]*attrTable{
+		unix.MDBA_SET_ENTRY_ATTRS: {
+			names: map[uint16]string{
+				unix.MDBE_ATTR_SOURCE:     "MDBE_ATTR_SOURCE",
+				unix.MDBE_ATTR_SRC_LIST:   "MDBE_ATTR_SRC_LIST",
+				unix.MDBE_ATTR_GROUP_MODE: "MDBE_ATTR_GROUP_MODE",
+				unix.MDBE_ATTR_RTPROT:     "MDBE_ATTR_RTPROT",
+				unix.MDBE_ATTR_DST:        "MDBE_ATTR_DST",
+				unix.MDBE_ATTR_DST_PORT:   "MDBE_ATTR_DST_PORT",
+				unix.MDBE_ATTR_VNI:        "MDBE_ATTR_VNI",
+				unix.MDBE_ATTR_IFINDEX:    "MDBE_ATTR_IFINDEX",
+				unix.MDBE_ATTR_SRC_VNI:    "MDBE_ATTR_SRC_VNI",
+			},
+			nested: map[uint16]*attrTable{
+				unix.MDBE_ATTR_SRC_LIST: {
+					names: map[uint16]string{
+						unix.MDBE_SRC_LIST_ENTRY: "MDBE_SRC_LIST_ENTRY",
+					},
+					nested: map[uint16]*attrTable{
+						unix.MDBE_SRC_LIST_ENTRY: {
+							names: map[uint16]string{
+								unix.MDBE_SRCATTR_ADDRESS: "MDBE_SRCATTR_ADDRESS",
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var tcAttrs = &attrTable{
+	names: map[uint16]string{
+		unix.TCA_KIND:          "TCA_KIND",
+		unix.TCA_OPTIONS:       "TCA_OPTIONS",
+		unix.TCA_CHAIN:         "TCA_CHAIN",
+		unix.TCA_INGRESS_BLOCK: "TCA_INGRESS_BLOCK",
+		unix.TCA_EGRESS_BLOCK:  "TCA_EGRESS_BLOCK",
+	},
+	nested: map[uint16]*attrTable{
+		unix.TCA_OPTIONS: opaque,
+	},
+}
+
+// messageAttrs returns the size of the fixed header of messages of type t and
+// the table of their attributes.
+func messageAttrs(t netlink.HeaderType) (int, *attrTable) {
+	switch t {
+	case unix.RTM_NEWLINK, unix.RTM_DELLINK, unix.RTM_GETLINK, unix.RTM_SETLINK:
+		return unix.SizeofIfInfomsg, linkAttrs
+	case unix.RTM_NEWADDR, unix.RTM_DELADDR, unix.RTM_GETADDR:
+		return unix.SizeofIfAddrmsg, addressAttrs
+	case unix.RTM_NEWROUTE, unix.RTM_DELROUTE, unix.RTM_GETROUTE:
+		return unix.SizeofRtMsg, routeAttrs
+	case unix.RTM_NEWNEIGH, unix.RTM_DELNEIGH, unix.RTM_GETNEIGH:
+		return unix.SizeofNdMsg, neighAttrs
+	case unix.RTM_NEWNEIGHTBL, unix.RTM_GETNEIGHTBL, unix.RTM_SETNEIGHTBL:
+		return sizeofNdtmsg, neighTableAttrs
+	case unix.RTM_NEWRULE, unix.RTM_DELRULE, unix.RTM_GETRULE:
+		// struct fib_rule_hdr
+		return 12, ruleAttrs
+	case unix.RTM_NEWNEXTHOP, unix.RTM_DELNEXTHOP, unix.RTM_GETNEXTHOP:
+		return unix.SizeofNhmsg, nexthopAttrs
+	case unix.RTM_NEWNSID, unix.RTM_DELNSID, unix.RTM_GETNSID:
+		return sizeofNSIDMsg, nsidAttrs
+	case unix.RTM_NEWNETCONF, unix.RTM_DELNETCONF, unix.RTM_GETNETCONF:
+		return sizeofNetconfmsg, netconfAttrs
+	case unix.RTM_NEWSTATS, unix.RTM_GETSTATS, unix.RTM_SETSTATS:
+		return sizeofIfStatsMsg, statsAttrs
+	case unix.RTM_NEWVLAN, unix.RTM_DELVLAN, unix.RTM_GETVLAN:
+		return sizeofBrVlanMsg, bridgeVlanAttrs
+	case unix.RTM_NEWMDB, unix.RTM_DELMDB, unix.RTM_GETMDB:
+		return sizeofBrPortMsg, mdbAttrs
+	case unix.RTM_NEWQDISC, unix.RTM_DELQDISC, unix.RTM_GETQDISC,
+		unix.RTM_NEWTCLASS, unix.RTM_DELTCLASS, unix.RTM_GETTCLASS,
+		unix.RTM_NEWTFILTER, unix.RTM_DELTFILTER, unix.RTM_GETTFILTER:
+		return unix.SizeofTcMsg, tcAttrs
+	default:
+		return 0, nil
+	}
+}
+
+// sizeofNlmsghdr is the size of the netlink header preceding the request
+// data, which the offset reported by the kernel accounts for.
+const sizeofNlmsghdr = 16
+
+// attributeName returns the name of the attribute of req at offset, or an
+// empty string if offset does not point into an attribute.
+func attributeName(req netlink.Message, offset int) string {
+	hdrlen, table := messageAttrs(req.Header.Type)
+	if table == nil {
+		return ""
+	}
+
+	off := offset - sizeofNlmsghdr - hdrlen
+	if off < 0 || hdrlen > len(req.Data) {
+		return ""
+	}
+
+	return table.lookup(req.Data[hdrlen:], off)
+}
+
+// lookup returns the path of the attribute in b found at offset off.
+func (t *attrTable) lookup(b []byte, off int) string {
+	const (
+		sizeofNlattr = 4
+		typeMask     = ^uint16(unix.NLA_F_NESTED | unix.NLA_F_NET_BYTEORDER)
+	)
+
+	for off >= 0 && len(b) >= sizeofNlattr {
+		l := int(nativeEndian.Uint16(b[0:2]))
+		if l < sizeofNlattr || l > len(b) {
+			return ""
+		}
+		typ := nativeEndian.Uint16(b[2:4]) & typeMask
+
+		if off < l {
+			name := t.name(typ)
+			if nested := t.nested[typ]; nested != nil && off >= sizeofNlattr {
+				if inner := nested.lookup(b[sizeofNlattr:l], off-sizeofNlattr); inner != "" {
+					name += "/" + inner
+				}
+			}
+			return name
+		}
+
+		// Attributes are padded to a multiple of 4 bytes.
+		next := (l + sizeofNlattr - 1) &^ (sizeofNlattr - 1)
+		if next > len(b) {
+			return ""
+		}
+		b = b[next:]
+		off -= next
+	}
+
+	return ""
+}
+
+func (t *attrTable) name(typ uint16) string {
+	if name, ok := t.names[typ]; ok {
+		return name
+	}
+	return strconv.Itoa(int(typ))
+}
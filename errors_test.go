@@ -0,0 +1,122 @@
+//go:build linux
+// +build linux
+
+package rtnetlink
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestConnExecuteOpError(t *testing.T) {
+	skipBigEndian(t)
+
+	c, tc := testConn(t)
+	tc.err = &netlink.OpError{
+		Op:      "receive",
+		Err:     unix.EEXIST,
+		Message: "Link already exists",
+		// Offset of IFLA_IFNAME, after the netlink header and ifinfomsg.
+		Offset: 32,
+	}
+
+	req := &LinkMessage{Attributes: &LinkAttributes{Name: "foo"}}
+	_, err := c.Execute(req, unix.RTM_NEWLINK, netlink.Request|netlink.Create|netlink.Excl)
+	if !errors.Is(err, unix.EEXIST) {
+		t.Fatalf("expected EEXIST, got: %v", err)
+	}
+
+	var nerr *netlink.OpError
+	if !errors.As(err, &nerr) {
+		t.Fatalf("expected a *netlink.OpError, got: %T", err)
+	}
+
+	var oerr *OpError
+	if !errors.As(err, &oerr) {
+		t.Fatalf("expected an *OpError, got: %T", err)
+	}
+	want := OpError{
+		Op:        "RTM_NEWLINK",
+		Err:       unix.EEXIST,
+		Message:   "Link already exists",
+		Offset:    32,
+		Attribute: "IFLA_IFNAME",
+	}
+	got := *oerr
+	got.err = nil
+	if want != got {
+		t.Fatalf("unexpected error:\n- want: %#v\n-  got: %#v", want, got)
+	}
+
+	const wantMsg = `rtnetlink RTM_NEWLINK: file exists: Link already exists (attribute IFLA_IFNAME)`
+	if got := err.Error(); got != wantMsg {
+		t.Fatalf("unexpected error string:\n- want: %s\n-  got: %s", wantMsg, got)
+	}
+}
+
+func TestConnExecuteSocketError(t *testing.T) {
+	c, tc := testConn(t)
+	tc.err = &netlink.OpError{Op: "receive", Err: os.NewSyscallError("recvmsg", unix.ENOBUFS)}
+
+	_, err := c.Execute(&LinkMessage{}, unix.RTM_GETLINK, netlink.Request|netlink.Dump)
+	if err != tc.err {
+		t.Fatalf("expected socket error to be returned unchanged, got: %v", err)
+	}
+}
+
+func TestOpErrorAttribute(t *testing.T) {
+	skipBigEndian(t)
+
+	ae := netlink.NewAttributeEncoder()
+	ae.String(unix.IFLA_IFNAME, "foo")
+	ae.Nested(unix.IFLA_LINKINFO, func(nae *netlink.AttributeEncoder) error {
+		nae.String(unix.IFLA_INFO_KIND, "vxlan")
+		nae.Nested(unix.IFLA_INFO_DATA, func(nae *netlink.AttributeEncoder) error {
+			nae.Uint32(unix.IFLA_VXLAN_ID, 10)
+			return nil
+		})
+		return nil
+	})
+	attrs, err := ae.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode attributes: %v", err)
+	}
+
+	req := netlink.Message{
+		Header: netlink.Header{Type: unix.RTM_NEWLINK},
+		Data:   append(make([]byte, unix.SizeofIfInfomsg), attrs...),
+	}
+
+	// The request starts at offset 16, its attributes at offset 32:
+	//   32 IFLA_IFNAME
+	//   40 IFLA_LINKINFO
+	//   44   IFLA_INFO_KIND
+	//   56   IFLA_INFO_DATA
+	//   60     IFLA_VXLAN_ID
+	tests := []struct {
+		name   string
+		offset int
+		want   string
+	}{
+		{name: "no offset"},
+		{name: "header", offset: 20},
+		{name: "attribute", offset: 32, want: "IFLA_IFNAME"},
+		{name: "attribute payload", offset: 36, want: "IFLA_IFNAME"},
+		{name: "nested", offset: 40, want: "IFLA_LINKINFO"},
+		{name: "nested attribute", offset: 48, want: "IFLA_LINKINFO/IFLA_INFO_KIND"},
+		{name: "driver attribute", offset: 60, want: "IFLA_LINKINFO/IFLA_INFO_DATA/1"},
+		{name: "out of range", offset: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attributeName(req, tt.offset); tt.want != got {
+				t.Fatalf("unexpected attribute: want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+//go:build linux
+// +build linux
+
+package rtnetlink_test
+
+import (
+	"context"
+	"log"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"golang.org/x/sys/unix"
+)
+
+// Watch links coming and going
+func Example_subscribeLinks() {
+	// Dial a dedicated connection to the rtnetlink socket for notifications
+	conn, err := rtnetlink.Dial(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	sub, err := conn.Subscribe(context.Background(), unix.RTNLGRP_LINK)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for ev := range sub.Events() {
+		switch ev := ev.(type) {
+		case rtnetlink.LinkEvent:
+			log.Printf("link %d %s", ev.Link.Index, ev.Op)
+		case rtnetlink.ResyncEvent:
+			log.Print("notifications were lost, re-dump links")
+		}
+	}
+
+	log.Fatal(sub.Err())
+}
@@ -44,3 +44,59 @@ func FuzzRuleMessage(f *testing.F) {
 		_ = m.UnmarshalBinary(data)
 	})
 }
+
+// FuzzTcMessage will fuzz a TcMessage
+func FuzzTcMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &TcMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
+
+// FuzzNSIDMessage will fuzz a NSIDMessage
+func FuzzNSIDMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &NSIDMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
+
+// FuzzNeighTableMessage will fuzz a NeighTableMessage
+func FuzzNeighTableMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &NeighTableMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
+
+// FuzzNetconfMessage will fuzz a NetconfMessage
+func FuzzNetconfMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &NetconfMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
+
+// FuzzStatsMessage will fuzz a StatsMessage
+func FuzzStatsMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &StatsMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
+
+// FuzzBridgeVlanMessage will fuzz a BridgeVlanMessage
+func FuzzBridgeVlanMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &BridgeVlanMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
+
+// FuzzMDBMessage will fuzz a MDBMessage
+func FuzzMDBMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := &MDBMessage{}
+		_ = m.UnmarshalBinary(data)
+	})
+}
@@ -11,6 +11,7 @@ const (
 	AF_INET                                    = linux.AF_INET
 	AF_INET6                                   = linux.AF_INET6
 	AF_UNSPEC                                  = linux.AF_UNSPEC
+	AF_BRIDGE                                  = linux.AF_BRIDGE
 	NETLINK_ROUTE                              = linux.NETLINK_ROUTE
 	SizeofIfAddrmsg                            = linux.SizeofIfAddrmsg
 	SizeofIfInfomsg                            = linux.SizeofIfInfomsg
@@ -389,11 +390,539 @@ const (
 	O_CLOEXEC                                  = linux.O_CLOEXEC
 	NUD_NONE                                   = linux.NUD_NONE
 	NUD_NOARP                                  = linux.NUD_NOARP
+	ENOBUFS                                    = linux.ENOBUFS
+	RTNLGRP_LINK                               = linux.RTNLGRP_LINK
+	RTNLGRP_NEIGH                              = linux.RTNLGRP_NEIGH
+	RTNLGRP_IPV4_IFADDR                        = linux.RTNLGRP_IPV4_IFADDR
+	RTNLGRP_IPV4_ROUTE                         = linux.RTNLGRP_IPV4_ROUTE
+	RTNLGRP_IPV4_RULE                          = linux.RTNLGRP_IPV4_RULE
+	RTNLGRP_IPV6_IFADDR                        = linux.RTNLGRP_IPV6_IFADDR
+	RTNLGRP_IPV6_ROUTE                         = linux.RTNLGRP_IPV6_ROUTE
+	RTNLGRP_IPV6_RULE                          = linux.RTNLGRP_IPV6_RULE
+	RTNLGRP_NEXTHOP                            = linux.RTNLGRP_NEXTHOP
+	RTM_NEWNEXTHOP                             = linux.RTM_NEWNEXTHOP
+	RTM_DELNEXTHOP                             = linux.RTM_DELNEXTHOP
+	RTM_GETNEXTHOP                             = linux.RTM_GETNEXTHOP
+	SizeofNhmsg                                = linux.SizeofNhmsg
+	NHA_UNSPEC                                 = linux.NHA_UNSPEC
+	NHA_ID                                     = linux.NHA_ID
+	NHA_GROUP                                  = linux.NHA_GROUP
+	NHA_GROUP_TYPE                             = linux.NHA_GROUP_TYPE
+	NHA_BLACKHOLE                              = linux.NHA_BLACKHOLE
+	NHA_OIF                                    = linux.NHA_OIF
+	NHA_GATEWAY                                = linux.NHA_GATEWAY
+	NHA_ENCAP_TYPE                             = linux.NHA_ENCAP_TYPE
+	NHA_ENCAP                                  = linux.NHA_ENCAP
+	NHA_GROUPS                                 = linux.NHA_GROUPS
+	NHA_MASTER                                 = linux.NHA_MASTER
+	RTNH_F_ONLINK                              = linux.RTNH_F_ONLINK
+	RTM_NEWQDISC                               = linux.RTM_NEWQDISC
+	RTM_DELQDISC                               = linux.RTM_DELQDISC
+	RTM_GETQDISC                               = linux.RTM_GETQDISC
+	RTM_NEWTCLASS                              = linux.RTM_NEWTCLASS
+	RTM_DELTCLASS                              = linux.RTM_DELTCLASS
+	RTM_GETTCLASS                              = linux.RTM_GETTCLASS
+	RTM_NEWTFILTER                             = linux.RTM_NEWTFILTER
+	RTM_DELTFILTER                             = linux.RTM_DELTFILTER
+	RTM_GETTFILTER                             = linux.RTM_GETTFILTER
+	RTNLGRP_TC                                 = linux.RTNLGRP_TC
+	TCA_KIND                                   = linux.TCA_KIND
+	TCA_OPTIONS                                = linux.TCA_OPTIONS
+	TCA_STATS2                                 = linux.TCA_STATS2
+	TCA_CHAIN                                  = linux.TCA_CHAIN
+	TCA_HW_OFFLOAD                             = linux.TCA_HW_OFFLOAD
+	TCA_INGRESS_BLOCK                          = linux.TCA_INGRESS_BLOCK
+	TCA_EGRESS_BLOCK                           = linux.TCA_EGRESS_BLOCK
+	ETH_P_ALL                                  = linux.ETH_P_ALL
+	ETH_P_IP                                   = linux.ETH_P_IP
+	ETH_P_IPV6                                 = linux.ETH_P_IPV6
+	IFLA_XDP_SKB_PROG_ID                       = linux.IFLA_XDP_SKB_PROG_ID
+	IFLA_XDP_DRV_PROG_ID                       = linux.IFLA_XDP_DRV_PROG_ID
+	IFLA_XDP_HW_PROG_ID                        = linux.IFLA_XDP_HW_PROG_ID
+	NLA_F_NESTED                               = linux.NLA_F_NESTED
+	NLA_F_NET_BYTEORDER                        = linux.NLA_F_NET_BYTEORDER
+	NDA_MASTER                                 = linux.NDA_MASTER
+	IFLA_LINK_NETNSID                          = linux.IFLA_LINK_NETNSID
+	IFLA_NEW_NETNSID                           = linux.IFLA_NEW_NETNSID
+	IFLA_TARGET_NETNSID                        = linux.IFLA_TARGET_NETNSID
+	RTM_NEWNSID                                = linux.RTM_NEWNSID
+	RTM_DELNSID                                = linux.RTM_DELNSID
+	RTM_GETNSID                                = linux.RTM_GETNSID
+	NETNSA_NONE                                = linux.NETNSA_NONE
+	NETNSA_NSID                                = linux.NETNSA_NSID
+	NETNSA_PID                                 = linux.NETNSA_PID
+	NETNSA_FD                                  = linux.NETNSA_FD
+	NETNSA_TARGET_NSID                         = linux.NETNSA_TARGET_NSID
+	NETNSA_CURRENT_NSID                        = linux.NETNSA_CURRENT_NSID
+	NETNSA_NSID_NOT_ASSIGNED                   = linux.NETNSA_NSID_NOT_ASSIGNED
+	RTNLGRP_NSID                               = linux.RTNLGRP_NSID
+	NDA_VLAN                                   = linux.NDA_VLAN
+	NDA_PORT                                   = linux.NDA_PORT
+	NDA_VNI                                    = linux.NDA_VNI
+	NDA_SRC_VNI                                = linux.NDA_SRC_VNI
+	NTF_USE                                    = linux.NTF_USE
+	NTF_SELF                                   = linux.NTF_SELF
+	NTF_MASTER                                 = linux.NTF_MASTER
+	NTF_EXT_LEARNED                            = linux.NTF_EXT_LEARNED
+	NTF_OFFLOADED                              = linux.NTF_OFFLOADED
+	NTF_ROUTER                                 = linux.NTF_ROUTER
+	NUD_PERMANENT                              = linux.NUD_PERMANENT
+	NUD_REACHABLE                              = linux.NUD_REACHABLE
+	NUD_STALE                                  = linux.NUD_STALE
+	NDA_PROBES                                 = linux.NDA_PROBES
+	RTM_GETNEIGHTBL                            = linux.RTM_GETNEIGHTBL
+	RTM_NEWNEIGHTBL                            = linux.RTM_NEWNEIGHTBL
+	RTM_SETNEIGHTBL                            = linux.RTM_SETNEIGHTBL
+	IFLA_AF_SPEC                               = linux.IFLA_AF_SPEC
+	IFLA_INET_CONF                             = linux.IFLA_INET_CONF
+	IFLA_INET6_FLAGS                           = linux.IFLA_INET6_FLAGS
+	IFLA_INET6_CONF                            = linux.IFLA_INET6_CONF
+	IFLA_INET6_STATS                           = linux.IFLA_INET6_STATS
+	IFLA_INET6_CACHEINFO                       = linux.IFLA_INET6_CACHEINFO
+	IFLA_INET6_ICMP6STATS                      = linux.IFLA_INET6_ICMP6STATS
+	IFLA_INET6_TOKEN                           = linux.IFLA_INET6_TOKEN
+	IFLA_INET6_ADDR_GEN_MODE                   = linux.IFLA_INET6_ADDR_GEN_MODE
+	IFLA_INET6_RA_MTU                          = linux.IFLA_INET6_RA_MTU
+	RTM_NEWNETCONF                             = linux.RTM_NEWNETCONF
+	RTM_DELNETCONF                             = linux.RTM_DELNETCONF
+	RTM_GETNETCONF                             = linux.RTM_GETNETCONF
+	RTNLGRP_IPV4_NETCONF                       = linux.RTNLGRP_IPV4_NETCONF
+	RTNLGRP_IPV6_NETCONF                       = linux.RTNLGRP_IPV6_NETCONF
+	AF_MPLS                                    = linux.AF_MPLS
+	IFLA_STATS_LINK_64                         = linux.IFLA_STATS_LINK_64
+	IFLA_STATS_LINK_XSTATS                     = linux.IFLA_STATS_LINK_XSTATS
+	IFLA_STATS_LINK_XSTATS_SLAVE               = linux.IFLA_STATS_LINK_XSTATS_SLAVE
+	IFLA_STATS_LINK_OFFLOAD_XSTATS             = linux.IFLA_STATS_LINK_OFFLOAD_XSTATS
+	IFLA_STATS_AF_SPEC                         = linux.IFLA_STATS_AF_SPEC
+	IFLA_STATS_GET_FILTERS                     = linux.IFLA_STATS_GET_FILTERS
+	IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS     = linux.IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS
+	IFLA_OFFLOAD_XSTATS_CPU_HIT                = linux.IFLA_OFFLOAD_XSTATS_CPU_HIT
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO              = linux.IFLA_OFFLOAD_XSTATS_HW_S_INFO
+	IFLA_OFFLOAD_XSTATS_L3_STATS               = linux.IFLA_OFFLOAD_XSTATS_L3_STATS
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO_REQUEST      = linux.IFLA_OFFLOAD_XSTATS_HW_S_INFO_REQUEST
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO_USED         = linux.IFLA_OFFLOAD_XSTATS_HW_S_INFO_USED
+	RTM_NEWSTATS                               = linux.RTM_NEWSTATS
+	RTM_GETSTATS                               = linux.RTM_GETSTATS
+	RTM_SETSTATS                               = linux.RTM_SETSTATS
+	RTM_NEWVLAN                                = linux.RTM_NEWVLAN
+	RTM_DELVLAN                                = linux.RTM_DELVLAN
+	RTM_GETVLAN                                = linux.RTM_GETVLAN
+	RTNLGRP_BRVLAN                             = linux.RTNLGRP_BRVLAN
+	RTM_NEWMDB                                 = linux.RTM_NEWMDB
+	RTM_DELMDB                                 = linux.RTM_DELMDB
+	RTM_GETMDB                                 = linux.RTM_GETMDB
+	RTNLGRP_MDB                                = linux.RTNLGRP_MDB
+	MCAST_EXCLUDE                              = linux.MCAST_EXCLUDE
+	MCAST_INCLUDE                              = linux.MCAST_INCLUDE
+	IPPROTO_IPIP                               = linux.IPPROTO_IPIP
+	IPPROTO_IPV6                               = linux.IPPROTO_IPV6
+	IPPROTO_MPLS                               = linux.IPPROTO_MPLS
+	IFLA_GENEVE_ID                             = linux.IFLA_GENEVE_ID
+	IFLA_GENEVE_REMOTE                         = linux.IFLA_GENEVE_REMOTE
+	IFLA_GENEVE_TTL                            = linux.IFLA_GENEVE_TTL
+	IFLA_GENEVE_TOS                            = linux.IFLA_GENEVE_TOS
+	IFLA_GENEVE_PORT                           = linux.IFLA_GENEVE_PORT
+	IFLA_GENEVE_COLLECT_METADATA               = linux.IFLA_GENEVE_COLLECT_METADATA
+	IFLA_GENEVE_REMOTE6                        = linux.IFLA_GENEVE_REMOTE6
+	IFLA_GENEVE_UDP_CSUM                       = linux.IFLA_GENEVE_UDP_CSUM
+	IFLA_GENEVE_UDP_ZERO_CSUM6_TX              = linux.IFLA_GENEVE_UDP_ZERO_CSUM6_TX
+	IFLA_GENEVE_UDP_ZERO_CSUM6_RX              = linux.IFLA_GENEVE_UDP_ZERO_CSUM6_RX
+	IFLA_GENEVE_LABEL                          = linux.IFLA_GENEVE_LABEL
+	IFLA_GENEVE_TTL_INHERIT                    = linux.IFLA_GENEVE_TTL_INHERIT
+	IFLA_GENEVE_DF                             = linux.IFLA_GENEVE_DF
+	IFLA_GENEVE_INNER_PROTO_INHERIT            = linux.IFLA_GENEVE_INNER_PROTO_INHERIT
+	IFLA_VRF_TABLE                             = linux.IFLA_VRF_TABLE
+	IFLA_VRF_PORT_TABLE                        = linux.IFLA_VRF_PORT_TABLE
+	IFLA_IPVLAN_MODE                           = linux.IFLA_IPVLAN_MODE
+	IFLA_IPVLAN_FLAGS                          = linux.IFLA_IPVLAN_FLAGS
 )
 
 const (
-	RTEXT_FILTER_VF         = 1 << iota
-	RTEXT_FILTER_SKIP_STATS = 0x8
+	RTEXT_FILTER_VF                = 1 << iota
+	RTEXT_FILTER_SKIP_STATS        = 0x8
+	NHA_FDB                        = 0xb
+	NHA_RES_GROUP                  = 0xc
+	NHA_RES_GROUP_BUCKETS          = 0x1
+	NHA_RES_GROUP_IDLE_TIMER       = 0x2
+	NHA_RES_GROUP_UNBALANCED_TIMER = 0x3
+	NHA_RES_GROUP_UNBALANCED_TIME  = 0x4
+	NEXTHOP_GRP_TYPE_MPATH         = 0x0
+	NEXTHOP_GRP_TYPE_RES           = 0x1
+	RTA_NH_ID                      = 0x1e
+	TCA_STATS_BASIC                = 0x1
+	TCA_STATS_RATE_EST             = 0x2
+	TCA_STATS_QUEUE                = 0x3
+	TC_H_ROOT                      = 0xffffffff
+	TC_H_INGRESS                   = 0xfffffff1
+	TC_H_CLSACT                    = 0xfffffff1
+	TC_H_MIN_INGRESS               = 0xfff2
+	TC_H_MIN_EGRESS                = 0xfff3
+	TC_H_MAJ_MASK                  = 0xffff0000
+	TC_H_MIN_MASK                  = 0xffff
+	TC_LINKLAYER_ETHERNET          = 0x1
+	TCA_HTB_PARMS                  = 0x1
+	TCA_HTB_INIT                   = 0x2
+	TCA_HTB_DIRECT_QLEN            = 0x5
+	TCA_HTB_RATE64                 = 0x6
+	TCA_HTB_CEIL64                 = 0x7
+	TCA_HTB_OFFLOAD                = 0x9
+	TC_HTB_PROTOVER                = 0x3
+	TCA_TBF_PARMS                  = 0x1
+	TCA_TBF_RATE64                 = 0x4
+	TCA_TBF_PRATE64                = 0x5
+	TCA_TBF_BURST                  = 0x6
+	TCA_TBF_PBURST                 = 0x7
+	TCA_FQ_PLIMIT                  = 0x1
+	TCA_FQ_FLOW_PLIMIT             = 0x2
+	TCA_FQ_QUANTUM                 = 0x3
+	TCA_FQ_INITIAL_QUANTUM         = 0x4
+	TCA_FQ_RATE_ENABLE             = 0x5
+	TCA_FQ_FLOW_MAX_RATE           = 0x7
+	TCA_FQ_BUCKETS_LOG             = 0x8
+	TCA_FQ_FLOW_REFILL_DELAY       = 0x9
+	TCA_FQ_ORPHAN_MASK             = 0xa
+	TCA_FQ_LOW_RATE_THRESHOLD      = 0xb
+	TCA_FQ_CE_THRESHOLD            = 0xc
+	TCA_FQ_TIMER_SLACK             = 0xd
+	TCA_FQ_HORIZON                 = 0xe
+	TCA_FQ_HORIZON_DROP            = 0xf
+	TCA_FQ_CODEL_TARGET            = 0x1
+	TCA_FQ_CODEL_LIMIT             = 0x2
+	TCA_FQ_CODEL_INTERVAL          = 0x3
+	TCA_FQ_CODEL_ECN               = 0x4
+	TCA_FQ_CODEL_FLOWS             = 0x5
+	TCA_FQ_CODEL_QUANTUM           = 0x6
+	TCA_FQ_CODEL_CE_THRESHOLD      = 0x7
+	TCA_FQ_CODEL_DROP_BATCH_SIZE   = 0x8
+	TCA_FQ_CODEL_MEMORY_LIMIT      = 0x9
+	TCA_NETEM_CORR                 = 0x1
+	TCA_NETEM_REORDER              = 0x3
+	TCA_NETEM_CORRUPT              = 0x4
+	TCA_NETEM_RATE                 = 0x6
+	TCA_NETEM_ECN                  = 0x7
+	TCA_NETEM_RATE64               = 0x8
+	TCA_NETEM_LATENCY64            = 0xa
+	TCA_NETEM_JITTER64             = 0xb
+	TCA_BPF_CLASSID                = 0x3
+	TCA_BPF_FD                     = 0x6
+	TCA_BPF_NAME                   = 0x7
+	TCA_BPF_FLAGS                  = 0x8
+	TCA_BPF_FLAGS_GEN              = 0x9
+	TCA_BPF_TAG                    = 0xa
+	TCA_BPF_ID                     = 0xb
+	TCA_BPF_FLAG_ACT_DIRECT        = 0x1
+	TCA_CLS_FLAGS_SKIP_HW          = 0x1
+	TCA_CLS_FLAGS_SKIP_SW          = 0x2
+	TCA_U32_CLASSID                = 0x1
+	TCA_U32_HASH                   = 0x2
+	TCA_U32_LINK                   = 0x3
+	TCA_U32_DIVISOR                = 0x4
+	TCA_U32_SEL                    = 0x5
+	TCA_U32_INDEV                  = 0x8
+	TCA_U32_FLAGS                  = 0xb
+	TC_U32_TERMINAL                = 0x1
+	TC_U32_OFFSET                  = 0x2
+	TC_U32_VAROFFSET               = 0x4
+	TC_U32_EAT                     = 0x8
+	TCA_FLOWER_CLASSID             = 0x1
+	TCA_FLOWER_INDEV               = 0x2
+	TCA_FLOWER_KEY_ETH_DST         = 0x4
+	TCA_FLOWER_KEY_ETH_DST_MASK    = 0x5
+	TCA_FLOWER_KEY_ETH_SRC         = 0x6
+	TCA_FLOWER_KEY_ETH_SRC_MASK    = 0x7
+	TCA_FLOWER_KEY_ETH_TYPE        = 0x8
+	TCA_FLOWER_KEY_IP_PROTO        = 0x9
+	TCA_FLOWER_KEY_IPV4_SRC        = 0xa
+	TCA_FLOWER_KEY_IPV4_SRC_MASK   = 0xb
+	TCA_FLOWER_KEY_IPV4_DST        = 0xc
+	TCA_FLOWER_KEY_IPV4_DST_MASK   = 0xd
+	TCA_FLOWER_KEY_IPV6_SRC        = 0xe
+	TCA_FLOWER_KEY_IPV6_SRC_MASK   = 0xf
+	TCA_FLOWER_KEY_IPV6_DST        = 0x10
+	TCA_FLOWER_KEY_IPV6_DST_MASK   = 0x11
+	TCA_FLOWER_KEY_TCP_SRC         = 0x12
+	TCA_FLOWER_KEY_TCP_DST         = 0x13
+	TCA_FLOWER_KEY_UDP_SRC         = 0x14
+	TCA_FLOWER_KEY_UDP_DST         = 0x15
+	TCA_FLOWER_FLAGS               = 0x16
+	TCA_FLOWER_KEY_VLAN_ID         = 0x17
+	TCA_FLOWER_KEY_VLAN_PRIO       = 0x18
+	TCA_MATCHALL_CLASSID           = 0x1
+	TCA_MATCHALL_FLAGS             = 0x3
+	SizeofTcMsg                    = 0x14
+	XDP_ATTACHED_NONE              = 0x0
+	XDP_ATTACHED_DRV               = 0x1
+	XDP_ATTACHED_SKB               = 0x2
+	XDP_ATTACHED_HW                = 0x3
+	XDP_ATTACHED_MULTI             = 0x4
+	IFLA_NETKIT_SCRUB              = 0x6
+	IFLA_NETKIT_PEER_SCRUB         = 0x7
+	IFLA_NETKIT_HEADROOM           = 0x8
+	IFLA_NETKIT_TAILROOM           = 0x9
+	NETKIT_SCRUB_NONE              = 0x0
+	NETKIT_SCRUB_DEFAULT           = 0x1
+	NDA_FDB_EXT_ATTRS              = 0xe
+	NFEA_ACTIVITY_NOTIFY           = 0x1
+	NFEA_DONT_REFRESH              = 0x2
+	NTF_STICKY                     = 0x40
+	FDB_NOTIFY_BIT                 = 0x1
+	FDB_NOTIFY_INACTIVE_BIT        = 0x2
+	NDA_PROTOCOL                   = 0xc
+	NDA_NH_ID                      = 0xd
+	NDA_FLAGS_EXT                  = 0xf
+	NTF_EXT_MANAGED                = 0x1
+	NTF_EXT_LOCKED                 = 0x2
+	NDTA_NAME                      = 0x1
+	NDTA_THRESH1                   = 0x2
+	NDTA_THRESH2                   = 0x3
+	NDTA_THRESH3                   = 0x4
+	NDTA_CONFIG                    = 0x5
+	NDTA_PARMS                     = 0x6
+	NDTA_STATS                     = 0x7
+	NDTA_GC_INTERVAL               = 0x8
+	NDTA_PAD                       = 0x9
+	NDTPA_IFINDEX                  = 0x1
+	NDTPA_REFCNT                   = 0x2
+	NDTPA_REACHABLE_TIME           = 0x3
+	NDTPA_BASE_REACHABLE_TIME      = 0x4
+	NDTPA_RETRANS_TIME             = 0x5
+	NDTPA_GC_STALETIME             = 0x6
+	NDTPA_DELAY_PROBE_TIME         = 0x7
+	NDTPA_QUEUE_LEN                = 0x8
+	NDTPA_APP_PROBES               = 0x9
+	NDTPA_UCAST_PROBES             = 0xa
+	NDTPA_MCAST_PROBES             = 0xb
+	NDTPA_ANYCAST_DELAY            = 0xc
+	NDTPA_PROXY_DELAY              = 0xd
+	NDTPA_PROXY_QLEN               = 0xe
+	NDTPA_LOCKTIME                 = 0xf
+	NDTPA_QUEUE_LENBYTES           = 0x10
+	NDTPA_MCAST_REPROBES           = 0x11
+	NDTPA_PAD                      = 0x12
+	NDTPA_INTERVAL_PROBE_TIME_MS   = 0x13
+	RTEXT_FILTER_BRVLAN            = 0x2
+	RTEXT_FILTER_BRVLAN_COMPRESSED = 0x4
+)
+
+// Values of attributes and structures which are not available in
+// golang.org/x/sys/unix.
+const (
+	NETCONFA_IFINDEX                          = 0x1
+	NETCONFA_FORWARDING                       = 0x2
+	NETCONFA_RP_FILTER                        = 0x3
+	NETCONFA_MC_FORWARDING                    = 0x4
+	NETCONFA_PROXY_NEIGH                      = 0x5
+	NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN      = 0x6
+	NETCONFA_INPUT                            = 0x7
+	NETCONFA_BC_FORWARDING                    = 0x8
+	NETCONFA_IFINDEX_ALL                      = -0x1
+	NETCONFA_IFINDEX_DEFAULT                  = -0x2
+	IN6_ADDR_GEN_MODE_EUI64                   = 0x0
+	IN6_ADDR_GEN_MODE_NONE                    = 0x1
+	IN6_ADDR_GEN_MODE_STABLE_PRIVACY          = 0x2
+	IN6_ADDR_GEN_MODE_RANDOM                  = 0x3
+	IPV4_DEVCONF_FORWARDING                   = 0x1
+	IPV4_DEVCONF_MC_FORWARDING                = 0x2
+	IPV4_DEVCONF_PROXY_ARP                    = 0x3
+	IPV4_DEVCONF_ACCEPT_REDIRECTS             = 0x4
+	IPV4_DEVCONF_SECURE_REDIRECTS             = 0x5
+	IPV4_DEVCONF_SEND_REDIRECTS               = 0x6
+	IPV4_DEVCONF_SHARED_MEDIA                 = 0x7
+	IPV4_DEVCONF_RP_FILTER                    = 0x8
+	IPV4_DEVCONF_ACCEPT_SOURCE_ROUTE          = 0x9
+	IPV4_DEVCONF_BOOTP_RELAY                  = 0xa
+	IPV4_DEVCONF_LOG_MARTIANS                 = 0xb
+	IPV4_DEVCONF_TAG                          = 0xc
+	IPV4_DEVCONF_ARPFILTER                    = 0xd
+	IPV4_DEVCONF_MEDIUM_ID                    = 0xe
+	IPV4_DEVCONF_NOXFRM                       = 0xf
+	IPV4_DEVCONF_NOPOLICY                     = 0x10
+	IPV4_DEVCONF_FORCE_IGMP_VERSION           = 0x11
+	IPV4_DEVCONF_ARP_ANNOUNCE                 = 0x12
+	IPV4_DEVCONF_ARP_IGNORE                   = 0x13
+	IPV4_DEVCONF_PROMOTE_SECONDARIES          = 0x14
+	IPV4_DEVCONF_ARP_ACCEPT                   = 0x15
+	IPV4_DEVCONF_ARP_NOTIFY                   = 0x16
+	IPV4_DEVCONF_ACCEPT_LOCAL                 = 0x17
+	IPV4_DEVCONF_SRC_VMARK                    = 0x18
+	IPV4_DEVCONF_PROXY_ARP_PVLAN              = 0x19
+	IPV4_DEVCONF_ROUTE_LOCALNET               = 0x1a
+	IPV4_DEVCONF_IGNORE_ROUTES_WITH_LINKDOWN  = 0x1d
+	IPV4_DEVCONF_DROP_UNICAST_IN_L2_MULTICAST = 0x1e
+	IPV4_DEVCONF_DROP_GRATUITOUS_ARP          = 0x1f
+	IPV4_DEVCONF_BC_FORWARDING                = 0x20
+	IPV4_DEVCONF_ARP_EVICT_NOCARRIER          = 0x21
+	DEVCONF_FORWARDING                        = 0x0
+	DEVCONF_HOPLIMIT                          = 0x1
+	DEVCONF_MTU6                              = 0x2
+	DEVCONF_ACCEPT_RA                         = 0x3
+	DEVCONF_ACCEPT_REDIRECTS                  = 0x4
+	DEVCONF_AUTOCONF                          = 0x5
+	DEVCONF_PROXY_NDP                         = 0x16
+	DEVCONF_MC_FORWARDING                     = 0x19
+	DEVCONF_DISABLE_IPV6                      = 0x1a
+	DEVCONF_ACCEPT_DAD                        = 0x1b
+	DEVCONF_IGNORE_ROUTES_WITH_LINKDOWN       = 0x27
+	DEVCONF_KEEP_ADDR_ON_DOWN                 = 0x2a
+	DEVCONF_ADDR_GEN_MODE                     = 0x2f
+	LINK_XSTATS_TYPE_BRIDGE                   = 0x1
+	LINK_XSTATS_TYPE_BOND                     = 0x2
+	BRIDGE_XSTATS_VLAN                        = 0x1
+	BRIDGE_XSTATS_MCAST                       = 0x2
+	BRIDGE_XSTATS_STP                         = 0x4
+	BOND_XSTATS_3AD                           = 0x1
+	BOND_3AD_STAT_LACPDU_RX                   = 0x0
+	BOND_3AD_STAT_LACPDU_TX                   = 0x1
+	BOND_3AD_STAT_LACPDU_UNKNOWN_RX           = 0x2
+	BOND_3AD_STAT_LACPDU_ILLEGAL_RX           = 0x3
+	BOND_3AD_STAT_MARKER_RX                   = 0x4
+	BOND_3AD_STAT_MARKER_TX                   = 0x5
+	BOND_3AD_STAT_MARKER_RESP_RX              = 0x6
+	BOND_3AD_STAT_MARKER_RESP_TX              = 0x7
+	BOND_3AD_STAT_MARKER_UNKNOWN_RX           = 0x8
+	MPLS_STATS_LINK                           = 0x1
+	BRIDGE_VLAN_INFO_MASTER                   = 0x1
+	BRIDGE_VLAN_INFO_PVID                     = 0x2
+	BRIDGE_VLAN_INFO_UNTAGGED                 = 0x4
+	BRIDGE_VLAN_INFO_RANGE_BEGIN              = 0x8
+	BRIDGE_VLAN_INFO_RANGE_END                = 0x10
+	BRIDGE_VLAN_INFO_BRENTRY                  = 0x20
+	BRIDGE_VLAN_INFO_ONLY_OPTS                = 0x40
+	IFLA_BRIDGE_FLAGS                         = 0x0
+	IFLA_BRIDGE_MODE                          = 0x1
+	IFLA_BRIDGE_VLAN_INFO                     = 0x2
+	IFLA_BRIDGE_VLAN_TUNNEL_INFO              = 0x3
+	IFLA_BRIDGE_VLAN_TUNNEL_ID                = 0x1
+	IFLA_BRIDGE_VLAN_TUNNEL_VID               = 0x2
+	IFLA_BRIDGE_VLAN_TUNNEL_FLAGS             = 0x3
+	BRIDGE_FLAGS_MASTER                       = 0x1
+	BRIDGE_FLAGS_SELF                         = 0x2
+	BRIDGE_MODE_VEB                           = 0x0
+	BRIDGE_MODE_VEPA                          = 0x1
+	BRIDGE_VLANDB_ENTRY                       = 0x1
+	BRIDGE_VLANDB_DUMP_FLAGS                  = 0x1
+	BRIDGE_VLANDB_DUMPF_STATS                 = 0x1
+	BRIDGE_VLANDB_ENTRY_INFO                  = 0x1
+	BRIDGE_VLANDB_ENTRY_RANGE                 = 0x2
+	BRIDGE_VLANDB_ENTRY_STATE                 = 0x3
+	BRIDGE_VLANDB_ENTRY_TUNNEL_INFO           = 0x4
+	BRIDGE_VLANDB_ENTRY_STATS                 = 0x5
+	BRIDGE_VLANDB_ENTRY_MCAST_ROUTER          = 0x6
+	BRIDGE_VLANDB_ENTRY_MCAST_N_GROUPS        = 0x7
+	BRIDGE_VLANDB_ENTRY_MCAST_MAX_GROUPS      = 0x8
+	BRIDGE_VLANDB_ENTRY_NEIGH_SUPPRESS        = 0x9
+	BRIDGE_VLANDB_TINFO_ID                    = 0x1
+	BRIDGE_VLANDB_TINFO_CMD                   = 0x2
+	BRIDGE_VLANDB_STATS_RX_BYTES              = 0x1
+	BRIDGE_VLANDB_STATS_RX_PACKETS            = 0x2
+	BRIDGE_VLANDB_STATS_TX_BYTES              = 0x3
+	BRIDGE_VLANDB_STATS_TX_PACKETS            = 0x4
+	MDBA_MDB                                  = 0x1
+	MDBA_ROUTER                               = 0x2
+	MDBA_MDB_ENTRY                            = 0x1
+	MDBA_MDB_ENTRY_INFO                       = 0x1
+	MDBA_MDB_EATTR_TIMER                      = 0x1
+	MDBA_MDB_EATTR_SRC_LIST                   = 0x2
+	MDBA_MDB_EATTR_GROUP_MODE                 = 0x3
+	MDBA_MDB_EATTR_SOURCE                     = 0x4
+	MDBA_MDB_EATTR_RTPROT                     = 0x5
+	MDBA_MDB_EATTR_DST                        = 0x6
+	MDBA_MDB_EATTR_DST_PORT                   = 0x7
+	MDBA_MDB_EATTR_VNI                        = 0x8
+	MDBA_MDB_EATTR_IFINDEX                    = 0x9
+	MDBA_MDB_EATTR_SRC_VNI                    = 0xa
+	MDBA_MDB_SRCLIST_ENTRY                    = 0x1
+	MDBA_MDB_SRCATTR_ADDRESS                  = 0x1
+	MDBA_MDB_SRCATTR_TIMER                    = 0x2
+	MDBA_ROUTER_PORT                          = 0x1
+	MDBA_ROUTER_PATTR_TIMER                   = 0x1
+	MDBA_ROUTER_PATTR_TYPE                    = 0x2
+	MDBA_ROUTER_PATTR_INET_TIMER              = 0x3
+	MDBA_ROUTER_PATTR_INET6_TIMER             = 0x4
+	MDBA_ROUTER_PATTR_VID                     = 0x5
+	MDBA_SET_ENTRY                            = 0x1
+	MDBA_SET_ENTRY_ATTRS                      = 0x2
+	MDBE_ATTR_SOURCE                          = 0x1
+	MDBE_ATTR_SRC_LIST                        = 0x2
+	MDBE_ATTR_GROUP_MODE                      = 0x3
+	MDBE_ATTR_RTPROT                          = 0x4
+	MDBE_ATTR_DST                             = 0x5
+	MDBE_ATTR_DST_PORT                        = 0x6
+	MDBE_ATTR_VNI                             = 0x7
+	MDBE_ATTR_IFINDEX                         = 0x8
+	MDBE_ATTR_SRC_VNI                         = 0x9
+	MDBE_SRC_LIST_ENTRY                       = 0x1
+	MDBE_SRCATTR_ADDRESS                      = 0x1
+	MDB_TEMPORARY                             = 0x0
+	MDB_PERMANENT                             = 0x1
+	MDB_FLAGS_OFFLOAD                         = 0x1
+	MDB_FLAGS_FAST_LEAVE                      = 0x2
+	MDB_FLAGS_STAR_EXCL                       = 0x4
+	MDB_FLAGS_BLOCKED                         = 0x8
+	MDB_FLAGS_OFFLOAD_FAILED                  = 0x10
+	MDB_RTR_TYPE_DISABLED                     = 0x0
+	MDB_RTR_TYPE_TEMP_QUERY                   = 0x1
+	MDB_RTR_TYPE_PERM                         = 0x2
+	MDB_RTR_TYPE_TEMP                         = 0x3
+	IFLA_GRE_LINK                             = 0x1
+	IFLA_GRE_IFLAGS                           = 0x2
+	IFLA_GRE_OFLAGS                           = 0x3
+	IFLA_GRE_IKEY                             = 0x4
+	IFLA_GRE_OKEY                             = 0x5
+	IFLA_GRE_LOCAL                            = 0x6
+	IFLA_GRE_REMOTE                           = 0x7
+	IFLA_GRE_TTL                              = 0x8
+	IFLA_GRE_TOS                              = 0x9
+	IFLA_GRE_PMTUDISC                         = 0xa
+	IFLA_GRE_ENCAP_LIMIT                      = 0xb
+	IFLA_GRE_FLOWINFO                         = 0xc
+	IFLA_GRE_FLAGS                            = 0xd
+	IFLA_GRE_ENCAP_TYPE                       = 0xe
+	IFLA_GRE_ENCAP_FLAGS                      = 0xf
+	IFLA_GRE_ENCAP_SPORT                      = 0x10
+	IFLA_GRE_ENCAP_DPORT                      = 0x11
+	IFLA_GRE_COLLECT_METADATA                 = 0x12
+	IFLA_GRE_IGNORE_DF                        = 0x13
+	IFLA_GRE_FWMARK                           = 0x14
+	IFLA_GRE_ERSPAN_INDEX                     = 0x15
+	IFLA_GRE_ERSPAN_VER                       = 0x16
+	IFLA_GRE_ERSPAN_DIR                       = 0x17
+	IFLA_GRE_ERSPAN_HWID                      = 0x18
+	TUNNEL_ENCAP_NONE                         = 0x0
+	TUNNEL_ENCAP_FOU                          = 0x1
+	TUNNEL_ENCAP_GUE                          = 0x2
+	TUNNEL_ENCAP_MPLS                         = 0x3
+	TUNNEL_ENCAP_FLAG_CSUM                    = 0x1
+	TUNNEL_ENCAP_FLAG_CSUM6                   = 0x2
+	TUNNEL_ENCAP_FLAG_REMCSUM                 = 0x4
+	IP6_TNL_F_IGN_ENCAP_LIMIT                 = 0x1
+	IP6_TNL_F_USE_ORIG_TCLASS                 = 0x2
+	IP6_TNL_F_USE_ORIG_FLOWLABEL              = 0x4
+	IP6_TNL_F_MIP6_DEV                        = 0x8
+	IP6_TNL_F_RCV_DSCP_COPY                   = 0x10
+	IP6_TNL_F_USE_ORIG_FWMARK                 = 0x20
+	IP6_TNL_F_ALLOW_LOCAL_REMOTE              = 0x40
+	IFLA_IPTUN_LINK                           = 0x1
+	IFLA_IPTUN_LOCAL                          = 0x2
+	IFLA_IPTUN_REMOTE                         = 0x3
+	IFLA_IPTUN_TTL                            = 0x4
+	IFLA_IPTUN_TOS                            = 0x5
+	IFLA_IPTUN_ENCAP_LIMIT                    = 0x6
+	IFLA_IPTUN_FLOWINFO                       = 0x7
+	IFLA_IPTUN_FLAGS                          = 0x8
+	IFLA_IPTUN_PROTO                          = 0x9
+	IFLA_IPTUN_PMTUDISC                       = 0xa
+	IFLA_IPTUN_6RD_PREFIX                     = 0xb
+	IFLA_IPTUN_6RD_RELAY_PREFIX               = 0xc
+	IFLA_IPTUN_6RD_PREFIXLEN                  = 0xd
+	IFLA_IPTUN_6RD_RELAY_PREFIXLEN            = 0xe
+	IFLA_IPTUN_ENCAP_TYPE                     = 0xf
+	IFLA_IPTUN_ENCAP_FLAGS                    = 0x10
+	IFLA_IPTUN_ENCAP_SPORT                    = 0x11
+	IFLA_IPTUN_ENCAP_DPORT                    = 0x12
+	IFLA_IPTUN_COLLECT_METADATA               = 0x13
+	IFLA_IPTUN_FWMARK                         = 0x14
+	SIT_ISATAP                                = 0x1
 )
 
 var Gettid = linux.Gettid
@@ -1,8 +1,10 @@
 package rtnetlink
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net"
 
 	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
@@ -22,7 +24,8 @@ var _ Message = &LinkMessage{}
 
 // A LinkMessage is a route netlink link message.
 type LinkMessage struct {
-	// Always set to AF_UNSPEC (0)
+	// Always set to AF_UNSPEC (0), except for AF_BRIDGE when dealing with the
+	// bridge specific configuration of a bridge port, see BridgeVlanService.
 	Family uint16
 
 	// Device Type
@@ -50,7 +53,7 @@ type LinkMessage struct {
 func (m *LinkMessage) MarshalBinary() ([]byte, error) {
 	b := make([]byte, unix.SizeofIfInfomsg)
 
-	b[0] = 0 // Family
+	b[0] = uint8(m.Family)
 	b[1] = 0 // reserved
 	nativeEndian.PutUint16(b[2:4], m.Type)
 	nativeEndian.PutUint32(b[4:8], m.Index)
@@ -68,7 +71,7 @@ func (m *LinkMessage) MarshalBinary() ([]byte, error) {
 
 		ae := netlink.NewAttributeEncoder()
 		ae.ByteOrder = nativeEndian
-		err := m.Attributes.encode(ae)
+		err := m.Attributes.encode(ae, m.Family)
 		if err != nil {
 			return nil, err
 		}
@@ -104,7 +107,7 @@ func (m *LinkMessage) UnmarshalBinary(b []byte) error {
 			return err
 		}
 		ad.ByteOrder = nativeEndian
-		err = m.Attributes.decode(ad)
+		err = m.Attributes.decode(ad, m.Family)
 		if err != nil {
 			return err
 		}
@@ -122,8 +125,8 @@ type LinkService struct {
 }
 
 // execute executes the request and returns the messages as a LinkMessage slice
-func (l *LinkService) execute(m Message, family uint16, flags netlink.HeaderFlags) ([]LinkMessage, error) {
-	msgs, err := l.c.Execute(m, family, flags)
+func (l *LinkService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]LinkMessage, error) {
+	msgs, err := l.c.ExecuteContext(ctx, m, family, flags)
 
 	links := make([]LinkMessage, len(msgs))
 	for i, msg := range msgs {
@@ -137,32 +140,47 @@ func (l *LinkService) execute(m Message, family uint16, flags netlink.HeaderFlag
 
 // New creates a new interface using the LinkMessage information.
 func (l *LinkService) New(req *LinkMessage) error {
+	return l.NewContext(context.Background(), req)
+}
+
+// NewContext is like New, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) NewContext(ctx context.Context, req *LinkMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
-	_, err := l.execute(req, unix.RTM_NEWLINK, flags)
+	_, err := l.execute(ctx, req, unix.RTM_NEWLINK, flags)
 
 	return err
 }
 
 // Delete removes an interface by index.
 func (l *LinkService) Delete(index uint32) error {
+	return l.DeleteContext(context.Background(), index)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) DeleteContext(ctx context.Context, index uint32) error {
 	req := &LinkMessage{
 		Index: index,
 	}
 
 	flags := netlink.Request | netlink.Acknowledge
-	_, err := l.c.Execute(req, unix.RTM_DELLINK, flags)
+	_, err := l.c.ExecuteContext(ctx, req, unix.RTM_DELLINK, flags)
 
 	return err
 }
 
 // Get retrieves interface information by index.
 func (l *LinkService) Get(index uint32) (LinkMessage, error) {
+	return l.GetContext(context.Background(), index)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) GetContext(ctx context.Context, index uint32) (LinkMessage, error) {
 	req := &LinkMessage{
 		Index: index,
 	}
 
 	flags := netlink.Request | netlink.DumpFiltered
-	links, err := l.execute(req, unix.RTM_GETLINK, flags)
+	links, err := l.execute(ctx, req, unix.RTM_GETLINK, flags)
 
 	if len(links) != 1 {
 		return LinkMessage{}, fmt.Errorf("too many/little matches, expected 1, actual %d", len(links))
@@ -181,8 +199,13 @@ func (l *LinkService) Get(index uint32) (LinkMessage, error) {
 //   - using RTM_NEWLINK is the preferred way to create AND update links
 //   - RTM_NEWLINK is backward compatible to RTM_SETLINK
 func (l *LinkService) Set(req *LinkMessage) error {
+	return l.SetContext(context.Background(), req)
+}
+
+// SetContext is like Set, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) SetContext(ctx context.Context, req *LinkMessage) error {
 	flags := netlink.Request | netlink.Acknowledge
-	_, err := l.c.Execute(req, unix.RTM_NEWLINK, flags)
+	_, err := l.c.ExecuteContext(ctx, req, unix.RTM_NEWLINK, flags)
 
 	return err
 }
@@ -201,8 +224,13 @@ func (l *LinkService) Set(req *LinkMessage) error {
 //
 // To remove an interface from its master, use RemoveMaster instead.
 func (l *LinkService) SetMaster(ifaceIndex, masterIndex uint32, slaveConfig LinkSlaveDriver) error {
+	return l.SetMasterContext(context.Background(), ifaceIndex, masterIndex, slaveConfig)
+}
+
+// SetMasterContext is like SetMaster, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) SetMasterContext(ctx context.Context, ifaceIndex, masterIndex uint32, slaveConfig LinkSlaveDriver) error {
 	// Get current interface state
-	rx, err := l.Get(ifaceIndex)
+	rx, err := l.GetContext(ctx, ifaceIndex)
 	if err != nil {
 		return err
 	}
@@ -234,7 +262,7 @@ func (l *LinkService) SetMaster(ifaceIndex, masterIndex uint32, slaveConfig Link
 		Change:     0,
 		Attributes: attrs,
 	}
-	return l.Set(tx)
+	return l.SetContext(ctx, tx)
 }
 
 // RemoveMaster un-enslaves an interface from its master device.
@@ -244,22 +272,50 @@ func (l *LinkService) SetMaster(ifaceIndex, masterIndex uint32, slaveConfig Link
 //
 //	err := conn.Link.RemoveMaster(ifaceIndex)
 func (l *LinkService) RemoveMaster(ifaceIndex uint32) error {
-	return l.SetMaster(ifaceIndex, 0, nil)
+	return l.RemoveMasterContext(context.Background(), ifaceIndex)
+}
+
+// RemoveMasterContext is like RemoveMaster, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) RemoveMasterContext(ctx context.Context, ifaceIndex uint32) error {
+	return l.SetMasterContext(ctx, ifaceIndex, 0, nil)
+}
+
+// SetAFSpec writes the IPv4 and IPv6 specific attributes present in change
+// to the interface with the given index.
+func (l *LinkService) SetAFSpec(index uint32, change *LinkAFSpecChange) error {
+	return l.SetAFSpecContext(context.Background(), index, change)
 }
 
-func (l *LinkService) list(kind string) ([]LinkMessage, error) {
+// SetAFSpecContext is like SetAFSpec, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) SetAFSpecContext(ctx context.Context, index uint32, change *LinkAFSpecChange) error {
+	return l.SetContext(ctx, afSpecRequest(index, change))
+}
+
+// afSpecRequest returns a request setting the address family specific
+// attributes of change.
+func afSpecRequest(index uint32, change *LinkAFSpecChange) *LinkMessage {
+	return &LinkMessage{
+		Family: unix.AF_UNSPEC,
+		Index:  index,
+		Attributes: &LinkAttributes{
+			afSpecChange: change,
+		},
+	}
+}
+
+func (l *LinkService) list(ctx context.Context, kind string) ([]LinkMessage, error) {
 	req := &LinkMessage{}
 	flags := netlink.Request | netlink.Dump
 
 	if kind == "" {
-		return l.execute(req, unix.RTM_GETLINK, flags)
+		return l.execute(ctx, req, unix.RTM_GETLINK, flags)
 	}
 
 	req.Attributes = &LinkAttributes{
 		Info: &LinkInfo{Kind: kind},
 	}
 
-	msgs, err := l.execute(req, unix.RTM_GETLINK, flags)
+	msgs, err := l.execute(ctx, req, unix.RTM_GETLINK, flags)
 
 	// All filtered links are marked by a NLM_F_DUMP_FILTERED flag
 	// no other links present in a response, so just check the first one
@@ -271,19 +327,188 @@ func (l *LinkService) list(kind string) ([]LinkMessage, error) {
 
 }
 
+func (l *LinkService) listSeq(ctx context.Context, kind string) iter.Seq2[LinkMessage, error] {
+	req := &LinkMessage{}
+	flags := netlink.Request | netlink.Dump
+
+	if kind == "" {
+		return executeSeq[LinkMessage](ctx, l.c, req, unix.RTM_GETLINK, flags)
+	}
+
+	req.Attributes = &LinkAttributes{
+		Info: &LinkInfo{Kind: kind},
+	}
+
+	links := executeSeq[LinkMessage](ctx, l.c, req, unix.RTM_GETLINK, flags)
+	return func(yield func(LinkMessage, error) bool) {
+		for link, err := range links {
+			// All filtered links are marked by a NLM_F_DUMP_FILTERED flag,
+			// the kernel did not filter the dump if the first one is not.
+			if err == nil && !link.filtered {
+				return
+			}
+			if !yield(link, err) {
+				return
+			}
+		}
+	}
+}
+
 // ListByKind retrieves all interfaces of a specific kind.
 func (l *LinkService) ListByKind(kind string) ([]LinkMessage, error) {
-	return l.list(kind)
+	return l.ListByKindContext(context.Background(), kind)
+}
+
+// ListByKindContext is like ListByKind, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) ListByKindContext(ctx context.Context, kind string) ([]LinkMessage, error) {
+	return l.list(ctx, kind)
+}
+
+// ListByKindSeq is like ListByKind, but returns an iterator which yields the
+// interfaces one at a time. See Conn.ExecuteSeq.
+func (l *LinkService) ListByKindSeq(kind string) iter.Seq2[LinkMessage, error] {
+	return l.ListByKindSeqContext(context.Background(), kind)
+}
+
+// ListByKindSeqContext is like ListByKindSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *LinkService) ListByKindSeqContext(ctx context.Context, kind string) iter.Seq2[LinkMessage, error] {
+	return l.listSeq(ctx, kind)
 }
 
 // List retrieves all interfaces.
 func (l *LinkService) List() ([]LinkMessage, error) {
-	return l.list("")
+	return l.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) ListContext(ctx context.Context) ([]LinkMessage, error) {
+	return l.list(ctx, "")
+}
+
+// ListSeq is like List, but returns an iterator which yields the interfaces
+// one at a time. See Conn.ExecuteSeq.
+func (l *LinkService) ListSeq() iter.Seq2[LinkMessage, error] {
+	return l.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *LinkService) ListSeqContext(ctx context.Context) iter.Seq2[LinkMessage, error] {
+	return l.listSeq(ctx, "")
+}
+
+// ListMatch retrieves the interfaces matching req. The Master and the kind of
+// the Info of the attributes of req are used as filter when set, all other
+// fields are ignored. A TargetNetNSID in the attributes of req lists the
+// interfaces of the network namespace with that ID instead.
+//
+// Recent kernels filter the dump themselves, with older kernels the
+// interfaces are filtered after they have been received.
+func (l *LinkService) ListMatch(req *LinkMessage) ([]LinkMessage, error) {
+	return l.ListMatchContext(context.Background(), req)
+}
+
+// ListMatchContext is like ListMatch, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) ListMatchContext(ctx context.Context, req *LinkMessage) ([]LinkMessage, error) {
+	filter := linkDumpFilter(req)
+
+	flags := netlink.Request | netlink.Dump
+	links, err := l.execute(ctx, filter, unix.RTM_GETLINK, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := links[:0]
+	for _, link := range links {
+		if link.matches(filter) {
+			matched = append(matched, link)
+		}
+	}
+
+	return matched, nil
+}
+
+// ListMatchSeq is like ListMatch, but returns an iterator which yields the
+// interfaces one at a time. See Conn.ExecuteSeq.
+func (l *LinkService) ListMatchSeq(req *LinkMessage) iter.Seq2[LinkMessage, error] {
+	return l.ListMatchSeqContext(context.Background(), req)
+}
+
+// ListMatchSeqContext is like ListMatchSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *LinkService) ListMatchSeqContext(ctx context.Context, req *LinkMessage) iter.Seq2[LinkMessage, error] {
+	filter := linkDumpFilter(req)
+
+	flags := netlink.Request | netlink.Dump
+	links := executeSeq[LinkMessage](ctx, l.c, filter, unix.RTM_GETLINK, flags)
+
+	return filterSeq(links, func(link *LinkMessage) bool {
+		return link.matches(filter)
+	})
+}
+
+// linkDumpFilter returns the dump request for the interfaces matching req.
+func linkDumpFilter(req *LinkMessage) *LinkMessage {
+	// The kernel only accepts the master and kind of the interfaces as
+	// filter, and no interface index.
+	var master *uint32
+	var target *int32
+	var kind string
+	if req.Attributes != nil {
+		if req.Attributes.Master != nil && *req.Attributes.Master != 0 {
+			master = req.Attributes.Master
+		}
+		if req.Attributes.Info != nil {
+			kind = req.Attributes.Info.Kind
+		}
+		target = req.Attributes.TargetNetNSID
+	}
+
+	filter := &LinkMessage{Attributes: &LinkAttributes{Master: master, TargetNetNSID: target}}
+	if kind != "" {
+		filter.Attributes.Info = &LinkInfo{Kind: kind}
+	}
+
+	return filter
+}
+
+// matches reports whether m matches the master and kind of the dump filter f.
+func (m *LinkMessage) matches(f *LinkMessage) bool {
+	a, fa := m.Attributes, f.Attributes
+	if fa.Master != nil && (a == nil || a.Master == nil || *a.Master != *fa.Master) {
+		return false
+	}
+	if fa.Info != nil && (a == nil || a.Info == nil || a.Info.Kind != fa.Info.Kind) {
+		return false
+	}
+
+	return true
 }
 
 // ListWithVFInfo retrieves all interfaces including SR-IOV VF information.
 // This sets the RTEXT_FILTER_VF extended filter mask to request VF details.
 func (l *LinkService) ListWithVFInfo() ([]LinkMessage, error) {
+	return l.ListWithVFInfoContext(context.Background())
+}
+
+// ListWithVFInfoContext is like ListWithVFInfo, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) ListWithVFInfoContext(ctx context.Context) ([]LinkMessage, error) {
+	extMask := uint32(unix.RTEXT_FILTER_VF)
+	req := &LinkMessage{
+		Attributes: &LinkAttributes{
+			ExtMask: &extMask,
+		},
+	}
+	flags := netlink.Request | netlink.Dump
+	return l.execute(ctx, req, unix.RTM_GETLINK, flags)
+}
+
+// ListWithVFInfoSeq is like ListWithVFInfo, but returns an iterator which
+// yields the interfaces one at a time. See Conn.ExecuteSeq.
+func (l *LinkService) ListWithVFInfoSeq() iter.Seq2[LinkMessage, error] {
+	return l.ListWithVFInfoSeqContext(context.Background())
+}
+
+// ListWithVFInfoSeqContext is like ListWithVFInfoSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *LinkService) ListWithVFInfoSeqContext(ctx context.Context) iter.Seq2[LinkMessage, error] {
 	extMask := uint32(unix.RTEXT_FILTER_VF)
 	req := &LinkMessage{
 		Attributes: &LinkAttributes{
@@ -291,7 +516,7 @@ func (l *LinkService) ListWithVFInfo() ([]LinkMessage, error) {
 		},
 	}
 	flags := netlink.Request | netlink.Dump
-	return l.execute(req, unix.RTM_GETLINK, flags)
+	return executeSeq[LinkMessage](ctx, l.c, req, unix.RTM_GETLINK, flags)
 }
 
 // LinkAttributes contains all attributes for an interface.
@@ -325,6 +550,12 @@ type LinkAttributes struct {
 	VFInfoList       []VFInfo         // Virtual Function information list (SR-IOV)
 	XDP              *LinkXDP         // Express Data Patch Information
 	NetNS            *NetNS           // Interface network namespace
+	LinkNetNSID      *int32           // ID of the network namespace of the peer or lower device
+	NewNetNSID       *int32           // ID of the network namespace an interface was moved to
+	TargetNetNSID    *int32           // ID of the network namespace to list, create or delete links in
+	AFSpec           *LinkAFSpec      // Address family specific configuration
+
+	afSpecChange *LinkAFSpecChange // Address family specific changes, see LinkService.SetAFSpec
 }
 
 // OperationalState represents an interface's operational state.
@@ -344,7 +575,7 @@ const (
 )
 
 // unmarshalBinary unmarshals the contents of a byte slice into a LinkMessage.
-func (a *LinkAttributes) decode(ad *netlink.AttributeDecoder) error {
+func (a *LinkAttributes) decode(ad *netlink.AttributeDecoder, family uint16) error {
 	for ad.Next() {
 		switch ad.Type() {
 		case unix.IFLA_UNSPEC:
@@ -425,6 +656,15 @@ func (a *LinkAttributes) decode(ad *netlink.AttributeDecoder) error {
 		case unix.IFLA_XDP:
 			a.XDP = &LinkXDP{}
 			ad.Nested(a.XDP.decode)
+		case unix.IFLA_AF_SPEC:
+			a.AFSpec = &LinkAFSpec{}
+			if family == unix.AF_BRIDGE {
+				// The bridge attributes are not nested by address family.
+				a.AFSpec.Bridge = &LinkAFSpecBridge{}
+				ad.Nested(a.AFSpec.Bridge.decode)
+			} else {
+				ad.Nested(a.AFSpec.decode)
+			}
 		case unix.IFLA_PROP_LIST:
 			// read nested encoded property list
 			nad, err := netlink.NewAttributeDecoder(ad.Bytes())
@@ -450,6 +690,15 @@ func (a *LinkAttributes) decode(ad *netlink.AttributeDecoder) error {
 				return err
 			}
 			a.VFInfoList = vfs
+		case unix.IFLA_LINK_NETNSID:
+			v := ad.Int32()
+			a.LinkNetNSID = &v
+		case unix.IFLA_NEW_NETNSID:
+			v := ad.Int32()
+			a.NewNetNSID = &v
+		case unix.IFLA_TARGET_NETNSID:
+			v := ad.Int32()
+			a.TargetNetNSID = &v
 		}
 	}
 
@@ -457,7 +706,7 @@ func (a *LinkAttributes) decode(ad *netlink.AttributeDecoder) error {
 }
 
 // MarshalBinary marshals a LinkAttributes into a byte slice.
-func (a *LinkAttributes) encode(ae *netlink.AttributeEncoder) error {
+func (a *LinkAttributes) encode(ae *netlink.AttributeEncoder, family uint16) error {
 	if a.Name != "" {
 		ae.String(unix.IFLA_IFNAME, a.Name)
 	}
@@ -521,6 +770,16 @@ func (a *LinkAttributes) encode(ae *netlink.AttributeEncoder) error {
 		ae.Bytes(unix.IFLA_XDP, b)
 	}
 
+	// Only the bridge attributes are written back, which the kernel only
+	// accepts in messages of family AF_BRIDGE.
+	if family == unix.AF_BRIDGE && a.AFSpec != nil && a.AFSpec.Bridge != nil {
+		ae.Nested(unix.IFLA_AF_SPEC, a.AFSpec.Bridge.encode)
+	}
+
+	if a.afSpecChange != nil {
+		ae.Nested(unix.IFLA_AF_SPEC, a.afSpecChange.encode)
+	}
+
 	if a.Master != nil {
 		ae.Uint32(unix.IFLA_MASTER, *a.Master)
 	}
@@ -533,6 +792,14 @@ func (a *LinkAttributes) encode(ae *netlink.AttributeEncoder) error {
 		ae.Uint32(unix.IFLA_EXT_MASK, *a.ExtMask)
 	}
 
+	if a.LinkNetNSID != nil {
+		ae.Int32(unix.IFLA_LINK_NETNSID, *a.LinkNetNSID)
+	}
+
+	if a.TargetNetNSID != nil {
+		ae.Int32(unix.IFLA_TARGET_NETNSID, *a.TargetNetNSID)
+	}
+
 	return nil
 }
 
@@ -1077,6 +1344,13 @@ type LinkXDP struct {
 	Attached   uint8
 	Flags      uint32
 	ProgID     uint32
+
+	// IDs of the programs attached in generic (skb), driver and offload
+	// mode. Only returned by the kernel. When programs are attached in
+	// multiple modes, Attached is XDP_ATTACHED_MULTI (4) and ProgID is 0.
+	SkbProgID uint32
+	DrvProgID uint32
+	HwProgID  uint32
 }
 
 func (xdp *LinkXDP) decode(ad *netlink.AttributeDecoder) error {
@@ -1092,6 +1366,12 @@ func (xdp *LinkXDP) decode(ad *netlink.AttributeDecoder) error {
 			xdp.Flags = ad.Uint32()
 		case unix.IFLA_XDP_PROG_ID:
 			xdp.ProgID = ad.Uint32()
+		case unix.IFLA_XDP_SKB_PROG_ID:
+			xdp.SkbProgID = ad.Uint32()
+		case unix.IFLA_XDP_DRV_PROG_ID:
+			xdp.DrvProgID = ad.Uint32()
+		case unix.IFLA_XDP_HW_PROG_ID:
+			xdp.HwProgID = ad.Uint32()
 		}
 	}
 	return nil
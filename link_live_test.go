@@ -4,6 +4,8 @@
 package rtnetlink
 
 import (
+	"errors"
+	"syscall"
 	"testing"
 
 	"github.com/cilium/ebpf"
@@ -261,6 +263,33 @@ func TestLinkListByKind(t *testing.T) {
 	}
 }
 
+func TestLinkExtendedAcknowledge(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	// Names are limited to IFNAMSIZ-1 bytes by the attribute policy.
+	err = conn.Link.Set(&LinkMessage{
+		Index: lo,
+		Attributes: &LinkAttributes{
+			Name: "averyveryverylongname",
+		},
+	})
+	if !errors.Is(err, syscall.ERANGE) {
+		t.Fatalf("expected ERANGE, got: %v", err)
+	}
+
+	var oerr *OpError
+	if !errors.As(err, &oerr) {
+		t.Fatalf("expected an *OpError, got: %T", err)
+	}
+	if oerr.Op != "RTM_NEWLINK" || oerr.Message == "" || oerr.Attribute != "IFLA_IFNAME" {
+		t.Fatalf("unexpected error: %#v", oerr)
+	}
+}
+
 func TestLinkSetMaster(t *testing.T) {
 	ns := testutils.NetNS(t)
 	conn, err := Dial(&netlink.Config{NetNS: ns})
@@ -218,6 +218,26 @@ func TestLinkMessageUnmarshalBinary(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "xdp multiple modes",
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x1c, 0x00, 0x2b, 0x00, 0x05, 0x00, 0x02, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x08, 0x00, 0x05, 0x00,
+				0x0a, 0x00, 0x00, 0x00, 0x08, 0x00, 0x07, 0x00,
+				0x0b, 0x00, 0x00, 0x00,
+			},
+			m: &LinkMessage{
+				Attributes: &LinkAttributes{
+					XDP: &LinkXDP{
+						Attached:  4, // XDP_ATTACHED_MULTI
+						DrvProgID: 10,
+						HwProgID:  11,
+					},
+				},
+			},
+		},
 		{
 			name: "no data",
 			b: []byte{
@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestLiveAddressListMatch(t *testing.T) {
+	for _, strict := range []bool{true, false} {
+		conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t), Strict: strict})
+		if err != nil {
+			t.Fatalf("failed to establish netlink socket: %v", err)
+		}
+		defer conn.Close()
+
+		if err := conn.Link.Set(&LinkMessage{Index: lo, Flags: unix.IFF_UP, Change: unix.IFF_UP}); err != nil {
+			t.Fatalf("failed to set up loopback: %v", err)
+		}
+
+		addrs, err := conn.Address.ListMatch(&AddressMessage{Family: unix.AF_INET, Index: lo})
+		if err != nil {
+			t.Fatalf("failed to list addresses (strict: %t): %v", strict, err)
+		}
+		if len(addrs) != 1 || !addrs[0].Attributes.Address.Equal(net.IPv4(127, 0, 0, 1)) {
+			t.Fatalf("unexpected addresses (strict: %t): %+v", strict, addrs)
+		}
+	}
+}
+
+func TestLiveNeighListMatch(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t), Strict: true})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	// The kernel rejects dump requests with attributes it cannot filter by.
+	_, err = conn.Neigh.ListMatch(&NeighMessage{
+		Family:     unix.AF_INET,
+		Index:      lo,
+		Attributes: &NeighAttributes{Master: lo},
+	})
+	if err != nil {
+		t.Fatalf("failed to list neighbors: %v", err)
+	}
+}
+
+func TestLiveRuleListMatch(t *testing.T) {
+	for _, strict := range []bool{true, false} {
+		conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t), Strict: strict})
+		if err != nil {
+			t.Fatalf("failed to establish netlink socket: %v", err)
+		}
+		defer conn.Close()
+
+		rules, err := conn.Rule.ListMatch(&RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN})
+		if err != nil {
+			t.Fatalf("failed to list rules (strict: %t): %v", strict, err)
+		}
+
+		// A new network namespace has a single rule looking up the main table.
+		if len(rules) != 1 || rules[0].Family != unix.AF_INET || rules[0].Table != unix.RT_TABLE_MAIN {
+			t.Fatalf("unexpected rules (strict: %t): %+v", strict, rules)
+		}
+	}
+}
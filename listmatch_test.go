@@ -0,0 +1,287 @@
+//go:build linux
+// +build linux
+
+package rtnetlink
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestAddressListMatch(t *testing.T) {
+	skipBigEndian(t)
+
+	c, dc := dumpConn(t)
+	dc.replies[unix.RTM_GETADDR] = replies(unix.RTM_NEWADDR,
+		&AddressMessage{Family: unix.AF_INET, Index: 1},
+		&AddressMessage{Family: unix.AF_INET6, Index: 1},
+		&AddressMessage{Family: unix.AF_INET, Index: 2},
+	)
+
+	got, err := c.Address.ListMatch(&AddressMessage{
+		Family:       unix.AF_INET,
+		Index:        1,
+		PrefixLength: 8,
+		Attributes:   &AddressAttributes{Label: "lo"},
+	})
+	if err != nil {
+		t.Fatalf("failed to list addresses: %v", err)
+	}
+
+	// Only the family and index are sent to the kernel.
+	if diff := cmp.Diff(mustMarshal(&AddressMessage{Family: unix.AF_INET, Index: 1}), dc.requests[0].Data); diff != "" {
+		t.Fatalf("unexpected request (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]AddressMessage{{Family: unix.AF_INET, Index: 1}}, got); diff != "" {
+		t.Fatalf("unexpected addresses (-want +got):\n%s", diff)
+	}
+}
+
+func TestRouteListMatch(t *testing.T) {
+	skipBigEndian(t)
+
+	c, dc := dumpConn(t)
+	dc.replies[unix.RTM_GETROUTE] = replies(unix.RTM_NEWROUTE,
+		&RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN, Attributes: RouteAttributes{Table: unix.RT_TABLE_MAIN, OutIface: 1}},
+		&RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_LOCAL, Attributes: RouteAttributes{Table: unix.RT_TABLE_LOCAL, OutIface: 1}},
+		&RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_COMPAT, Attributes: RouteAttributes{Table: 1000, OutIface: 1}},
+		&RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN, Attributes: RouteAttributes{Table: unix.RT_TABLE_MAIN, OutIface: 2}},
+		&RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN, Attributes: RouteAttributes{Table: unix.RT_TABLE_MAIN, Multipath: []NextHop{
+			{Hop: RTNextHop{Length: 8, IfIndex: 2}},
+			{Hop: RTNextHop{Length: 8, IfIndex: 1}},
+		}}},
+	)
+
+	tests := []struct {
+		name string
+		req  *RouteMessage
+		want []int
+	}{
+		{
+			name: "all",
+			req:  &RouteMessage{},
+			want: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name: "header table",
+			req:  &RouteMessage{Table: unix.RT_TABLE_LOCAL},
+			want: []int{1},
+		},
+		{
+			name: "attribute table",
+			req:  &RouteMessage{Attributes: RouteAttributes{Table: 1000}},
+			want: []int{2},
+		},
+		{
+			name: "interface",
+			req:  &RouteMessage{Table: unix.RT_TABLE_MAIN, Attributes: RouteAttributes{OutIface: 1}},
+			want: []int{0, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Route.ListMatch(tt.req)
+			if err != nil {
+				t.Fatalf("failed to list routes: %v", err)
+			}
+
+			var idx []int
+			for _, rt := range got {
+				for i, m := range dc.replies[unix.RTM_GETROUTE] {
+					if cmp.Equal(mustMarshal(&rt), m.Data) {
+						idx = append(idx, i)
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.want, idx); diff != "" {
+				t.Fatalf("unexpected routes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRuleListMatch(t *testing.T) {
+	skipBigEndian(t)
+
+	c, dc := dumpConn(t)
+	dc.replies[unix.RTM_GETRULE] = replies(unix.RTM_NEWRULE,
+		&RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN, Attributes: &RuleAttributes{Table: uint32Ptr(unix.RT_TABLE_MAIN), Protocol: uint8Ptr(unix.RTPROT_KERNEL)}},
+		&RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN, Attributes: &RuleAttributes{Table: uint32Ptr(unix.RT_TABLE_MAIN), Protocol: uint8Ptr(unix.RTPROT_STATIC)}},
+		&RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_UNSPEC, Attributes: &RuleAttributes{Table: uint32Ptr(1000), Protocol: uint8Ptr(unix.RTPROT_STATIC)}},
+		&RuleMessage{Family: unix.AF_INET6, Table: unix.RT_TABLE_MAIN, Attributes: &RuleAttributes{Table: uint32Ptr(unix.RT_TABLE_MAIN), Protocol: uint8Ptr(unix.RTPROT_KERNEL)}},
+	)
+
+	tests := []struct {
+		name string
+		req  *RuleMessage
+		want []int
+	}{
+		{
+			name: "all",
+			req:  &RuleMessage{},
+			want: []int{0, 1, 2, 3},
+		},
+		{
+			name: "header table",
+			req:  &RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN},
+			want: []int{0, 1},
+		},
+		{
+			name: "attribute table",
+			req:  &RuleMessage{Attributes: &RuleAttributes{Table: uint32Ptr(1000)}},
+			want: []int{2},
+		},
+		{
+			name: "protocol",
+			req:  &RuleMessage{Attributes: &RuleAttributes{Protocol: uint8Ptr(unix.RTPROT_KERNEL)}},
+			want: []int{0, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc.requests = nil
+			got, err := c.Rule.ListMatch(tt.req)
+			if err != nil {
+				t.Fatalf("failed to list rules: %v", err)
+			}
+
+			// Only the family is sent to the kernel.
+			if diff := cmp.Diff(mustMarshal(&RuleMessage{Family: tt.req.Family}), dc.requests[0].Data); diff != "" {
+				t.Fatalf("unexpected request (-want +got):\n%s", diff)
+			}
+
+			var idx []int
+			for _, rule := range got {
+				for i, m := range dc.replies[unix.RTM_GETRULE] {
+					if cmp.Equal(mustMarshal(&rule), m.Data) {
+						idx = append(idx, i)
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.want, idx); diff != "" {
+				t.Fatalf("unexpected rules (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNeighListMatch(t *testing.T) {
+	skipBigEndian(t)
+
+	c, dc := dumpConn(t)
+	dc.replies[unix.RTM_GETNEIGH] = replies(unix.RTM_NEWNEIGH,
+		&NeighMessage{Family: unix.AF_INET, Index: 2, State: unix.NUD_REACHABLE},
+		&NeighMessage{Family: unix.AF_INET, Index: 2, State: unix.NUD_FAILED},
+		&NeighMessage{Family: unix.AF_INET, Index: 3, State: unix.NUD_STALE},
+		&NeighMessage{Family: unix.AF_INET, Index: 4, State: unix.NUD_REACHABLE},
+	)
+	master := uint32(10)
+	dc.replies[unix.RTM_GETLINK] = replies(unix.RTM_NEWLINK,
+		&LinkMessage{Index: 2, Attributes: &LinkAttributes{Master: &master}},
+		&LinkMessage{Index: 3, Attributes: &LinkAttributes{Master: &master}},
+		&LinkMessage{Index: 4},
+	)
+
+	got, err := c.Neigh.ListMatch(&NeighMessage{
+		Family:     unix.AF_INET,
+		State:      unix.NUD_REACHABLE | unix.NUD_STALE,
+		Attributes: &NeighAttributes{Master: master},
+	})
+	if err != nil {
+		t.Fatalf("failed to list neighbors: %v", err)
+	}
+
+	// The master device is passed as attribute, the state is only filtered
+	// in userspace.
+	want := []byte{
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x09, 0x00,
+		0x0a, 0x00, 0x00, 0x00,
+	}
+	if diff := cmp.Diff(want, dc.requests[0].Data); diff != "" {
+		t.Fatalf("unexpected request (-want +got):\n%s", diff)
+	}
+
+	var idx []uint32
+	for _, n := range got {
+		idx = append(idx, n.Index)
+	}
+	if diff := cmp.Diff([]uint32{2, 3}, idx); diff != "" {
+		t.Fatalf("unexpected neighbors (-want +got):\n%s", diff)
+	}
+}
+
+func TestNeighListFDB(t *testing.T) {
+	skipBigEndian(t)
+
+	c, dc := dumpConn(t)
+	dc.replies[unix.RTM_GETNEIGH] = replies(unix.RTM_NEWNEIGH,
+		&NeighMessage{Family: unix.AF_BRIDGE, Index: 10, Flags: unix.NTF_SELF},
+		&NeighMessage{Family: unix.AF_BRIDGE, Index: 2},
+		&NeighMessage{Family: unix.AF_BRIDGE, Index: 3},
+	)
+	master := uint32(10)
+	dc.replies[unix.RTM_GETLINK] = replies(unix.RTM_NEWLINK,
+		&LinkMessage{Index: 2, Attributes: &LinkAttributes{Master: &master}},
+		&LinkMessage{Index: 3},
+		&LinkMessage{Index: 10},
+	)
+
+	got, err := c.Neigh.ListFDB(&NeighMessage{Attributes: &NeighAttributes{Master: master}})
+	if err != nil {
+		t.Fatalf("failed to list fdb entries: %v", err)
+	}
+
+	want := []byte{
+		0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x09, 0x00,
+		0x0a, 0x00, 0x00, 0x00,
+	}
+	if diff := cmp.Diff(want, dc.requests[0].Data); diff != "" {
+		t.Fatalf("unexpected request (-want +got):\n%s", diff)
+	}
+
+	// The entries of the bridge itself are kept.
+	var idx []uint32
+	for _, n := range got {
+		idx = append(idx, n.Index)
+	}
+	if diff := cmp.Diff([]uint32{10, 2}, idx); diff != "" {
+		t.Fatalf("unexpected fdb entries (-want +got):\n%s", diff)
+	}
+}
+
+// dumpConn returns a Conn which replies to requests of a message type with
+// the messages stored for it.
+func dumpConn(t *testing.T) (*Conn, *testDumpConn) {
+	c := &testDumpConn{replies: make(map[netlink.HeaderType][]netlink.Message)}
+	return newConn(c), c
+}
+
+type testDumpConn struct {
+	requests []netlink.Message
+	replies  map[netlink.HeaderType][]netlink.Message
+
+	noopConn
+}
+
+func (c *testDumpConn) Execute(m netlink.Message) ([]netlink.Message, error) {
+	c.requests = append(c.requests, m)
+	return c.replies[m.Header.Type], nil
+}
+
+func replies(typ netlink.HeaderType, ms ...Message) []netlink.Message {
+	msgs := make([]netlink.Message, 0, len(ms))
+	for _, m := range ms {
+		msgs = append(msgs, netlink.Message{
+			Header: netlink.Header{Type: typ},
+			Data:   mustMarshal(m),
+		})
+	}
+	return msgs
+}
@@ -0,0 +1,586 @@
+package rtnetlink
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+var (
+	// errInvalidMDBMessage is returned when a MDBMessage is malformed.
+	errInvalidMDBMessage = errors.New("rtnetlink MDBMessage is invalid or too short")
+
+	// errInvalidMDBEntry is returned when a MDBEntry is malformed.
+	errInvalidMDBEntry = errors.New("rtnetlink MDBEntry is invalid or too short")
+)
+
+const (
+	// sizeofBrPortMsg is the size of a struct br_port_msg.
+	sizeofBrPortMsg = 8
+
+	// sizeofBrMdbEntry is the size of a struct br_mdb_entry.
+	sizeofBrMdbEntry = 28
+)
+
+var _ Message = &MDBMessage{}
+
+// A MDBMessage is a route netlink bridge multicast database message.
+type MDBMessage struct {
+	// Always set to AF_BRIDGE
+	Family uint8
+
+	// Interface index of the bridge
+	Index uint32
+
+	// Optional attributes which are appended when not nil.
+	Attributes *MDBAttributes
+}
+
+// MarshalBinary marshals a MDBMessage into a byte slice.
+func (m *MDBMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofBrPortMsg)
+
+	b[0] = m.Family
+	// 3 bytes of padding
+	nativeEndian.PutUint32(b[4:8], m.Index)
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a MDBMessage.
+func (m *MDBMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < sizeofBrPortMsg {
+		return errInvalidMDBMessage
+	}
+
+	m.Family = b[0]
+	m.Index = nativeEndian.Uint32(b[4:8])
+
+	if l > sizeofBrPortMsg {
+		m.Attributes = &MDBAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[sizeofBrPortMsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*MDBMessage) rtMessage() {}
+
+// MDBAttributes contains all attributes for the multicast database of a
+// bridge.
+type MDBAttributes struct {
+	// The entry to add or delete. Only used in requests.
+	Entry *MDBEntry
+
+	// The entries of the database, reported by the kernel
+	Entries []MDBEntry
+
+	// The multicast router ports of the bridge, reported by the kernel
+	Routers []MDBRouterPort
+}
+
+func (a *MDBAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.MDBA_MDB:
+			ad.Nested(a.decodeMDB)
+		case unix.MDBA_ROUTER:
+			ad.Nested(a.decodeRouter)
+		}
+	}
+
+	return ad.Err()
+}
+
+// decodeMDB decodes the entries of all groups of MDBA_MDB.
+func (a *MDBAttributes) decodeMDB(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() != unix.MDBA_MDB_ENTRY {
+			continue
+		}
+
+		ad.Nested(func(nad *netlink.AttributeDecoder) error {
+			for nad.Next() {
+				if nad.Type() == unix.MDBA_MDB_ENTRY_INFO {
+					var e MDBEntry
+					nad.Do(e.unmarshalBinary)
+					a.Entries = append(a.Entries, e)
+				}
+			}
+			return nad.Err()
+		})
+	}
+
+	return ad.Err()
+}
+
+// decodeRouter decodes the router ports of MDBA_ROUTER.
+func (a *MDBAttributes) decodeRouter(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.MDBA_ROUTER_PORT {
+			var p MDBRouterPort
+			ad.Do(p.unmarshalBinary)
+			a.Routers = append(a.Routers, p)
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *MDBAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.Entry == nil {
+		return nil
+	}
+
+	b, err := a.Entry.marshalBinary()
+	if err != nil {
+		return err
+	}
+	ae.Bytes(unix.MDBA_SET_ENTRY, b)
+
+	if a.Entry.hasAttributes() {
+		ae.Nested(unix.MDBA_SET_ENTRY_ATTRS, a.Entry.encode)
+	}
+
+	return nil
+}
+
+// A MDBEntry is an entry of the multicast database, describing a group
+// joined on a bridge port.
+type MDBEntry struct {
+	// Interface index of the bridge port, or of the bridge itself for groups
+	// joined by the host
+	Port uint32
+
+	// unix.MDB_TEMPORARY or unix.MDB_PERMANENT
+	State uint8
+
+	// Flags (unix.MDB_FLAGS_*), reported by the kernel
+	Flags uint8
+
+	// VLAN of the group, or 0
+	Vid uint16
+
+	// IPv4 or IPv6 address of the group
+	Group net.IP
+
+	// MAC address of the group, used when Group is nil
+	MAC net.HardwareAddr
+
+	// Source address of a (S, G) entry
+	Source net.IP
+
+	// Source list of a (*, G) entry
+	Sources []MDBSource
+
+	// Filter mode of a (*, G) entry, unix.MCAST_INCLUDE or unix.MCAST_EXCLUDE
+	GroupMode *uint8
+
+	// Protocol which installed the entry (RTPROT_*)
+	Protocol *uint8
+
+	// Remaining time until the entry expires in hundredths of a second,
+	// reported by the kernel
+	Timer *uint32
+
+	// Remote destination of an entry of a VXLAN device
+	Dst     net.IP
+	DstPort *uint16
+	VNI     *uint32
+	IfIndex *uint32 // outgoing interface
+	SrcVNI  *uint32
+}
+
+func (e *MDBEntry) marshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofBrMdbEntry)
+
+	nativeEndian.PutUint32(b[0:4], e.Port)
+	b[4] = e.State
+	b[5] = e.Flags
+	nativeEndian.PutUint16(b[6:8], e.Vid)
+
+	var proto uint16
+	switch {
+	case e.Group != nil:
+		ip, err := encodeIP(e.Group)()
+		if err != nil {
+			return nil, err
+		}
+		copy(b[8:24], ip)
+
+		proto = unix.ETH_P_IPV6
+		if len(ip) == net.IPv4len {
+			proto = unix.ETH_P_IP
+		}
+	case e.MAC != nil:
+		if len(e.MAC) != 6 {
+			return nil, fmt.Errorf("rtnetlink: invalid MDB group MAC address: %s", e.MAC)
+		}
+		copy(b[8:14], e.MAC)
+	}
+	binary.BigEndian.PutUint16(b[24:26], proto)
+	// 2 bytes of padding
+
+	return b, nil
+}
+
+// unmarshalBinary decodes a MDBA_MDB_ENTRY_INFO, a struct br_mdb_entry
+// followed by the attributes of the entry.
+func (e *MDBEntry) unmarshalBinary(b []byte) error {
+	if len(b) < sizeofBrMdbEntry {
+		return errInvalidMDBEntry
+	}
+
+	e.Port = nativeEndian.Uint32(b[0:4])
+	e.State = b[4]
+	e.Flags = b[5]
+	e.Vid = nativeEndian.Uint16(b[6:8])
+
+	switch binary.BigEndian.Uint16(b[24:26]) {
+	case unix.ETH_P_IP:
+		e.Group = make(net.IP, net.IPv4len)
+		copy(e.Group, b[8:12])
+	case unix.ETH_P_IPV6:
+		e.Group = make(net.IP, net.IPv6len)
+		copy(e.Group, b[8:24])
+	default:
+		e.MAC = make(net.HardwareAddr, 6)
+		copy(e.MAC, b[8:14])
+	}
+
+	if len(b) == sizeofBrMdbEntry {
+		return nil
+	}
+
+	ad, err := netlink.NewAttributeDecoder(b[sizeofBrMdbEntry:])
+	if err != nil {
+		return err
+	}
+	ad.ByteOrder = nativeEndian
+
+	return e.decode(ad)
+}
+
+func (e *MDBEntry) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.MDBA_MDB_EATTR_TIMER:
+			v := ad.Uint32()
+			e.Timer = &v
+		case unix.MDBA_MDB_EATTR_SRC_LIST:
+			ad.Nested(e.decodeSources)
+		case unix.MDBA_MDB_EATTR_GROUP_MODE:
+			v := ad.Uint8()
+			e.GroupMode = &v
+		case unix.MDBA_MDB_EATTR_SOURCE:
+			ad.Do(decodeIP(&e.Source))
+		case unix.MDBA_MDB_EATTR_RTPROT:
+			v := ad.Uint8()
+			e.Protocol = &v
+		case unix.MDBA_MDB_EATTR_DST:
+			ad.Do(decodeIP(&e.Dst))
+		case unix.MDBA_MDB_EATTR_DST_PORT:
+			v := ad.Uint16()
+			e.DstPort = &v
+		case unix.MDBA_MDB_EATTR_VNI:
+			v := ad.Uint32()
+			e.VNI = &v
+		case unix.MDBA_MDB_EATTR_IFINDEX:
+			v := ad.Uint32()
+			e.IfIndex = &v
+		case unix.MDBA_MDB_EATTR_SRC_VNI:
+			v := ad.Uint32()
+			e.SrcVNI = &v
+		}
+	}
+
+	return ad.Err()
+}
+
+// decodeSources decodes the source list of MDBA_MDB_EATTR_SRC_LIST.
+func (e *MDBEntry) decodeSources(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.MDBA_MDB_SRCLIST_ENTRY {
+			var s MDBSource
+			ad.Nested(s.decode)
+			e.Sources = append(e.Sources, s)
+		}
+	}
+
+	return ad.Err()
+}
+
+// hasAttributes reports whether any of the attributes of MDBA_SET_ENTRY_ATTRS
+// is set.
+func (e *MDBEntry) hasAttributes() bool {
+	return e.Source != nil || e.Sources != nil || e.GroupMode != nil ||
+		e.Protocol != nil || e.Dst != nil || e.DstPort != nil ||
+		e.VNI != nil || e.IfIndex != nil || e.SrcVNI != nil
+}
+
+// encode encodes the attributes of MDBA_SET_ENTRY_ATTRS, which are numbered
+// differently than the ones reported by the kernel.
+func (e *MDBEntry) encode(ae *netlink.AttributeEncoder) error {
+	if e.Source != nil {
+		ae.Do(unix.MDBE_ATTR_SOURCE, encodeIP(e.Source))
+	}
+
+	if e.Sources != nil {
+		ae.Nested(unix.MDBE_ATTR_SRC_LIST, func(nae *netlink.AttributeEncoder) error {
+			for _, s := range e.Sources {
+				nae.Nested(unix.MDBE_SRC_LIST_ENTRY, s.encode)
+			}
+			return nil
+		})
+	}
+
+	if e.GroupMode != nil {
+		ae.Uint8(unix.MDBE_ATTR_GROUP_MODE, *e.GroupMode)
+	}
+
+	if e.Protocol != nil {
+		ae.Uint8(unix.MDBE_ATTR_RTPROT, *e.Protocol)
+	}
+
+	if e.Dst != nil {
+		ae.Do(unix.MDBE_ATTR_DST, encodeIP(e.Dst))
+	}
+
+	if e.DstPort != nil {
+		ae.Uint16(unix.MDBE_ATTR_DST_PORT, *e.DstPort)
+	}
+
+	if e.VNI != nil {
+		ae.Uint32(unix.MDBE_ATTR_VNI, *e.VNI)
+	}
+
+	if e.IfIndex != nil {
+		ae.Uint32(unix.MDBE_ATTR_IFINDEX, *e.IfIndex)
+	}
+
+	if e.SrcVNI != nil {
+		ae.Uint32(unix.MDBE_ATTR_SRC_VNI, *e.SrcVNI)
+	}
+
+	return nil
+}
+
+// A MDBSource is a source of the source list of a MDBEntry.
+type MDBSource struct {
+	Address net.IP
+
+	// Remaining time until the source expires in hundredths of a second,
+	// reported by the kernel
+	Timer *uint32
+}
+
+func (s *MDBSource) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.MDBA_MDB_SRCATTR_ADDRESS:
+			ad.Do(decodeIP(&s.Address))
+		case unix.MDBA_MDB_SRCATTR_TIMER:
+			v := ad.Uint32()
+			s.Timer = &v
+		}
+	}
+
+	return ad.Err()
+}
+
+func (s *MDBSource) encode(ae *netlink.AttributeEncoder) error {
+	ae.Do(unix.MDBE_SRCATTR_ADDRESS, encodeIP(s.Address))
+	return nil
+}
+
+// A MDBRouterPort is a bridge port, or the bridge itself, which is a
+// multicast router port.
+type MDBRouterPort struct {
+	// Interface index of the port
+	Port uint32
+
+	// Remaining time until the port times out in hundredths of a second
+	Timer *uint32
+
+	// Router mode of the port (unix.MDB_RTR_TYPE_*)
+	Type *uint8
+
+	// Remaining time until the port times out for IPv4 and IPv6
+	Inet4Timer *uint32
+	Inet6Timer *uint32
+
+	// VLAN of the router port when multicast snooping is done per VLAN
+	Vid *uint16
+}
+
+// unmarshalBinary decodes a MDBA_ROUTER_PORT, an interface index followed by
+// the attributes of the port on newer kernels.
+func (p *MDBRouterPort) unmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return errInvalidMDBMessage
+	}
+
+	p.Port = nativeEndian.Uint32(b[0:4])
+	if len(b) == 4 {
+		return nil
+	}
+
+	ad, err := netlink.NewAttributeDecoder(b[4:])
+	if err != nil {
+		return err
+	}
+	ad.ByteOrder = nativeEndian
+
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.MDBA_ROUTER_PATTR_TIMER:
+			v := ad.Uint32()
+			p.Timer = &v
+		case unix.MDBA_ROUTER_PATTR_TYPE:
+			v := ad.Uint8()
+			p.Type = &v
+		case unix.MDBA_ROUTER_PATTR_INET_TIMER:
+			v := ad.Uint32()
+			p.Inet4Timer = &v
+		case unix.MDBA_ROUTER_PATTR_INET6_TIMER:
+			v := ad.Uint32()
+			p.Inet6Timer = &v
+		case unix.MDBA_ROUTER_PATTR_VID:
+			v := ad.Uint16()
+			p.Vid = &v
+		}
+	}
+
+	return ad.Err()
+}
+
+// MDBService is used to manage the multicast database of bridges and VXLAN
+// devices.
+type MDBService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a MDBMessage slice
+func (m *MDBService) execute(ctx context.Context, msg Message, family uint16, flags netlink.HeaderFlags) ([]MDBMessage, error) {
+	msgs, err := m.c.ExecuteContext(ctx, msg, family, flags)
+
+	mdbs := make([]MDBMessage, len(msgs))
+	for i, msg := range msgs {
+		if mm, ok := msg.(*MDBMessage); ok {
+			mdbs[i] = *mm
+		}
+	}
+
+	return mdbs, err
+}
+
+// Add adds the Entry of req to the multicast database of the bridge or VXLAN
+// device with the Index of req.
+func (m *MDBService) Add(req *MDBMessage) error {
+	return m.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (m *MDBService) AddContext(ctx context.Context, req *MDBMessage) error {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
+	_, err := m.c.ExecuteContext(ctx, &r, unix.RTM_NEWMDB, flags)
+
+	return err
+}
+
+// Replace adds the Entry of req to the multicast database of the bridge or
+// VXLAN device with the Index of req, or replaces an existing entry.
+func (m *MDBService) Replace(req *MDBMessage) error {
+	return m.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See
+// Conn.ExecuteContext.
+func (m *MDBService) ReplaceContext(ctx context.Context, req *MDBMessage) error {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
+	_, err := m.c.ExecuteContext(ctx, &r, unix.RTM_NEWMDB, flags)
+
+	return err
+}
+
+// Delete deletes the Entry of req from the multicast database of the bridge
+// or VXLAN device with the Index of req.
+func (m *MDBService) Delete(req *MDBMessage) error {
+	return m.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (m *MDBService) DeleteContext(ctx context.Context, req *MDBMessage) error {
+	r := *req
+	r.Family = unix.AF_BRIDGE
+
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := m.c.ExecuteContext(ctx, &r, unix.RTM_DELMDB, flags)
+
+	return err
+}
+
+// List retrieves the multicast databases and router ports of all bridges and
+// VXLAN devices.
+func (m *MDBService) List() ([]MDBMessage, error) {
+	return m.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (m *MDBService) ListContext(ctx context.Context) ([]MDBMessage, error) {
+	req := &MDBMessage{Family: unix.AF_BRIDGE}
+
+	flags := netlink.Request | netlink.Dump
+	return m.execute(ctx, req, unix.RTM_GETMDB, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the multicast databases
+// one at a time. See Conn.ExecuteSeq.
+func (m *MDBService) ListSeq() iter.Seq2[MDBMessage, error] {
+	return m.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (m *MDBService) ListSeqContext(ctx context.Context) iter.Seq2[MDBMessage, error] {
+	req := &MDBMessage{Family: unix.AF_BRIDGE}
+
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[MDBMessage](ctx, m.c, req, unix.RTM_GETMDB, flags)
+}
@@ -0,0 +1,99 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestMDBAddListDelete(t *testing.T) {
+	const bridgeIndex = 3301
+
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	// Groups can only be added to a running bridge.
+	err = conn.Link.New(&LinkMessage{
+		Index:  bridgeIndex,
+		Flags:  unix.IFF_UP,
+		Change: unix.IFF_UP,
+		Attributes: &LinkAttributes{
+			Name: "mdbbr0",
+			Info: &LinkInfo{Kind: "bridge"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create bridge: %v", err)
+	}
+
+	// Join the group on the bridge itself, as a port would need a carrier.
+	// Groups joined by the host can't be permanent.
+	group := net.IPv4(239, 1, 1, 1).To4()
+	req := &MDBMessage{
+		Index: bridgeIndex,
+		Attributes: &MDBAttributes{
+			Entry: &MDBEntry{
+				Port:  bridgeIndex,
+				State: unix.MDB_TEMPORARY,
+				Group: group,
+			},
+		},
+	}
+
+	// findGroup returns the entry of group in the database of the bridge.
+	findGroup := func() *MDBEntry {
+		t.Helper()
+
+		msgs, err := conn.MDB.List()
+		if err != nil {
+			t.Fatalf("failed to list mdb: %v", err)
+		}
+
+		for _, m := range msgs {
+			if m.Index != bridgeIndex || m.Attributes == nil {
+				continue
+			}
+			for _, e := range m.Attributes.Entries {
+				if e.Group.Equal(group) {
+					return &e
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := conn.MDB.Add(req); err != nil {
+		t.Fatalf("failed to add group: %v", err)
+	}
+
+	e := findGroup()
+	if e == nil {
+		t.Fatal("expected group in mdb")
+	}
+	if e.Port != bridgeIndex || e.State != unix.MDB_TEMPORARY {
+		t.Fatalf("unexpected group entry: %+v", e)
+	}
+
+	if err := conn.MDB.Add(req); !errors.Is(err, syscall.EEXIST) {
+		t.Fatalf("expected EEXIST adding group again, got: %v", err)
+	}
+
+	if err := conn.MDB.Delete(req); err != nil {
+		t.Fatalf("failed to delete group: %v", err)
+	}
+
+	if e := findGroup(); e != nil {
+		t.Fatalf("expected group to be deleted, got: %+v", e)
+	}
+}
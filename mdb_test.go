@@ -0,0 +1,228 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestMDBMessageMarshalBinary(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m *MDBMessage
+		b []byte
+	}{
+		"empty": {
+			m: &MDBMessage{},
+			b: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		"ipv4 source group": {
+			m: &MDBMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				Attributes: &MDBAttributes{
+					Entry: &MDBEntry{
+						Port:   4,
+						State:  unix.MDB_PERMANENT,
+						Vid:    10,
+						Group:  net.IPv4(239, 1, 1, 1),
+						Source: net.IPv4(192, 0, 2, 1),
+					},
+				},
+			},
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x20, 0x00, 0x01, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x0a, 0x00, 0xef, 0x01, 0x01, 0x01,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x02, 0x80, 0x08, 0x00, 0x01, 0x00,
+				0xc0, 0x00, 0x02, 0x01,
+			},
+		},
+		"ipv6 source list": {
+			m: &MDBMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				Attributes: &MDBAttributes{
+					Entry: &MDBEntry{
+						Port:      4,
+						State:     unix.MDB_PERMANENT,
+						Group:     net.ParseIP("ff0e::1"),
+						Sources:   []MDBSource{{Address: net.ParseIP("2001:db8::1")}},
+						GroupMode: uint8Ptr(unix.MCAST_INCLUDE),
+						Protocol:  uint8Ptr(unix.RTPROT_STATIC),
+					},
+				},
+			},
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x20, 0x00, 0x01, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xff, 0x0e, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0x86, 0xdd, 0x00, 0x00, 0x30, 0x00, 0x02, 0x80, 0x1c, 0x00, 0x02, 0x80,
+				0x18, 0x00, 0x01, 0x80, 0x14, 0x00, 0x01, 0x00, 0x20, 0x01, 0x0d, 0xb8,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0x05, 0x00, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x04, 0x00,
+				0x04, 0x00, 0x00, 0x00,
+			},
+		},
+		"l2 group": {
+			m: &MDBMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				Attributes: &MDBAttributes{
+					Entry: &MDBEntry{
+						Port:  4,
+						State: unix.MDB_PERMANENT,
+						MAC:   net.HardwareAddr{0x01, 0x00, 0x5e, 0x01, 0x01, 0x01},
+					},
+				},
+			},
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x20, 0x00, 0x01, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x5e, 0x01,
+				0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		"vxlan remote": {
+			m: &MDBMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				Attributes: &MDBAttributes{
+					Entry: &MDBEntry{
+						Port:    5,
+						State:   unix.MDB_PERMANENT,
+						Group:   net.IPv4(239, 1, 1, 1),
+						Dst:     net.IPv4(198, 51, 100, 1),
+						DstPort: uint16Ptr(4789),
+						VNI:     uint32Ptr(100),
+						IfIndex: uint32Ptr(2),
+						SrcVNI:  uint32Ptr(200),
+					},
+				},
+			},
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x20, 0x00, 0x01, 0x00,
+				0x05, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xef, 0x01, 0x01, 0x01,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x02, 0x80, 0x08, 0x00, 0x05, 0x00,
+				0xc6, 0x33, 0x64, 0x01, 0x06, 0x00, 0x06, 0x00, 0xb5, 0x12, 0x00, 0x00,
+				0x08, 0x00, 0x07, 0x00, 0x64, 0x00, 0x00, 0x00, 0x08, 0x00, 0x08, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x09, 0x00, 0xc8, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+			}
+		})
+	}
+
+	t.Run("invalid group", func(t *testing.T) {
+		m := &MDBMessage{
+			Attributes: &MDBAttributes{
+				Entry: &MDBEntry{MAC: net.HardwareAddr{0x01, 0x00, 0x5e}},
+			},
+		}
+		if _, err := m.MarshalBinary(); err == nil {
+			t.Fatal("expected an error marshaling an invalid group")
+		}
+	})
+}
+
+func TestMDBMessageUnmarshalBinary(t *testing.T) {
+	skipBigEndian(t)
+
+	b := []byte{
+		0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x78, 0x00, 0x01, 0x80,
+		0x74, 0x00, 0x01, 0x80, 0x50, 0x00, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00,
+		0x00, 0x02, 0x00, 0x00, 0xef, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x00, 0x00,
+		0x08, 0x00, 0x01, 0x00, 0xa8, 0x61, 0x00, 0x00, 0x18, 0x00, 0x02, 0x80,
+		0x14, 0x00, 0x01, 0x80, 0x08, 0x00, 0x01, 0x00, 0xc0, 0x00, 0x02, 0x01,
+		0x08, 0x00, 0x02, 0x00, 0x64, 0x00, 0x00, 0x00, 0x05, 0x00, 0x03, 0x00,
+		0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x05, 0x00, 0x04, 0x00, 0x00, 0x00,
+		0x20, 0x00, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x0a, 0x00,
+		0x01, 0x00, 0x5e, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x00, 0x02, 0x80,
+		0x08, 0x00, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x18, 0x00, 0x01, 0x00,
+		0x05, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x05, 0x00, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00,
+	}
+
+	want := &MDBMessage{
+		Family: unix.AF_BRIDGE,
+		Index:  3,
+		Attributes: &MDBAttributes{
+			Entries: []MDBEntry{
+				{
+					Port:  4,
+					State: unix.MDB_TEMPORARY,
+					Flags: unix.MDB_FLAGS_FAST_LEAVE,
+					Group: net.IPv4(239, 1, 1, 1).To4(),
+					Sources: []MDBSource{{
+						Address: net.IPv4(192, 0, 2, 1).To4(),
+						Timer:   uint32Ptr(100),
+					}},
+					GroupMode: uint8Ptr(unix.MCAST_INCLUDE),
+					Protocol:  uint8Ptr(unix.RTPROT_STATIC),
+					Timer:     uint32Ptr(25000),
+				},
+				{
+					Port:  3,
+					State: unix.MDB_PERMANENT,
+					Vid:   10,
+					MAC:   net.HardwareAddr{0x01, 0x00, 0x5e, 0x01, 0x01, 0x01},
+				},
+			},
+			Routers: []MDBRouterPort{
+				{Port: 4},
+				{
+					Port:  5,
+					Timer: uint32Ptr(0),
+					Type:  uint8Ptr(unix.MDB_RTR_TYPE_PERM),
+				},
+			},
+		},
+	}
+
+	m := &MDBMessage{}
+	if err := m.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, m) {
+		t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", want, m)
+	}
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &MDBMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x07, 0x00, 0x00, 0x00})
+		if !errors.Is(unmarshalErr, errInvalidMDBMessage) {
+			t.Fatalf("Expected 'errInvalidMDBMessage' but got '%v'", unmarshalErr)
+		}
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		m := &MDBMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{
+			0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x10, 0x00, 0x01, 0x80,
+			0x0c, 0x00, 0x01, 0x80, 0x08, 0x00, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00,
+		})
+		if !errors.Is(unmarshalErr, errInvalidMDBEntry) {
+			t.Fatalf("Expected 'errInvalidMDBEntry' but got '%v'", unmarshalErr)
+		}
+	})
+}
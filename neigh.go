@@ -1,8 +1,11 @@
 package rtnetlink
 
 import (
+	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"iter"
 	"net"
 
 	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
@@ -104,10 +107,32 @@ type NeighService struct {
 	c *Conn
 }
 
-// New creates a new interface using the LinkMessage information.
+// New creates a new neighbor entry using the NeighMessage information.
 func (l *NeighService) New(req *NeighMessage) error {
+	return l.NewContext(context.Background(), req)
+}
+
+// NewContext is like New, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) NewContext(ctx context.Context, req *NeighMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
-	_, err := l.c.Execute(req, unix.RTM_NEWNEIGH, flags)
+	_, err := l.c.ExecuteContext(ctx, req, unix.RTM_NEWNEIGH, flags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Replace creates a new neighbor entry or replaces an existing one, e.g. to
+// force the link layer address or state of a neighbor.
+func (l *NeighService) Replace(req *NeighMessage) error {
+	return l.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) ReplaceContext(ctx context.Context, req *NeighMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
+	_, err := l.c.ExecuteContext(ctx, req, unix.RTM_NEWNEIGH, flags)
 	if err != nil {
 		return err
 	}
@@ -115,12 +140,17 @@ func (l *NeighService) New(req *NeighMessage) error {
 	return nil
 }
 
-// Delete removes an neighbor entry by index.
-func (l *NeighService) Delete(index uint32) error {
-	req := &NeighMessage{}
+// Delete removes the neighbor entry identified by the Family, Index and the
+// Address of the attributes of req. Proxy entries are removed by setting
+// unix.NTF_PROXY in the Flags of req.
+func (l *NeighService) Delete(req *NeighMessage) error {
+	return l.DeleteContext(context.Background(), req)
+}
 
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) DeleteContext(ctx context.Context, req *NeighMessage) error {
 	flags := netlink.Request | netlink.Acknowledge
-	_, err := l.c.Execute(req, unix.RTM_DELNEIGH, flags)
+	_, err := l.c.ExecuteContext(ctx, neighIdentity(req), unix.RTM_DELNEIGH, flags)
 	if err != nil {
 		return err
 	}
@@ -128,12 +158,60 @@ func (l *NeighService) Delete(index uint32) error {
 	return nil
 }
 
+// Get retrieves the neighbor entry identified by the Family, Index and the
+// Address of the attributes of req. Proxy entries are retrieved by setting
+// unix.NTF_PROXY in the Flags of req. Getting a single entry requires Linux
+// 5.0 or later.
+func (l *NeighService) Get(req *NeighMessage) (NeighMessage, error) {
+	return l.GetContext(context.Background(), req)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) GetContext(ctx context.Context, req *NeighMessage) (NeighMessage, error) {
+	flags := netlink.Request
+	msgs, err := l.c.ExecuteContext(ctx, neighIdentity(req), unix.RTM_GETNEIGH, flags)
+	if err != nil {
+		return NeighMessage{}, err
+	}
+
+	if len(msgs) != 1 {
+		return NeighMessage{}, fmt.Errorf("too many/little matches, expected 1, actual %d", len(msgs))
+	}
+
+	n, ok := msgs[0].(*NeighMessage)
+	if !ok {
+		return NeighMessage{}, errInvalidNeighMessage
+	}
+
+	return *n, nil
+}
+
+// neighIdentity returns a message which only holds the fields identifying the
+// neighbor entry req, as the kernel rejects others in get requests.
+func neighIdentity(req *NeighMessage) *NeighMessage {
+	m := &NeighMessage{
+		Family: req.Family,
+		Index:  req.Index,
+		Flags:  req.Flags & unix.NTF_PROXY,
+	}
+	if req.Attributes != nil {
+		m.Attributes = &NeighAttributes{Address: req.Attributes.Address}
+	}
+
+	return m
+}
+
 // List retrieves all neighbors.
 func (l *NeighService) List() ([]NeighMessage, error) {
+	return l.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) ListContext(ctx context.Context) ([]NeighMessage, error) {
 	req := NeighMessage{}
 
 	flags := netlink.Request | netlink.Dump
-	msgs, err := l.c.Execute(&req, unix.RTM_GETNEIGH, flags)
+	msgs, err := l.c.ExecuteContext(ctx, &req, unix.RTM_GETNEIGH, flags)
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +224,295 @@ func (l *NeighService) List() ([]NeighMessage, error) {
 	return neighs, nil
 }
 
+// ListSeq is like List, but returns an iterator which yields the neighbors
+// one at a time. See Conn.ExecuteSeq.
+func (l *NeighService) ListSeq() iter.Seq2[NeighMessage, error] {
+	return l.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *NeighService) ListSeqContext(ctx context.Context) iter.Seq2[NeighMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[NeighMessage](ctx, l.c, &NeighMessage{}, unix.RTM_GETNEIGH, flags)
+}
+
+// ListMatch retrieves the neighbors matching req. The Family, Index and State
+// of req and the Master of its attributes are used as filter when non-zero,
+// all other fields are ignored. A neighbor matches the State of req if it is
+// in any of the given states.
+//
+// The kernel filters the dump by interface and master device if the Conn has
+// strict checking enabled, see netlink.Config.Strict, which requires Linux
+// 4.20 or later. Otherwise, and for the other fields, the neighbors are
+// filtered after they have been received.
+func (l *NeighService) ListMatch(req *NeighMessage) ([]NeighMessage, error) {
+	return l.ListMatchContext(context.Background(), req)
+}
+
+// ListMatchContext is like ListMatch, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) ListMatchContext(ctx context.Context, req *NeighMessage) ([]NeighMessage, error) {
+	filter := neighDumpFilter(req)
+
+	flags := netlink.Request | netlink.Dump
+	msgs, err := l.c.ExecuteContext(ctx, filter, unix.RTM_GETNEIGH, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	var enslaved map[uint32]bool
+	if filter.master != 0 && len(msgs) > 0 {
+		enslaved, err = l.enslaved(ctx, filter.master)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	neighs := make([]NeighMessage, 0, len(msgs))
+	for _, m := range msgs {
+		n := m.(*NeighMessage)
+		if n.matches(filter, req.State, enslaved) {
+			neighs = append(neighs, *n)
+		}
+	}
+
+	return neighs, nil
+}
+
+// ListMatchSeq is like ListMatch, but returns an iterator which yields the
+// neighbors one at a time. See Conn.ExecuteSeq.
+func (l *NeighService) ListMatchSeq(req *NeighMessage) iter.Seq2[NeighMessage, error] {
+	return l.ListMatchSeqContext(context.Background(), req)
+}
+
+// ListMatchSeqContext is like ListMatchSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *NeighService) ListMatchSeqContext(ctx context.Context, req *NeighMessage) iter.Seq2[NeighMessage, error] {
+	filter := neighDumpFilter(req)
+	state := req.State
+
+	flags := netlink.Request | netlink.Dump
+	neighs := executeSeq[NeighMessage](ctx, l.c, filter, unix.RTM_GETNEIGH, flags)
+
+	return func(yield func(NeighMessage, error) bool) {
+		// The enslaved interfaces have to be known before the dump starts,
+		// the Conn can't be used for other requests during the iteration.
+		var enslaved map[uint32]bool
+		if filter.master != 0 {
+			var err error
+			if enslaved, err = l.enslaved(ctx, filter.master); err != nil {
+				yield(NeighMessage{}, err)
+				return
+			}
+		}
+
+		for n, err := range neighs {
+			if err == nil && !n.matches(filter, state, enslaved) {
+				continue
+			}
+			if !yield(n, err) {
+				return
+			}
+		}
+	}
+}
+
+// neighDumpFilter returns the dump request for the neighbors matching req.
+func neighDumpFilter(req *NeighMessage) *neighDumpRequest {
+	filter := &neighDumpRequest{
+		family: req.Family,
+		index:  req.Index,
+	}
+	if req.Attributes != nil {
+		filter.master = req.Attributes.Master
+	}
+
+	return filter
+}
+
+// enslaved returns the indexes of the interfaces enslaved to master.
+// Neighbors do not carry the master device of their interface, so they are
+// needed in case the kernel did not filter by it.
+func (l *NeighService) enslaved(ctx context.Context, master uint32) (map[uint32]bool, error) {
+	links, err := l.c.Link.ListMatchContext(ctx, &LinkMessage{
+		Attributes: &LinkAttributes{Master: &master},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enslaved := make(map[uint32]bool, len(links))
+	for _, link := range links {
+		enslaved[link.Index] = true
+	}
+
+	return enslaved, nil
+}
+
+// matches reports whether m matches the dump filter f and any of the given
+// states, if not zero. enslaved holds the interfaces enslaved to the master
+// of f, if any.
+func (m *NeighMessage) matches(f *neighDumpRequest, state uint16, enslaved map[uint32]bool) bool {
+	if f.family != 0 && m.Family != f.family {
+		return false
+	}
+	if f.index != 0 && m.Index != f.index {
+		return false
+	}
+	if state != 0 && m.State&state == 0 {
+		return false
+	}
+	// Bridge FDB dumps also include the entries of the bridge itself.
+	if enslaved != nil && !enslaved[m.Index] && (m.Family != unix.AF_BRIDGE || m.Index != f.master) {
+		return false
+	}
+
+	return true
+}
+
+// AddFDB adds a bridge forwarding database entry, like 'bridge fdb add'. The
+// Family of req is always set to unix.AF_BRIDGE.
+//
+// The Flags of req select where the entry is added: unix.NTF_SELF adds it to
+// the device given by Index itself, e.g. a vxlan device, unix.NTF_MASTER to
+// the bridge the device is enslaved to, which is also the default when no
+// flag is set. unix.NTF_EXT_LEARNED marks an entry learned by a control
+// plane, such as EVPN, instead of by the bridge. The State of req is usually
+// unix.NUD_PERMANENT for a static entry, or unix.NUD_NOARP.
+func (l *NeighService) AddFDB(req *NeighMessage) error {
+	return l.AddFDBContext(context.Background(), req)
+}
+
+// AddFDBContext is like AddFDB, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) AddFDBContext(ctx context.Context, req *NeighMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
+	return l.executeFDB(ctx, req, unix.RTM_NEWNEIGH, flags)
+}
+
+// AppendFDB appends a bridge forwarding database entry, like 'bridge fdb
+// append'. It is used to add further remotes to an entry of a vxlan device,
+// e.g. the all-zeros entry for flooding to multiple VTEPs. See AddFDB for the
+// fields of req.
+func (l *NeighService) AppendFDB(req *NeighMessage) error {
+	return l.AppendFDBContext(context.Background(), req)
+}
+
+// AppendFDBContext is like AppendFDB, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) AppendFDBContext(ctx context.Context, req *NeighMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Append
+	return l.executeFDB(ctx, req, unix.RTM_NEWNEIGH, flags)
+}
+
+// ReplaceFDB replaces or adds a bridge forwarding database entry, like
+// 'bridge fdb replace'. See AddFDB for the fields of req.
+func (l *NeighService) ReplaceFDB(req *NeighMessage) error {
+	return l.ReplaceFDBContext(context.Background(), req)
+}
+
+// ReplaceFDBContext is like ReplaceFDB, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) ReplaceFDBContext(ctx context.Context, req *NeighMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Replace
+	return l.executeFDB(ctx, req, unix.RTM_NEWNEIGH, flags)
+}
+
+// DeleteFDB deletes a bridge forwarding database entry, like 'bridge fdb
+// del'. The entry is identified by the Index, Flags and link layer address
+// of req, and its VLAN or, for vxlan devices, its remote and VNI.
+func (l *NeighService) DeleteFDB(req *NeighMessage) error {
+	return l.DeleteFDBContext(context.Background(), req)
+}
+
+// DeleteFDBContext is like DeleteFDB, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) DeleteFDBContext(ctx context.Context, req *NeighMessage) error {
+	flags := netlink.Request | netlink.Acknowledge
+	return l.executeFDB(ctx, req, unix.RTM_DELNEIGH, flags)
+}
+
+// ListFDB retrieves the bridge forwarding database entries, like 'bridge fdb
+// show'. The Index of req restricts them to the entries of a device, such as
+// a bridge port or a vxlan device, and the Master of its attributes to the
+// entries of a bridge and its ports. See ListMatch for how the entries are
+// filtered.
+func (l *NeighService) ListFDB(req *NeighMessage) ([]NeighMessage, error) {
+	return l.ListFDBContext(context.Background(), req)
+}
+
+// ListFDBContext is like ListFDB, but takes a context. See Conn.ExecuteContext.
+func (l *NeighService) ListFDBContext(ctx context.Context, req *NeighMessage) ([]NeighMessage, error) {
+	return l.ListMatchContext(ctx, fdbDumpFilter(req))
+}
+
+// ListFDBSeq is like ListFDB, but returns an iterator which yields the
+// entries one at a time. See Conn.ExecuteSeq.
+func (l *NeighService) ListFDBSeq(req *NeighMessage) iter.Seq2[NeighMessage, error] {
+	return l.ListFDBSeqContext(context.Background(), req)
+}
+
+// ListFDBSeqContext is like ListFDBSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (l *NeighService) ListFDBSeqContext(ctx context.Context, req *NeighMessage) iter.Seq2[NeighMessage, error] {
+	return l.ListMatchSeqContext(ctx, fdbDumpFilter(req))
+}
+
+// fdbDumpFilter returns the neighbor filter for the bridge forwarding database
+// entries matching req.
+func fdbDumpFilter(req *NeighMessage) *NeighMessage {
+	filter := &NeighMessage{Family: unix.AF_BRIDGE, Index: req.Index}
+	if req.Attributes != nil {
+		filter.Attributes = &NeighAttributes{Master: req.Attributes.Master}
+	}
+
+	return filter
+}
+
+// executeFDB executes a request for the bridge forwarding database entry req.
+func (l *NeighService) executeFDB(ctx context.Context, req *NeighMessage, typ uint16, flags netlink.HeaderFlags) error {
+	m := *req
+	m.Family = unix.AF_BRIDGE
+
+	_, err := l.c.ExecuteContext(ctx, &m, typ, flags)
+	return err
+}
+
+// neighDumpRequest is a request to dump the neighbors. Strict checking
+// requires filters to be passed as attributes, which a NeighMessage always
+// encodes together with others that are rejected in a dump request.
+type neighDumpRequest struct {
+	family uint16
+	index  uint32
+	master uint32
+}
+
+var _ Message = &neighDumpRequest{}
+
+// MarshalBinary marshals a neighDumpRequest into a byte slice.
+func (r *neighDumpRequest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, unix.SizeofNdMsg)
+	b[0] = uint8(r.family)
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if r.index != 0 {
+		ae.Uint32(unix.NDA_IFINDEX, r.index)
+	}
+	if r.master != 0 {
+		ae.Uint32(unix.NDA_MASTER, r.master)
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary is only implemented to satisfy the Message interface, a
+// neighDumpRequest is never received.
+func (r *neighDumpRequest) UnmarshalBinary(b []byte) error {
+	return errInvalidNeighMessage
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*neighDumpRequest) rtMessage() {}
+
 // NeighCacheInfo contains neigh information
 type NeighCacheInfo struct {
 	Confirmed uint32
@@ -174,6 +541,18 @@ type NeighAttributes struct {
 	LLAddress net.HardwareAddr // a neighbor cache link layer address
 	CacheInfo *NeighCacheInfo  // cache statistics
 	IfIndex   uint32
+	Master    uint32  // index of the master device of the neighbor's interface
+	Probes    *uint32 // number of probes sent to resolve the neighbor
+	Protocol  *uint8  // protocol which installed the entry (unix.RTPROT_*)
+	NexthopID *uint32 // nexthop object used to reach the neighbor
+	ExtFlags  *uint32 // extended neighbor flags (unix.NTF_EXT_*)
+
+	// Bridge and vxlan FDB entries only.
+	Vlan        *uint16                // VLAN ID of the entry
+	Port        *uint16                // UDP destination port of a vxlan remote
+	VNI         *uint32                // VNI of a vxlan remote
+	SrcVNI      *uint32                // source VNI of the entry on a vxlan device in collect metadata mode
+	FDBExtAttrs *NeighFDBExtAttributes // extended bridge FDB entry attributes
 }
 
 func (a *NeighAttributes) decode(ad *netlink.AttributeDecoder) error {
@@ -200,17 +579,123 @@ func (a *NeighAttributes) decode(ad *netlink.AttributeDecoder) error {
 			}
 		case unix.NDA_IFINDEX:
 			a.IfIndex = ad.Uint32()
+		case unix.NDA_MASTER:
+			a.Master = ad.Uint32()
+		case unix.NDA_PROBES:
+			v := ad.Uint32()
+			a.Probes = &v
+		case unix.NDA_PROTOCOL:
+			v := ad.Uint8()
+			a.Protocol = &v
+		case unix.NDA_NH_ID:
+			v := ad.Uint32()
+			a.NexthopID = &v
+		case unix.NDA_FLAGS_EXT:
+			v := ad.Uint32()
+			a.ExtFlags = &v
+		case unix.NDA_VLAN:
+			v := ad.Uint16()
+			a.Vlan = &v
+		case unix.NDA_PORT:
+			// Port is in network byte order (big-endian)
+			if b := ad.Bytes(); len(b) == 2 {
+				v := binary.BigEndian.Uint16(b)
+				a.Port = &v
+			}
+		case unix.NDA_VNI:
+			v := ad.Uint32()
+			a.VNI = &v
+		case unix.NDA_SRC_VNI:
+			v := ad.Uint32()
+			a.SrcVNI = &v
+		case unix.NDA_FDB_EXT_ATTRS:
+			a.FDBExtAttrs = &NeighFDBExtAttributes{}
+			ad.Nested(a.FDBExtAttrs.decode)
 		}
 	}
 
-	return nil
+	return ad.Err()
 }
 
 func (a *NeighAttributes) encode(ae *netlink.AttributeEncoder) error {
-	ae.Uint16(unix.NDA_UNSPEC, 0)
-	ae.Bytes(unix.NDA_DST, a.Address)
-	ae.Bytes(unix.NDA_LLADDR, a.LLAddress)
-	ae.Uint32(unix.NDA_IFINDEX, a.IfIndex)
+	// Empty attributes are rejected in vxlan FDB requests, where NDA_DST and
+	// NDA_IFINDEX describe an optional remote.
+	if len(a.Address) != 0 {
+		ae.Bytes(unix.NDA_DST, a.Address)
+	}
+	if len(a.LLAddress) != 0 {
+		ae.Bytes(unix.NDA_LLADDR, a.LLAddress)
+	}
+	if a.IfIndex != 0 {
+		ae.Uint32(unix.NDA_IFINDEX, a.IfIndex)
+	}
+	if a.Master != 0 {
+		ae.Uint32(unix.NDA_MASTER, a.Master)
+	}
+	if a.Protocol != nil {
+		ae.Uint8(unix.NDA_PROTOCOL, *a.Protocol)
+	}
+	if a.NexthopID != nil {
+		ae.Uint32(unix.NDA_NH_ID, *a.NexthopID)
+	}
+	if a.ExtFlags != nil {
+		ae.Uint32(unix.NDA_FLAGS_EXT, *a.ExtFlags)
+	}
+	if a.Vlan != nil {
+		ae.Uint16(unix.NDA_VLAN, *a.Vlan)
+	}
+	if a.Port != nil {
+		// Port must be in network byte order (big-endian)
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, *a.Port)
+		ae.Bytes(unix.NDA_PORT, b)
+	}
+	if a.VNI != nil {
+		ae.Uint32(unix.NDA_VNI, *a.VNI)
+	}
+	if a.SrcVNI != nil {
+		ae.Uint32(unix.NDA_SRC_VNI, *a.SrcVNI)
+	}
+	if a.FDBExtAttrs != nil {
+		ae.Nested(unix.NDA_FDB_EXT_ATTRS, a.FDBExtAttrs.encode)
+	}
+
+	return nil
+}
+
+// NeighFDBExtAttributes contains the extended attributes of a bridge FDB
+// entry (NDA_FDB_EXT_ATTRS).
+type NeighFDBExtAttributes struct {
+	// Activity notification state of the entry, a combination of
+	// unix.FDB_NOTIFY_BIT and unix.FDB_NOTIFY_INACTIVE_BIT.
+	ActivityNotify *uint8
+
+	// DontRefresh keeps the activity of an existing entry from being
+	// refreshed by a replace request.
+	DontRefresh bool
+}
+
+func (a *NeighFDBExtAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NFEA_ACTIVITY_NOTIFY:
+			v := ad.Uint8()
+			a.ActivityNotify = &v
+		case unix.NFEA_DONT_REFRESH:
+			a.DontRefresh = true
+		}
+	}
+
+	return nil
+}
+
+func (a *NeighFDBExtAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.ActivityNotify != nil {
+		ae.Uint8(unix.NFEA_ACTIVITY_NOTIFY, *a.ActivityNotify)
+	}
+	if a.DontRefresh {
+		ae.Flag(unix.NFEA_DONT_REFRESH, true)
+	}
 
 	return nil
 }
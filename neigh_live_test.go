@@ -0,0 +1,174 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestNeighReplaceDelete(t *testing.T) {
+	const vethIndex = 3101
+
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.Link.New(&LinkMessage{
+		Index:      vethIndex,
+		Flags:      unix.IFF_UP,
+		Change:     unix.IFF_UP,
+		Attributes: &LinkAttributes{Name: "neighveth0", Info: &LinkInfo{Kind: "veth"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create veth: %v", err)
+	}
+
+	addr := net.IPv4(192, 0, 2, 2).To4()
+	neigh := func(mac net.HardwareAddr, proto uint8) *NeighMessage {
+		return &NeighMessage{
+			Family: unix.AF_INET,
+			Index:  vethIndex,
+			State:  unix.NUD_PERMANENT,
+			Attributes: &NeighAttributes{
+				Address:   addr,
+				LLAddress: mac,
+				Protocol:  &proto,
+			},
+		}
+	}
+	id := &NeighMessage{Family: unix.AF_INET, Index: vethIndex, Attributes: &NeighAttributes{Address: addr}}
+
+	if err := conn.Neigh.New(neigh(net.HardwareAddr{0x02, 0, 0, 0, 0, 0x01}, unix.RTPROT_BOOT)); err != nil {
+		t.Fatalf("failed to add neighbor: %v", err)
+	}
+
+	newMAC := net.HardwareAddr{0x02, 0, 0, 0, 0, 0x02}
+	if err := conn.Neigh.Replace(neigh(newMAC, unix.RTPROT_STATIC)); err != nil {
+		t.Fatalf("failed to replace neighbor: %v", err)
+	}
+
+	got, err := conn.Neigh.Get(id)
+	if err != nil {
+		t.Fatalf("failed to get neighbor: %v", err)
+	}
+	a := got.Attributes
+	if !bytes.Equal(a.LLAddress, newMAC) || a.Protocol == nil || *a.Protocol != unix.RTPROT_STATIC {
+		t.Fatalf("unexpected neighbor after replace: %+v", a)
+	}
+
+	if err := conn.Neigh.Delete(id); err != nil {
+		t.Fatalf("failed to delete neighbor: %v", err)
+	}
+	if _, err := conn.Neigh.Get(id); !errors.Is(err, syscall.ENOENT) {
+		t.Fatalf("expected ENOENT for deleted neighbor, got: %v", err)
+	}
+}
+
+func TestNeighFDB(t *testing.T) {
+	const (
+		bridgeIndex = 3001
+		portIndex   = 3002
+	)
+
+	mac := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+	// Entries of the bridge are reported without NTF_MASTER, but with the
+	// bridge as master.
+	hasEntry := func(entries []NeighMessage, self bool) bool {
+		for _, e := range entries {
+			if e.Index != portIndex || e.Attributes == nil || !bytes.Equal(e.Attributes.LLAddress, mac) {
+				continue
+			}
+			if self && e.Flags&unix.NTF_SELF != 0 || !self && e.Flags&unix.NTF_SELF == 0 && e.Attributes.Master == bridgeIndex {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, strict := range []bool{true, false} {
+		conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t), Strict: strict})
+		if err != nil {
+			t.Fatalf("failed to establish netlink socket: %v", err)
+		}
+		defer conn.Close()
+
+		err = conn.Link.New(&LinkMessage{
+			Index:      bridgeIndex,
+			Attributes: &LinkAttributes{Name: "fdbbr0", Info: &LinkInfo{Kind: "bridge"}},
+		})
+		if err != nil {
+			t.Fatalf("failed to create bridge: %v", err)
+		}
+
+		master := uint32(bridgeIndex)
+		err = conn.Link.New(&LinkMessage{
+			Index: portIndex,
+			Attributes: &LinkAttributes{
+				Name:   "fdbveth0",
+				Info:   &LinkInfo{Kind: "veth"},
+				Master: &master,
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create bridge port: %v", err)
+		}
+
+		entry := &NeighMessage{
+			Index:      portIndex,
+			State:      unix.NUD_PERMANENT,
+			Flags:      unix.NTF_MASTER,
+			Attributes: &NeighAttributes{LLAddress: mac},
+		}
+		if err := conn.Neigh.AddFDB(entry); err != nil {
+			t.Fatalf("failed to add fdb entry (strict: %t): %v", strict, err)
+		}
+		if err := conn.Neigh.ReplaceFDB(entry); err != nil {
+			t.Fatalf("failed to replace fdb entry (strict: %t): %v", strict, err)
+		}
+
+		// The entry is added to the unicast addresses of the port itself
+		// with NTF_SELF.
+		self := *entry
+		self.Flags = unix.NTF_SELF
+		if err := conn.Neigh.AddFDB(&self); err != nil {
+			t.Fatalf("failed to add self fdb entry (strict: %t): %v", strict, err)
+		}
+
+		entries, err := conn.Neigh.ListFDB(&NeighMessage{Attributes: &NeighAttributes{Master: bridgeIndex}})
+		if err != nil {
+			t.Fatalf("failed to list fdb entries of bridge (strict: %t): %v", strict, err)
+		}
+		if !hasEntry(entries, false) || !hasEntry(entries, true) {
+			t.Fatalf("expected fdb entries in bridge (strict: %t): %+v", strict, entries)
+		}
+		for _, e := range entries {
+			if e.Family != unix.AF_BRIDGE || (e.Index != portIndex && e.Index != bridgeIndex) {
+				t.Fatalf("unexpected fdb entry of bridge (strict: %t): %+v", strict, e)
+			}
+		}
+
+		if err := conn.Neigh.DeleteFDB(entry); err != nil {
+			t.Fatalf("failed to delete fdb entry (strict: %t): %v", strict, err)
+		}
+
+		entries, err = conn.Neigh.ListFDB(&NeighMessage{Index: portIndex})
+		if err != nil {
+			t.Fatalf("failed to list fdb entries of port (strict: %t): %v", strict, err)
+		}
+		if hasEntry(entries, false) || !hasEntry(entries, true) {
+			t.Fatalf("unexpected fdb entries of port (strict: %t): %+v", strict, entries)
+		}
+	}
+}
@@ -55,13 +55,11 @@ func TestNeighMessageMarshalBinary(t *testing.T) {
 			},
 			b: []byte{
 				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
-				0x40, 0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00,
-				0x00, 0x00, 0x00, 0x00, 0x14, 0x00, 0x01, 0x00,
+				0x40, 0x00, 0x00, 0x08, 0x14, 0x00, 0x01, 0x00,
 				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 				0x00, 0x00, 0xff, 0xff, 0x0a, 0x00, 0x00, 0x00,
 				0x0a, 0x00, 0x02, 0x00, 0x33, 0x33, 0x00, 0x00,
-				0x00, 0x16, 0x00, 0x00, 0x08, 0x00, 0x08, 0x00,
-				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x16, 0x00, 0x00,
 			},
 		},
 		{
@@ -76,12 +74,10 @@ func TestNeighMessageMarshalBinary(t *testing.T) {
 			},
 			b: []byte{
 				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
-				0x40, 0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00,
-				0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x01, 0x00,
-				0x18, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00,
-				0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-				0x02, 0x00, 0x5e, 0x10, 0x00, 0x00, 0x00, 0x01,
-				0x08, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x40, 0x00, 0x00, 0x08, 0x18, 0x00, 0x02, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xfe, 0x80, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x5e, 0x10,
+				0x00, 0x00, 0x00, 0x01,
 			},
 		},
 		{
@@ -96,11 +92,60 @@ func TestNeighMessageMarshalBinary(t *testing.T) {
 			},
 			b: []byte{
 				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
-				0x40, 0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00,
-				0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x01, 0x00,
-				0x0c, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00,
-				0xfe, 0x80, 0x00, 0x0d, 0x08, 0x00, 0x08, 0x00,
-				0x00, 0x00, 0x00, 0x00,
+				0x40, 0x00, 0x00, 0x08, 0x0c, 0x00, 0x02, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xfe, 0x80, 0x00, 0x0d,
+			},
+		},
+		{
+			name: "fdb",
+			m: &NeighMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				State:  unix.NUD_PERMANENT,
+				Flags:  unix.NTF_SELF,
+				Attributes: &NeighAttributes{
+					Address:   net.IPv4(192, 0, 2, 1).To4(),
+					LLAddress: net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+					Vlan:      uint16Ptr(10),
+					Port:      uint16Ptr(4789),
+					VNI:       uint32Ptr(100),
+					FDBExtAttrs: &NeighFDBExtAttributes{
+						ActivityNotify: uint8Ptr(unix.FDB_NOTIFY_BIT),
+					},
+				},
+			},
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+				0x80, 0x00, 0x02, 0x00, 0x08, 0x00, 0x01, 0x00,
+				0xc0, 0x00, 0x02, 0x01, 0x0a, 0x00, 0x02, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+				0x06, 0x00, 0x05, 0x00, 0x0a, 0x00, 0x00, 0x00,
+				0x06, 0x00, 0x06, 0x00, 0x12, 0xb5, 0x00, 0x00,
+				0x08, 0x00, 0x07, 0x00, 0x64, 0x00, 0x00, 0x00,
+				0x0c, 0x00, 0x0e, 0x80, 0x05, 0x00, 0x01, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			name: "managed",
+			m: &NeighMessage{
+				Family: unix.AF_INET,
+				Index:  2,
+				State:  unix.NUD_REACHABLE,
+				Attributes: &NeighAttributes{
+					Address:   net.IPv4(192, 0, 2, 2).To4(),
+					Protocol:  uint8Ptr(unix.RTPROT_STATIC),
+					NexthopID: uint32Ptr(5),
+					ExtFlags:  uint32Ptr(unix.NTF_EXT_MANAGED),
+				},
+			},
+			b: []byte{
+				0x02, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00,
+				0xc0, 0x00, 0x02, 0x02, 0x05, 0x00, 0x0c, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0d, 0x00,
+				0x05, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0f, 0x00,
+				0x01, 0x00, 0x00, 0x00,
 			},
 		},
 	}
@@ -175,6 +220,60 @@ func TestNeighMessageUnmarshalBinary(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "fdb",
+			b: []byte{
+				0x07, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+				0x80, 0x00, 0x02, 0x00, 0x08, 0x00, 0x01, 0x00,
+				0xc0, 0x00, 0x02, 0x01, 0x0a, 0x00, 0x02, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+				0x06, 0x00, 0x05, 0x00, 0x0a, 0x00, 0x00, 0x00,
+				0x06, 0x00, 0x06, 0x00, 0x12, 0xb5, 0x00, 0x00,
+				0x08, 0x00, 0x07, 0x00, 0x64, 0x00, 0x00, 0x00,
+				0x0c, 0x00, 0x0e, 0x80, 0x05, 0x00, 0x01, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+			},
+			m: &NeighMessage{
+				Family: unix.AF_BRIDGE,
+				Index:  3,
+				State:  unix.NUD_PERMANENT,
+				Flags:  unix.NTF_SELF,
+				Attributes: &NeighAttributes{
+					Address:   net.IPv4(192, 0, 2, 1).To4(),
+					LLAddress: net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+					Vlan:      uint16Ptr(10),
+					Port:      uint16Ptr(4789),
+					VNI:       uint32Ptr(100),
+					FDBExtAttrs: &NeighFDBExtAttributes{
+						ActivityNotify: uint8Ptr(unix.FDB_NOTIFY_BIT),
+					},
+				},
+			},
+		},
+		{
+			name: "managed",
+			b: []byte{
+				0x02, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00,
+				0xc0, 0x00, 0x02, 0x02, 0x05, 0x00, 0x0c, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0d, 0x00,
+				0x05, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0f, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x04, 0x00, 0x03, 0x00, 0x00, 0x00,
+			},
+			m: &NeighMessage{
+				Family: unix.AF_INET,
+				Index:  2,
+				State:  unix.NUD_REACHABLE,
+				Attributes: &NeighAttributes{
+					Address:   net.IPv4(192, 0, 2, 2).To4(),
+					Protocol:  uint8Ptr(unix.RTPROT_STATIC),
+					NexthopID: uint32Ptr(5),
+					ExtFlags:  uint32Ptr(unix.NTF_EXT_MANAGED),
+					Probes:    uint32Ptr(3),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
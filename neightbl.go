@@ -0,0 +1,436 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// errInvalidNeighTableMessage is returned when a NeighTableMessage is malformed.
+var errInvalidNeighTableMessage = errors.New("rtnetlink NeighTableMessage is invalid or too short")
+
+// sizeofNdtmsg is the size of a struct ndtmsg.
+const sizeofNdtmsg = 4
+
+var _ Message = &NeighTableMessage{}
+
+// A NeighTableMessage is a route netlink neighbor table message. It describes
+// a neighbor table, such as the ARP (unix.AF_INET) or ND (unix.AF_INET6)
+// table, or the parameters of the table for a single interface.
+type NeighTableMessage struct {
+	// Address family of the table
+	Family uint8
+
+	// Optional attributes which are appended when not nil.
+	Attributes *NeighTableAttributes
+}
+
+// MarshalBinary marshals a NeighTableMessage into a byte slice.
+func (m *NeighTableMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofNdtmsg)
+	b[0] = m.Family
+	// b[1:4] is padding
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a NeighTableMessage.
+func (m *NeighTableMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < sizeofNdtmsg {
+		return errInvalidNeighTableMessage
+	}
+
+	m.Family = b[0]
+
+	if l > sizeofNdtmsg {
+		m.Attributes = &NeighTableAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[sizeofNdtmsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*NeighTableMessage) rtMessage() {}
+
+// NeighTableService is used to retrieve and tune the parameters of the
+// neighbor tables.
+type NeighTableService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a NeighTableMessage slice
+func (n *NeighTableService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]NeighTableMessage, error) {
+	msgs, err := n.c.ExecuteContext(ctx, m, family, flags)
+
+	tables := make([]NeighTableMessage, len(msgs))
+	for i, msg := range msgs {
+		if t, ok := msg.(*NeighTableMessage); ok {
+			tables[i] = *t
+		}
+	}
+
+	return tables, err
+}
+
+// List retrieves the neighbor tables. Each table is reported with its
+// configuration, statistics and default parameters, followed by a message
+// holding only the Name and Parms for each interface using the table.
+func (n *NeighTableService) List() ([]NeighTableMessage, error) {
+	return n.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (n *NeighTableService) ListContext(ctx context.Context) ([]NeighTableMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return n.execute(ctx, &NeighTableMessage{}, unix.RTM_GETNEIGHTBL, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the neighbor tables
+// one at a time. See Conn.ExecuteSeq.
+func (n *NeighTableService) ListSeq() iter.Seq2[NeighTableMessage, error] {
+	return n.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (n *NeighTableService) ListSeqContext(ctx context.Context) iter.Seq2[NeighTableMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[NeighTableMessage](ctx, n.c, &NeighTableMessage{}, unix.RTM_GETNEIGHTBL, flags)
+}
+
+// Set changes the parameters of the neighbor table with the Family of req
+// and the Name of its attributes, such as "arp_cache" or "ndisc_cache". Only
+// the thresholds, GC interval and writable parameters set in req are changed.
+// The parameters of a single interface are changed by setting the IfIndex of
+// the Parms.
+//
+// The thresholds and GC interval are global and can only be changed from the
+// initial network namespace.
+func (n *NeighTableService) Set(req *NeighTableMessage) error {
+	return n.SetContext(context.Background(), req)
+}
+
+// SetContext is like Set, but takes a context. See Conn.ExecuteContext.
+func (n *NeighTableService) SetContext(ctx context.Context, req *NeighTableMessage) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := n.c.ExecuteContext(ctx, req, unix.RTM_SETNEIGHTBL, flags)
+
+	return err
+}
+
+// NeighTableAttributes contains all attributes for a neighbor table.
+type NeighTableAttributes struct {
+	// Name of the table, e.g. "arp_cache" or "ndisc_cache"
+	Name string
+
+	// Garbage collection thresholds (gc_thresh1, gc_thresh2 and gc_thresh3)
+	Thresh1 *uint32
+	Thresh2 *uint32
+	Thresh3 *uint32
+
+	// Garbage collection interval in milliseconds
+	GCInterval *uint64
+
+	// Configuration of the table, only reported by the kernel.
+	Config *NeighTableConfig
+
+	// Statistics of the table, only reported by the kernel.
+	Stats *NeighTableStats
+
+	// Default parameters of the table, or the parameters of an interface.
+	Parms *NeighTableParms
+}
+
+func (a *NeighTableAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NDTA_NAME:
+			a.Name = ad.String()
+		case unix.NDTA_THRESH1:
+			v := ad.Uint32()
+			a.Thresh1 = &v
+		case unix.NDTA_THRESH2:
+			v := ad.Uint32()
+			a.Thresh2 = &v
+		case unix.NDTA_THRESH3:
+			v := ad.Uint32()
+			a.Thresh3 = &v
+		case unix.NDTA_GC_INTERVAL:
+			v := ad.Uint64()
+			a.GCInterval = &v
+		case unix.NDTA_CONFIG:
+			a.Config = &NeighTableConfig{}
+			ad.Do(a.Config.unmarshalBinary)
+		case unix.NDTA_STATS:
+			a.Stats = &NeighTableStats{}
+			ad.Do(a.Stats.unmarshalBinary)
+		case unix.NDTA_PARMS:
+			a.Parms = &NeighTableParms{}
+			ad.Nested(a.Parms.decode)
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *NeighTableAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.Name != "" {
+		ae.String(unix.NDTA_NAME, a.Name)
+	}
+
+	if a.Thresh1 != nil {
+		ae.Uint32(unix.NDTA_THRESH1, *a.Thresh1)
+	}
+
+	if a.Thresh2 != nil {
+		ae.Uint32(unix.NDTA_THRESH2, *a.Thresh2)
+	}
+
+	if a.Thresh3 != nil {
+		ae.Uint32(unix.NDTA_THRESH3, *a.Thresh3)
+	}
+
+	if a.GCInterval != nil {
+		ae.Uint64(unix.NDTA_GC_INTERVAL, *a.GCInterval)
+	}
+
+	if a.Parms != nil {
+		ae.Nested(unix.NDTA_PARMS, a.Parms.encode)
+	}
+
+	return nil
+}
+
+// NeighTableConfig contains the configuration of a neighbor table
+// (struct ndt_config).
+type NeighTableConfig struct {
+	KeyLen        uint16
+	EntrySize     uint16
+	Entries       uint32 // number of entries in the table
+	LastFlush     uint32 // milliseconds since the last flush
+	LastRand      uint32 // milliseconds since the last randomization of the reachable times
+	HashRnd       uint32
+	HashMask      uint32
+	HashChainGC   uint32
+	ProxyQueueLen uint32
+}
+
+// sizeofNdtConfig is the size of a struct ndt_config.
+const sizeofNdtConfig = 32
+
+func (c *NeighTableConfig) unmarshalBinary(b []byte) error {
+	if len(b) < sizeofNdtConfig {
+		return fmt.Errorf("incorrect NeighTableConfig size, want: %d, got: %d", sizeofNdtConfig, len(b))
+	}
+
+	c.KeyLen = nativeEndian.Uint16(b[0:2])
+	c.EntrySize = nativeEndian.Uint16(b[2:4])
+	c.Entries = nativeEndian.Uint32(b[4:8])
+	c.LastFlush = nativeEndian.Uint32(b[8:12])
+	c.LastRand = nativeEndian.Uint32(b[12:16])
+	c.HashRnd = nativeEndian.Uint32(b[16:20])
+	c.HashMask = nativeEndian.Uint32(b[20:24])
+	c.HashChainGC = nativeEndian.Uint32(b[24:28])
+	c.ProxyQueueLen = nativeEndian.Uint32(b[28:32])
+
+	return nil
+}
+
+// NeighTableStats contains the statistics of a neighbor table
+// (struct ndt_stats).
+type NeighTableStats struct {
+	Allocs         uint64
+	Destroys       uint64
+	HashGrows      uint64
+	ResFailed      uint64 // failed resolutions
+	Lookups        uint64
+	Hits           uint64
+	RcvProbesMcast uint64
+	RcvProbesUcast uint64
+	PeriodicGCRuns uint64
+	ForcedGCRuns   uint64
+	TableFulls     uint64 // times the table was full, since Linux 4.3
+}
+
+// sizeofNdtStats is the size of a struct ndt_stats before Linux 4.3, which
+// added the table fulls counter.
+const sizeofNdtStats = 80
+
+func (s *NeighTableStats) unmarshalBinary(b []byte) error {
+	if len(b) < sizeofNdtStats {
+		return fmt.Errorf("incorrect NeighTableStats size, want: %d, got: %d", sizeofNdtStats, len(b))
+	}
+
+	s.Allocs = nativeEndian.Uint64(b[0:8])
+	s.Destroys = nativeEndian.Uint64(b[8:16])
+	s.HashGrows = nativeEndian.Uint64(b[16:24])
+	s.ResFailed = nativeEndian.Uint64(b[24:32])
+	s.Lookups = nativeEndian.Uint64(b[32:40])
+	s.Hits = nativeEndian.Uint64(b[40:48])
+	s.RcvProbesMcast = nativeEndian.Uint64(b[48:56])
+	s.RcvProbesUcast = nativeEndian.Uint64(b[56:64])
+	s.PeriodicGCRuns = nativeEndian.Uint64(b[64:72])
+	s.ForcedGCRuns = nativeEndian.Uint64(b[72:80])
+	if len(b) >= sizeofNdtStats+8 {
+		s.TableFulls = nativeEndian.Uint64(b[80:88])
+	}
+
+	return nil
+}
+
+// NeighTableParms contains the parameters of a neighbor table or of an
+// interface using it (NDTA_PARMS). Times are in milliseconds.
+type NeighTableParms struct {
+	// Index of the interface the parameters apply to, zero for the default
+	// parameters of the table.
+	IfIndex uint32
+
+	// Reference count of the parameters, only reported by the kernel.
+	RefCount *uint32
+
+	// Current randomized reachable time, only reported by the kernel. It is
+	// derived from BaseReachableTime.
+	ReachableTime *uint64
+
+	BaseReachableTime *uint64
+	RetransTime       *uint64
+	GCStaleTime       *uint64
+	DelayProbeTime    *uint64
+	IntervalProbeTime *uint64
+	AnycastDelay      *uint64
+	ProxyDelay        *uint64
+	LockTime          *uint64
+
+	QueueLen      *uint32 // unresolved queue length in packets
+	QueueLenBytes *uint32 // unresolved queue length in bytes
+	ProxyQueueLen *uint32
+	AppProbes     *uint32
+	UcastProbes   *uint32
+	McastProbes   *uint32
+	McastReprobes *uint32
+}
+
+func (p *NeighTableParms) decode(ad *netlink.AttributeDecoder) error {
+	u32 := func(v **uint32) {
+		x := ad.Uint32()
+		*v = &x
+	}
+	u64 := func(v **uint64) {
+		x := ad.Uint64()
+		*v = &x
+	}
+
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NDTPA_IFINDEX:
+			p.IfIndex = ad.Uint32()
+		case unix.NDTPA_REFCNT:
+			u32(&p.RefCount)
+		case unix.NDTPA_REACHABLE_TIME:
+			u64(&p.ReachableTime)
+		case unix.NDTPA_BASE_REACHABLE_TIME:
+			u64(&p.BaseReachableTime)
+		case unix.NDTPA_RETRANS_TIME:
+			u64(&p.RetransTime)
+		case unix.NDTPA_GC_STALETIME:
+			u64(&p.GCStaleTime)
+		case unix.NDTPA_DELAY_PROBE_TIME:
+			u64(&p.DelayProbeTime)
+		case unix.NDTPA_INTERVAL_PROBE_TIME_MS:
+			u64(&p.IntervalProbeTime)
+		case unix.NDTPA_ANYCAST_DELAY:
+			u64(&p.AnycastDelay)
+		case unix.NDTPA_PROXY_DELAY:
+			u64(&p.ProxyDelay)
+		case unix.NDTPA_LOCKTIME:
+			u64(&p.LockTime)
+		case unix.NDTPA_QUEUE_LEN:
+			u32(&p.QueueLen)
+		case unix.NDTPA_QUEUE_LENBYTES:
+			u32(&p.QueueLenBytes)
+		case unix.NDTPA_PROXY_QLEN:
+			u32(&p.ProxyQueueLen)
+		case unix.NDTPA_APP_PROBES:
+			u32(&p.AppProbes)
+		case unix.NDTPA_UCAST_PROBES:
+			u32(&p.UcastProbes)
+		case unix.NDTPA_MCAST_PROBES:
+			u32(&p.McastProbes)
+		case unix.NDTPA_MCAST_REPROBES:
+			u32(&p.McastReprobes)
+		}
+	}
+
+	return nil
+}
+
+func (p *NeighTableParms) encode(ae *netlink.AttributeEncoder) error {
+	if p.IfIndex != 0 {
+		ae.Uint32(unix.NDTPA_IFINDEX, p.IfIndex)
+	}
+
+	for _, v := range []struct {
+		typ uint16
+		v   *uint64
+	}{
+		{unix.NDTPA_BASE_REACHABLE_TIME, p.BaseReachableTime},
+		{unix.NDTPA_RETRANS_TIME, p.RetransTime},
+		{unix.NDTPA_GC_STALETIME, p.GCStaleTime},
+		{unix.NDTPA_DELAY_PROBE_TIME, p.DelayProbeTime},
+		{unix.NDTPA_INTERVAL_PROBE_TIME_MS, p.IntervalProbeTime},
+		{unix.NDTPA_ANYCAST_DELAY, p.AnycastDelay},
+		{unix.NDTPA_PROXY_DELAY, p.ProxyDelay},
+		{unix.NDTPA_LOCKTIME, p.LockTime},
+	} {
+		if v.v != nil {
+			ae.Uint64(v.typ, *v.v)
+		}
+	}
+
+	for _, v := range []struct {
+		typ uint16
+		v   *uint32
+	}{
+		{unix.NDTPA_QUEUE_LEN, p.QueueLen},
+		{unix.NDTPA_QUEUE_LENBYTES, p.QueueLenBytes},
+		{unix.NDTPA_PROXY_QLEN, p.ProxyQueueLen},
+		{unix.NDTPA_APP_PROBES, p.AppProbes},
+		{unix.NDTPA_UCAST_PROBES, p.UcastProbes},
+		{unix.NDTPA_MCAST_PROBES, p.McastProbes},
+		{unix.NDTPA_MCAST_REPROBES, p.McastReprobes},
+	} {
+		if v.v != nil {
+			ae.Uint32(v.typ, *v.v)
+		}
+	}
+
+	return nil
+}
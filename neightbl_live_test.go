@@ -0,0 +1,66 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestNeighTable(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	// arpParms returns the parameters of the ARP table for an interface, or
+	// the table itself for index 0.
+	arpParms := func(index uint32) *NeighTableMessage {
+		t.Helper()
+
+		tables, err := conn.NeighTable.List()
+		if err != nil {
+			t.Fatalf("failed to list neighbor tables: %v", err)
+		}
+		for _, tbl := range tables {
+			a := tbl.Attributes
+			if tbl.Family == unix.AF_INET && a != nil && a.Name == "arp_cache" && a.Parms != nil && a.Parms.IfIndex == index {
+				return &tbl
+			}
+		}
+		t.Fatalf("no arp_cache parameters for interface %d in: %+v", index, tables)
+		return nil
+	}
+
+	tbl := arpParms(0)
+	if a := tbl.Attributes; a.Config == nil || a.Config.KeyLen != 4 || a.Stats == nil || a.Thresh1 == nil {
+		t.Fatalf("expected arp_cache with configuration, statistics and thresholds: %+v", a)
+	}
+
+	// A multiple of the jiffies of any HZ, which the time is stored in.
+	const reachable = 12000
+	err = conn.NeighTable.Set(&NeighTableMessage{
+		Family: unix.AF_INET,
+		Attributes: &NeighTableAttributes{
+			Name: "arp_cache",
+			Parms: &NeighTableParms{
+				IfIndex:           lo,
+				BaseReachableTime: uint64Ptr(reachable),
+				UcastProbes:       uint32Ptr(7),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to set neighbor table parameters: %v", err)
+	}
+
+	p := arpParms(lo).Attributes.Parms
+	if p.BaseReachableTime == nil || *p.BaseReachableTime != reachable || p.UcastProbes == nil || *p.UcastProbes != 7 {
+		t.Fatalf("unexpected parameters of lo: base reachable time %d, unicast probes %d", *p.BaseReachableTime, *p.UcastProbes)
+	}
+}
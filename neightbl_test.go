@@ -0,0 +1,132 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestNeighTableMessage(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m            Message
+		b            []byte
+		marshalErr   error
+		unmarshalErr error
+	}{
+		"empty": {
+			m: &NeighTableMessage{},
+			b: []byte{0x00, 0x00, 0x00, 0x00},
+		},
+		"set": {
+			m: &NeighTableMessage{
+				Family: unix.AF_INET,
+				Attributes: &NeighTableAttributes{
+					Name:       "arp_cache",
+					Thresh1:    uint32Ptr(128),
+					GCInterval: uint64Ptr(30000),
+					Parms: &NeighTableParms{
+						IfIndex:           1,
+						BaseReachableTime: uint64Ptr(30000),
+						UcastProbes:       uint32Ptr(3),
+					},
+				},
+			},
+			b: []byte{
+				0x02, 0x00, 0x00, 0x00, 0x0e, 0x00, 0x01, 0x00,
+				0x61, 0x72, 0x70, 0x5f, 0x63, 0x61, 0x63, 0x68,
+				0x65, 0x00, 0x00, 0x00, 0x08, 0x00, 0x02, 0x00,
+				0x80, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x08, 0x00,
+				0x30, 0x75, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x20, 0x00, 0x06, 0x80, 0x08, 0x00, 0x01, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x04, 0x00,
+				0x30, 0x75, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x0a, 0x00, 0x03, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b []byte
+			t.Run("marshal", func(t *testing.T) {
+				var marshalErr error
+				b, marshalErr = tt.m.MarshalBinary()
+
+				if !errors.Is(marshalErr, tt.marshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.marshalErr, marshalErr)
+				}
+			})
+
+			t.Run("compare bytes", func(t *testing.T) {
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+
+			m := &NeighTableMessage{}
+			t.Run("unmarshal", func(t *testing.T) {
+				unmarshalErr := (m).UnmarshalBinary(b)
+				if !errors.Is(unmarshalErr, tt.unmarshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.unmarshalErr, unmarshalErr)
+				}
+			})
+
+			t.Run("compare messages", func(t *testing.T) {
+				if !reflect.DeepEqual(tt.m, m) {
+					t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", tt.m, m)
+				}
+			})
+		})
+	}
+
+	t.Run("config and stats", func(t *testing.T) {
+		config := make([]byte, sizeofNdtConfig)
+		nativeEndian.PutUint16(config[0:2], 4)
+		nativeEndian.PutUint32(config[4:8], 2)
+
+		// Statistics without the table fulls counter of Linux 4.3 and later.
+		stats := make([]byte, sizeofNdtStats)
+		nativeEndian.PutUint64(stats[0:8], 5)
+		nativeEndian.PutUint64(stats[72:80], 1)
+
+		ae := netlink.NewAttributeEncoder()
+		ae.String(unix.NDTA_NAME, "arp_cache")
+		ae.Bytes(unix.NDTA_CONFIG, config)
+		ae.Bytes(unix.NDTA_STATS, stats)
+		attrs, err := ae.Encode()
+		if err != nil {
+			t.Fatalf("failed to encode attributes: %v", err)
+		}
+
+		m := &NeighTableMessage{}
+		if err := m.UnmarshalBinary(append([]byte{unix.AF_INET, 0x00, 0x00, 0x00}, attrs...)); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+
+		want := &NeighTableMessage{
+			Family: unix.AF_INET,
+			Attributes: &NeighTableAttributes{
+				Name:   "arp_cache",
+				Config: &NeighTableConfig{KeyLen: 4, Entries: 2},
+				Stats:  &NeighTableStats{Allocs: 5, ForcedGCRuns: 1},
+			},
+		}
+		if !reflect.DeepEqual(want, m) {
+			t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", want, m)
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &NeighTableMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x00, 0x01})
+		if !errors.Is(unmarshalErr, errInvalidNeighTableMessage) {
+			t.Fatalf("Expected 'errInvalidNeighTableMessage' but got '%v'", unmarshalErr)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// errInvalidNetconfMessage is returned when a NetconfMessage is malformed.
+var errInvalidNetconfMessage = errors.New("rtnetlink NetconfMessage is invalid or too short")
+
+// sizeofNetconfmsg is the size of the header of a NetconfMessage, a struct
+// netconfmsg padded to 4 bytes.
+const sizeofNetconfmsg = 4
+
+var _ Message = &NetconfMessage{}
+
+// A NetconfMessage is a route netlink per-interface IP configuration message.
+//
+// It reports the configuration of an interface which is relevant for routing,
+// such as forwarding and reverse path filtering. The configuration itself is
+// changed with LinkService.SetAFSpec or through sysctl.
+type NetconfMessage struct {
+	// Address family, unix.AF_INET, unix.AF_INET6 or unix.AF_MPLS
+	Family uint8
+
+	// Optional attributes which are appended when not nil.
+	Attributes *NetconfAttributes
+}
+
+// MarshalBinary marshals a NetconfMessage into a byte slice.
+func (m *NetconfMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofNetconfmsg)
+	b[0] = m.Family
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a NetconfMessage.
+func (m *NetconfMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < sizeofNetconfmsg {
+		return errInvalidNetconfMessage
+	}
+
+	m.Family = b[0]
+
+	if l > sizeofNetconfmsg {
+		m.Attributes = &NetconfAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[sizeofNetconfmsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*NetconfMessage) rtMessage() {}
+
+// NetconfAttributes contains all attributes for the IP configuration of an
+// interface. The kernel only reports the settings which exist for the family.
+type NetconfAttributes struct {
+	// Interface index, or unix.NETCONFA_IFINDEX_ALL and
+	// unix.NETCONFA_IFINDEX_DEFAULT for the 'all' and 'default' configuration
+	IfIndex *int32
+
+	Forwarding               *int32
+	RPFilter                 *int32 // IPv4 only
+	MCForwarding             *int32
+	ProxyNeigh               *int32
+	IgnoreRoutesWithLinkdown *int32
+	Input                    *int32 // MPLS only
+	BCForwarding             *int32 // IPv4 only
+}
+
+func (a *NetconfAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		var p **int32
+		switch ad.Type() {
+		case unix.NETCONFA_IFINDEX:
+			p = &a.IfIndex
+		case unix.NETCONFA_FORWARDING:
+			p = &a.Forwarding
+		case unix.NETCONFA_RP_FILTER:
+			p = &a.RPFilter
+		case unix.NETCONFA_MC_FORWARDING:
+			p = &a.MCForwarding
+		case unix.NETCONFA_PROXY_NEIGH:
+			p = &a.ProxyNeigh
+		case unix.NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN:
+			p = &a.IgnoreRoutesWithLinkdown
+		case unix.NETCONFA_INPUT:
+			p = &a.Input
+		case unix.NETCONFA_BC_FORWARDING:
+			p = &a.BCForwarding
+		default:
+			continue
+		}
+
+		v := ad.Int32()
+		*p = &v
+	}
+
+	return ad.Err()
+}
+
+func (a *NetconfAttributes) encode(ae *netlink.AttributeEncoder) error {
+	for _, attr := range []struct {
+		typ uint16
+		v   *int32
+	}{
+		{unix.NETCONFA_IFINDEX, a.IfIndex},
+		{unix.NETCONFA_FORWARDING, a.Forwarding},
+		{unix.NETCONFA_RP_FILTER, a.RPFilter},
+		{unix.NETCONFA_MC_FORWARDING, a.MCForwarding},
+		{unix.NETCONFA_PROXY_NEIGH, a.ProxyNeigh},
+		{unix.NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN, a.IgnoreRoutesWithLinkdown},
+		{unix.NETCONFA_INPUT, a.Input},
+		{unix.NETCONFA_BC_FORWARDING, a.BCForwarding},
+	} {
+		if attr.v != nil {
+			ae.Int32(attr.typ, *attr.v)
+		}
+	}
+
+	return nil
+}
+
+// NetconfService is used to retrieve the IP configuration of interfaces.
+type NetconfService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a NetconfMessage slice
+func (n *NetconfService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]NetconfMessage, error) {
+	msgs, err := n.c.ExecuteContext(ctx, m, family, flags)
+
+	confs := make([]NetconfMessage, len(msgs))
+	for i, msg := range msgs {
+		if nm, ok := msg.(*NetconfMessage); ok {
+			confs[i] = *nm
+		}
+	}
+
+	return confs, err
+}
+
+// Get retrieves the configuration of family for the interface with the given
+// index, which may also be unix.NETCONFA_IFINDEX_ALL or
+// unix.NETCONFA_IFINDEX_DEFAULT.
+func (n *NetconfService) Get(family uint8, index int32) (NetconfMessage, error) {
+	return n.GetContext(context.Background(), family, index)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (n *NetconfService) GetContext(ctx context.Context, family uint8, index int32) (NetconfMessage, error) {
+	req := &NetconfMessage{
+		Family: family,
+		Attributes: &NetconfAttributes{
+			IfIndex: &index,
+		},
+	}
+
+	flags := netlink.Request
+	confs, err := n.execute(ctx, req, unix.RTM_GETNETCONF, flags)
+	if err != nil {
+		return NetconfMessage{}, err
+	}
+
+	if len(confs) != 1 {
+		return NetconfMessage{}, fmt.Errorf("too many/little matches, expected 1, actual %d", len(confs))
+	}
+
+	return confs[0], nil
+}
+
+// List retrieves the configuration of all interfaces for family, or for all
+// families if family is unix.AF_UNSPEC.
+func (n *NetconfService) List(family uint8) ([]NetconfMessage, error) {
+	return n.ListContext(context.Background(), family)
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (n *NetconfService) ListContext(ctx context.Context, family uint8) ([]NetconfMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return n.execute(ctx, &NetconfMessage{Family: family}, unix.RTM_GETNETCONF, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the configurations
+// one at a time. See Conn.ExecuteSeq.
+func (n *NetconfService) ListSeq(family uint8) iter.Seq2[NetconfMessage, error] {
+	return n.ListSeqContext(context.Background(), family)
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (n *NetconfService) ListSeqContext(ctx context.Context, family uint8) iter.Seq2[NetconfMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[NetconfMessage](ctx, n.c, &NetconfMessage{Family: family}, unix.RTM_GETNETCONF, flags)
+}
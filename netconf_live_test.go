@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestNetconf(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	// lo has index 1 in a new network namespace.
+	const lo = 1
+
+	conf, err := conn.Netconf.Get(unix.AF_INET, lo)
+	if err != nil {
+		t.Fatalf("failed to get netconf: %v", err)
+	}
+	if a := conf.Attributes; a == nil || a.IfIndex == nil || *a.IfIndex != lo || a.Forwarding == nil || *a.Forwarding != 0 {
+		t.Fatalf("unexpected netconf of lo: %+v", a)
+	}
+
+	err = conn.Link.SetAFSpec(lo, &LinkAFSpecChange{
+		InetConf:         map[uint16]uint32{unix.IPV4_DEVCONF_FORWARDING: 1},
+		Inet6AddrGenMode: uint8Ptr(unix.IN6_ADDR_GEN_MODE_NONE),
+	})
+	if err != nil {
+		t.Fatalf("failed to set address family configuration: %v", err)
+	}
+
+	link, err := conn.Link.Get(lo)
+	if err != nil {
+		t.Fatalf("failed to get link: %v", err)
+	}
+	s := link.Attributes.AFSpec
+	if s == nil || s.Inet == nil || s.Inet6 == nil {
+		t.Fatalf("expected inet and inet6 configuration: %+v", s)
+	}
+	if got := s.Inet.Conf[unix.IPV4_DEVCONF_FORWARDING]; got != 1 {
+		t.Fatalf("unexpected IPv4 forwarding: %d", got)
+	}
+	if got := s.Inet6.AddrGenMode; got == nil || *got != unix.IN6_ADDR_GEN_MODE_NONE {
+		t.Fatalf("unexpected IPv6 address generation mode: %v", got)
+	}
+	if got := s.Inet6.Conf[unix.DEVCONF_HOPLIMIT]; got == 0 {
+		t.Fatalf("expected IPv6 hop limit, got: %v", s.Inet6.Conf)
+	}
+
+	// The reported configuration, including the IPv6 token the kernel
+	// rejects for lo, is not written back when setting the link again.
+	err = conn.Link.Set(&LinkMessage{
+		Family:     unix.AF_UNSPEC,
+		Index:      lo,
+		Attributes: &LinkAttributes{AFSpec: s},
+	})
+	if err != nil {
+		t.Fatalf("failed to set retrieved link: %v", err)
+	}
+
+	conf, err = conn.Netconf.Get(unix.AF_INET, lo)
+	if err != nil {
+		t.Fatalf("failed to get netconf: %v", err)
+	}
+	if a := conf.Attributes; a.Forwarding == nil || *a.Forwarding != 1 {
+		t.Fatalf("expected forwarding to be enabled: %+v", a)
+	}
+
+	confs, err := conn.Netconf.List(unix.AF_INET)
+	if err != nil {
+		t.Fatalf("failed to list netconf: %v", err)
+	}
+
+	indexes := make(map[int32]bool)
+	for _, c := range confs {
+		if c.Family != unix.AF_INET || c.Attributes == nil || c.Attributes.IfIndex == nil {
+			t.Fatalf("unexpected netconf message: %+v", c)
+		}
+		indexes[*c.Attributes.IfIndex] = true
+	}
+	for _, i := range []int32{lo, unix.NETCONFA_IFINDEX_ALL, unix.NETCONFA_IFINDEX_DEFAULT} {
+		if !indexes[i] {
+			t.Fatalf("expected netconf of interface %d, got: %v", i, indexes)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestNetconfMessage(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m            Message
+		b            []byte
+		marshalErr   error
+		unmarshalErr error
+	}{
+		"empty": {
+			m: &NetconfMessage{},
+			b: []byte{0x00, 0x00, 0x00, 0x00},
+		},
+		"request": {
+			m: &NetconfMessage{
+				Family: unix.AF_INET6,
+				Attributes: &NetconfAttributes{
+					IfIndex: int32Ptr(1),
+				},
+			},
+			b: []byte{
+				0x0a, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+		},
+		"all": {
+			m: &NetconfMessage{
+				Family: unix.AF_INET,
+				Attributes: &NetconfAttributes{
+					IfIndex:                  int32Ptr(unix.NETCONFA_IFINDEX_ALL),
+					Forwarding:               int32Ptr(1),
+					RPFilter:                 int32Ptr(2),
+					MCForwarding:             int32Ptr(0),
+					ProxyNeigh:               int32Ptr(0),
+					IgnoreRoutesWithLinkdown: int32Ptr(0),
+					BCForwarding:             int32Ptr(0),
+				},
+			},
+			b: []byte{
+				0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0xff, 0xff, 0xff, 0xff,
+				0x08, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x08, 0x00, 0x03, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x06, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b []byte
+			t.Run("marshal", func(t *testing.T) {
+				var marshalErr error
+				b, marshalErr = tt.m.MarshalBinary()
+
+				if !errors.Is(marshalErr, tt.marshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.marshalErr, marshalErr)
+				}
+			})
+
+			t.Run("compare bytes", func(t *testing.T) {
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+
+			m := &NetconfMessage{}
+			t.Run("unmarshal", func(t *testing.T) {
+				unmarshalErr := (m).UnmarshalBinary(b)
+				if !errors.Is(unmarshalErr, tt.unmarshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.unmarshalErr, unmarshalErr)
+				}
+			})
+
+			t.Run("compare messages", func(t *testing.T) {
+				if !reflect.DeepEqual(tt.m, m) {
+					t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", tt.m, m)
+				}
+			})
+		})
+	}
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &NetconfMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x00, 0x01, 0x02})
+		if !errors.Is(unmarshalErr, errInvalidNetconfMessage) {
+			t.Fatalf("Expected 'errInvalidNetconfMessage' but got '%v'", unmarshalErr)
+		}
+	})
+}
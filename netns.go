@@ -1,18 +1,31 @@
 package rtnetlink
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
 )
 
 // NetNS represents a Linux network namespace handle to specify in
 // [LinkAttributes].
 //
 // Use [NetNSForPID] to create a handle to the network namespace of an existing
-// PID, or [NetNSForFD] for a handle to an existing network namespace created by
-// another library.
+// PID, [NetNSForPath] for a handle to a network namespace bind mounted to a
+// file, or [NetNSForFD] for a handle to an existing network namespace created by
+// another library. Package netns creates and opens network namespaces and
+// returns a NetNS for them.
+//
+// A NetNS can also be passed to [DialNetNS] to dial a Conn inside the network
+// namespace.
 type NetNS struct {
 	fd  *uint32
 	pid *uint32
+
+	// file is the namespace file opened by NetNSForPath, owned by the NetNS.
+	file *os.File
 }
 
 // NetNSForPID returns a handle to the network namespace of an existing process
@@ -34,6 +47,64 @@ func NetNSForFD(fd uint32) *NetNS {
 	return &NetNS{fd: &fd}
 }
 
+// NetNSForPath returns a handle to the network namespace referred to by path,
+// such as a bind mount created by 'ip netns add' in /run/netns or
+// /proc/<pid>/ns/net.
+//
+// Unlike the other handles, the NetNS owns the file it opens and keeps the
+// network namespace alive until it is closed with Close.
+func NetNSForPath(path string) (*NetNS, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fd := uint32(f.Fd())
+	return &NetNS{fd: &fd, file: f}, nil
+}
+
+// Close closes the file of a NetNS returned by NetNSForPath, after which the
+// NetNS must not be used anymore. It does nothing for other handles, whose
+// lifecycle is managed by the caller.
+func (ns *NetNS) Close() error {
+	if ns.file == nil {
+		return nil
+	}
+	return ns.file.Close()
+}
+
+// DialNetNS dials a route netlink connection inside the network namespace ns,
+// independent of the network namespace of the calling thread. The Conn stays
+// in ns for its whole lifetime, so a process can hold a Conn for each of many
+// network namespaces. ns is only used while dialing and can be closed once
+// DialNetNS returns. A nil ns dials the current network namespace, like Dial.
+//
+// Config specifies optional configuration for the underlying netlink
+// connection, its NetNS is overridden.
+func DialNetNS(ns *NetNS, config *netlink.Config) (*Conn, error) {
+	var c netlink.Config
+	if config != nil {
+		c = *config
+	}
+
+	if ns != nil {
+		switch {
+		case ns.fd != nil:
+			c.NetNS = int(*ns.fd)
+		case ns.pid != nil:
+			f, err := os.Open(fmt.Sprintf("/proc/%d/ns/net", *ns.pid))
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+
+			c.NetNS = int(f.Fd())
+		}
+	}
+
+	return Dial(&c)
+}
+
 // value returns the type and value of the NetNS for use in netlink attributes.
 func (ns *NetNS) value() (uint16, uint32) {
 	if ns.fd != nil {
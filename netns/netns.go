@@ -0,0 +1,84 @@
+// Package netns manages Linux network namespaces for use with the rtnetlink
+// library.
+//
+// Named network namespaces are compatible with those of 'ip netns': they are
+// kept alive by a bind mount of the namespace to a file in /run/netns, and can
+// be used with 'ip netns exec' and vice versa.
+//
+//	ns, err := netns.New("blue")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer ns.Close()
+//
+//	conn, err := ns.Dial(nil)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer conn.Close()
+//
+// Network namespaces are only supported on Linux, on other platforms all
+// functions return an error.
+package netns
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jsimonetti/rtnetlink/v2"
+	"github.com/mdlayher/netlink"
+)
+
+// Dir is the directory holding the bind mounts of named network namespaces,
+// as used by 'ip netns'.
+const Dir = "/run/netns"
+
+// A NetNS is an open handle to a network namespace. The namespace is kept
+// alive while the handle is open, even if it is deleted in the meantime.
+type NetNS struct {
+	f    *os.File
+	name string
+}
+
+// Name returns the name of a named network namespace, or an empty string for
+// a namespace without a name.
+func (ns *NetNS) Name() string {
+	return ns.name
+}
+
+// FD returns the file descriptor of the handle. It is valid until the handle
+// is closed.
+func (ns *NetNS) FD() int {
+	return int(ns.f.Fd())
+}
+
+// Close closes the handle. The network namespace disappears if nothing else
+// refers to it, such as the bind mount of a named namespace or a process.
+func (ns *NetNS) Close() error {
+	return ns.f.Close()
+}
+
+// NetNS returns a handle to the network namespace to specify in
+// rtnetlink.LinkAttributes, e.g. to move a link into the namespace. It is valid
+// until ns is closed.
+func (ns *NetNS) NetNS() *rtnetlink.NetNS {
+	return rtnetlink.NetNSForFD(uint32(ns.FD()))
+}
+
+// Dial dials a route netlink connection inside the network namespace. Config
+// specifies optional configuration for the underlying netlink connection, its
+// NetNS is overridden.
+func (ns *NetNS) Dial(config *netlink.Config) (*rtnetlink.Conn, error) {
+	return rtnetlink.DialNetNS(ns.NetNS(), config)
+}
+
+// path returns the path of the bind mount of the named network namespace.
+func path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsRune(name, '/') {
+		return "", fmt.Errorf("netns: invalid network namespace name %q", name)
+	}
+
+	return filepath.Join(Dir, name), nil
+}
@@ -0,0 +1,180 @@
+package netns
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// New creates a new network namespace with the given name, like
+// 'ip netns add'. The namespace lives until it is deleted with Delete, the
+// returned handle has to be closed by the caller.
+func New(name string) (*NetNS, error) {
+	p, err := path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prepareDir(); err != nil {
+		return nil, err
+	}
+
+	// Create the mount point, which fails if the namespace already exists.
+	f, err := os.OpenFile(p, os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0)
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Close()
+
+	if err := bindNew(p); err != nil {
+		_ = os.Remove(p)
+		return nil, err
+	}
+
+	return Open(name)
+}
+
+// prepareDir creates Dir and turns it into a shared mount point, so that the
+// bind mounts of namespaces propagate to other mount namespaces. This follows
+// what 'ip netns add' does.
+func prepareDir() error {
+	if err := os.MkdirAll(Dir, 0o755); err != nil {
+		return err
+	}
+
+	err := unix.Mount("", Dir, "none", unix.MS_SHARED|unix.MS_REC, "")
+	if !errors.Is(err, unix.EINVAL) {
+		return os.NewSyscallError("mount", err)
+	}
+
+	// Dir is no mount point yet, bind mount it onto itself first.
+	if err := unix.Mount(Dir, Dir, "none", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return os.NewSyscallError("mount", err)
+	}
+
+	return os.NewSyscallError("mount", unix.Mount("", Dir, "none", unix.MS_SHARED|unix.MS_REC, ""))
+}
+
+// bindNew creates a new network namespace and bind mounts it to path.
+func bindNew(path string) error {
+	errc := make(chan error, 1)
+	go func() {
+		// Never unlock the goroutine from its thread, so that the thread
+		// terminates with the goroutine instead of being reused while it is
+		// in the new namespace.
+		runtime.LockOSThread()
+
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			errc <- os.NewSyscallError("unshare", err)
+			return
+		}
+
+		src := fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid())
+		errc <- os.NewSyscallError("mount", unix.Mount(src, path, "none", unix.MS_BIND, ""))
+	}()
+
+	return <-errc
+}
+
+// Open opens the named network namespace, created by New or 'ip netns add'.
+func Open(name string) (*NetNS, error) {
+	p, err := path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(p, name)
+}
+
+// OpenPath opens the network namespace referred to by a file, such as
+// /proc/<pid>/ns/net or a bind mount of a namespace.
+func OpenPath(path string) (*NetNS, error) {
+	return open(path, "")
+}
+
+// Current opens the network namespace of the calling goroutine.
+func Current() (*NetNS, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	return OpenPath(fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid()))
+}
+
+func open(path, name string) (*NetNS, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// A file which is not bind mounted to a namespace, e.g. a leftover of a
+	// deleted namespace, belongs to the file system holding it.
+	var st unix.Statfs_t
+	if err := unix.Fstatfs(int(f.Fd()), &st); err != nil {
+		_ = f.Close()
+		return nil, os.NewSyscallError("fstatfs", err)
+	}
+	if st.Type != unix.NSFS_MAGIC {
+		_ = f.Close()
+		return nil, fmt.Errorf("netns: %s is not a namespace", path)
+	}
+
+	return &NetNS{f: f, name: name}, nil
+}
+
+// List returns the names of the named network namespaces.
+func List() ([]string, error) {
+	entries, err := os.ReadDir(Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	return names, nil
+}
+
+// Delete deletes the named network namespace, like 'ip netns delete'. The
+// namespace itself disappears once nothing else refers to it, such as open
+// handles or processes.
+func Delete(name string) error {
+	p, err := path(name)
+	if err != nil {
+		return err
+	}
+
+	// EINVAL is returned for a mount point without a namespace mounted.
+	if err := unix.Unmount(p, unix.MNT_DETACH); err != nil && !errors.Is(err, unix.EINVAL) {
+		return os.NewSyscallError("umount2", err)
+	}
+
+	return os.Remove(p)
+}
+
+// Do runs fn in the network namespace and returns its error. fn runs on a
+// separate goroutine locked to an OS thread which has joined the namespace,
+// goroutines started by fn do not run in the namespace.
+func (ns *NetNS) Do(fn func() error) error {
+	errc := make(chan error, 1)
+	go func() {
+		// Never unlock the goroutine from its thread, see bindNew.
+		runtime.LockOSThread()
+
+		if err := unix.Setns(ns.FD(), unix.CLONE_NEWNET); err != nil {
+			errc <- os.NewSyscallError("setns", err)
+			return
+		}
+
+		errc <- fn()
+	}()
+
+	return <-errc
+}
@@ -0,0 +1,79 @@
+//go:build integration
+// +build integration
+
+package netns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestNetNS(t *testing.T) {
+	name := fmt.Sprintf("rtnetlink-test-%d", os.Getpid())
+
+	ns, err := New(name)
+	if err != nil {
+		t.Fatalf("failed to create network namespace: %v", err)
+	}
+	defer ns.Close()
+	defer Delete(name)
+
+	if _, err := New(name); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("expected ErrExist creating a duplicate namespace, got: %v", err)
+	}
+
+	names, err := List()
+	if err != nil {
+		t.Fatalf("failed to list network namespaces: %v", err)
+	}
+	if !slices.Contains(names, name) {
+		t.Fatalf("network namespace %s not listed in %v", name, names)
+	}
+
+	// A fresh namespace only holds a loopback interface.
+	conn, err := ns.Dial(nil)
+	if err != nil {
+		t.Fatalf("failed to dial netlink in namespace: %v", err)
+	}
+	defer conn.Close()
+
+	links, err := conn.Link.List()
+	if err != nil {
+		t.Fatalf("failed to list links: %v", err)
+	}
+	if len(links) != 1 || links[0].Attributes.Name != "lo" {
+		t.Fatalf("unexpected links in namespace: %+v", links)
+	}
+
+	err = ns.Do(func() error {
+		ifis, err := net.Interfaces()
+		if err != nil {
+			return err
+		}
+		if len(ifis) != 1 || ifis[0].Name != "lo" {
+			return fmt.Errorf("unexpected interfaces: %+v", ifis)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to run in namespace: %v", err)
+	}
+
+	// The namespace can be opened by name until it is deleted.
+	ns2, err := Open(name)
+	if err != nil {
+		t.Fatalf("failed to open network namespace: %v", err)
+	}
+	ns2.Close()
+
+	if err := Delete(name); err != nil {
+		t.Fatalf("failed to delete network namespace: %v", err)
+	}
+	if _, err := Open(name); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist opening a deleted namespace, got: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !linux
+// +build !linux
+
+package netns
+
+import (
+	"fmt"
+	"runtime"
+)
+
+var errUnsupported = fmt.Errorf("netns: network namespaces are not supported on %s", runtime.GOOS)
+
+// New is not supported on this platform.
+func New(name string) (*NetNS, error) {
+	return nil, errUnsupported
+}
+
+// Open is not supported on this platform.
+func Open(name string) (*NetNS, error) {
+	return nil, errUnsupported
+}
+
+// OpenPath is not supported on this platform.
+func OpenPath(path string) (*NetNS, error) {
+	return nil, errUnsupported
+}
+
+// Current is not supported on this platform.
+func Current() (*NetNS, error) {
+	return nil, errUnsupported
+}
+
+// List is not supported on this platform.
+func List() ([]string, error) {
+	return nil, errUnsupported
+}
+
+// Delete is not supported on this platform.
+func Delete(name string) error {
+	return errUnsupported
+}
+
+// Do is not supported on this platform.
+func (ns *NetNS) Do(fn func() error) error {
+	return errUnsupported
+}
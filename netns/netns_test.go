@@ -0,0 +1,47 @@
+//go:build linux
+// +build linux
+
+package netns
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	for _, name := range []string{"", ".", "..", "a/b", "../etc"} {
+		if _, err := path(name); err == nil {
+			t.Fatalf("expected an error for name %q", name)
+		}
+	}
+
+	p, err := path("blue")
+	if err != nil {
+		t.Fatalf("failed to get path: %v", err)
+	}
+	if want := "/run/netns/blue"; p != want {
+		t.Fatalf("unexpected path: want %s, got %s", want, p)
+	}
+}
+
+func TestOpenPath(t *testing.T) {
+	ns, err := Current()
+	if err != nil {
+		t.Fatalf("failed to open current network namespace: %v", err)
+	}
+	defer ns.Close()
+
+	if ns.Name() != "" {
+		t.Fatalf("unexpected name: %q", ns.Name())
+	}
+
+	// Files other than namespaces are rejected.
+	f := filepath.Join(t.TempDir(), "netns")
+	if err := os.WriteFile(f, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenPath(f); err == nil {
+		t.Fatal("expected an error opening a regular file")
+	}
+}
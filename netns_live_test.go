@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestDialNetNS(t *testing.T) {
+	fd := testutils.NetNS(t)
+
+	path, err := NetNSForPath(fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), fd))
+	if err != nil {
+		t.Fatalf("failed to open network namespace: %v", err)
+	}
+	defer path.Close()
+
+	for _, ns := range []*NetNS{NetNSForFD(uint32(fd)), path} {
+		conn, err := DialNetNS(ns, nil)
+		if err != nil {
+			t.Fatalf("failed to dial network namespace: %v", err)
+		}
+		defer conn.Close()
+
+		// A fresh network namespace only holds a loopback interface, which is
+		// down.
+		links, err := conn.Link.List()
+		if err != nil {
+			t.Fatalf("failed to list links: %v", err)
+		}
+		if len(links) != 1 || links[0].Attributes.Name != "lo" || links[0].Flags&unix.IFF_UP != 0 {
+			t.Fatalf("unexpected links: %+v", links)
+		}
+	}
+
+	// A process handle dials the namespace of the process.
+	conn, err := DialNetNS(NetNSForPID(uint32(os.Getpid())), nil)
+	if err != nil {
+		t.Fatalf("failed to dial network namespace of pid: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Link.Get(lo); err != nil {
+		t.Fatalf("failed to get loopback interface: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+package rtnetlink
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestNetNSForPath(t *testing.T) {
+	ns, err := NetNSForPath("/proc/self/ns/net")
+	if err != nil {
+		t.Fatalf("failed to open network namespace: %v", err)
+	}
+
+	typ, fd := ns.value()
+	if typ != unix.IFLA_NET_NS_FD || fd != uint32(ns.file.Fd()) {
+		t.Fatalf("unexpected value: type %d, fd %d", typ, fd)
+	}
+
+	if err := ns.Close(); err != nil {
+		t.Fatalf("failed to close network namespace: %v", err)
+	}
+	if err := ns.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected ErrClosed closing twice, got: %v", err)
+	}
+
+	if _, err := NetNSForPath("/nonexistent"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got: %v", err)
+	}
+
+	// Handles not created from a path are owned by the caller.
+	if err := NetNSForFD(uint32(os.Stdin.Fd())).Close(); err != nil {
+		t.Fatalf("unexpected error closing a NetNS from a fd: %v", err)
+	}
+}
@@ -0,0 +1,471 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+var (
+	// errInvalidNexthopMessage is returned when a NexthopMessage is malformed.
+	errInvalidNexthopMessage = errors.New("rtnetlink NexthopMessage is invalid or too short")
+
+	// errInvalidNexthopGroup is returned when a NHA_GROUP attribute is malformed.
+	errInvalidNexthopGroup = errors.New("rtnetlink NexthopMessage contains an invalid nexthop group")
+)
+
+var _ Message = &NexthopMessage{}
+
+// A NexthopMessage is a route netlink nexthop object message.
+type NexthopMessage struct {
+	// Address family of the gateway (unix.AF_INET or unix.AF_INET6). Groups
+	// and blackhole nexthops use unix.AF_UNSPEC, fdb nexthops use the family
+	// of their gateway.
+	Family uint8
+
+	// Distance to the destination
+	Scope uint8
+
+	// Protocol which installed the nexthop
+	Protocol uint8
+
+	// Nexthop flags (unix.RTNH_F_*)
+	Flags uint32
+
+	// Optional attributes which are appended when not nil.
+	Attributes *NexthopAttributes
+}
+
+// MarshalBinary marshals a NexthopMessage into a byte slice.
+func (m *NexthopMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, unix.SizeofNhmsg)
+
+	b[0] = m.Family
+	b[1] = m.Scope
+	b[2] = m.Protocol
+	// b[3] is reserved
+	nativeEndian.PutUint32(b[4:8], m.Flags)
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a NexthopMessage.
+func (m *NexthopMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < unix.SizeofNhmsg {
+		return errInvalidNexthopMessage
+	}
+
+	m.Family = b[0]
+	m.Scope = b[1]
+	m.Protocol = b[2]
+	m.Flags = nativeEndian.Uint32(b[4:8])
+
+	if l > unix.SizeofNhmsg {
+		m.Attributes = &NexthopAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[unix.SizeofNhmsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*NexthopMessage) rtMessage() {}
+
+// NexthopService is used to retrieve rtnetlink family information.
+type NexthopService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a NexthopMessage slice
+func (n *NexthopService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]NexthopMessage, error) {
+	msgs, err := n.c.ExecuteContext(ctx, m, family, flags)
+
+	nexthops := make([]NexthopMessage, len(msgs))
+	for i, msg := range msgs {
+		if nh, ok := msg.(*NexthopMessage); ok {
+			nexthops[i] = *nh
+		}
+	}
+
+	return nexthops, err
+}
+
+// Add creates a new nexthop or nexthop group.
+func (n *NexthopService) Add(req *NexthopMessage) error {
+	return n.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (n *NexthopService) AddContext(ctx context.Context, req *NexthopMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
+	_, err := n.c.ExecuteContext(ctx, req, unix.RTM_NEWNEXTHOP, flags)
+
+	return err
+}
+
+// Replace replaces or adds a nexthop or nexthop group.
+func (n *NexthopService) Replace(req *NexthopMessage) error {
+	return n.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (n *NexthopService) ReplaceContext(ctx context.Context, req *NexthopMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
+	_, err := n.c.ExecuteContext(ctx, req, unix.RTM_NEWNEXTHOP, flags)
+
+	return err
+}
+
+// Delete removes a nexthop or nexthop group by ID.
+func (n *NexthopService) Delete(id uint32) error {
+	return n.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (n *NexthopService) DeleteContext(ctx context.Context, id uint32) error {
+	req := &NexthopMessage{
+		Attributes: &NexthopAttributes{
+			ID: id,
+		},
+	}
+
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := n.c.ExecuteContext(ctx, req, unix.RTM_DELNEXTHOP, flags)
+
+	return err
+}
+
+// Get retrieves a nexthop or nexthop group by ID.
+func (n *NexthopService) Get(id uint32) (NexthopMessage, error) {
+	return n.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (n *NexthopService) GetContext(ctx context.Context, id uint32) (NexthopMessage, error) {
+	req := &NexthopMessage{
+		Attributes: &NexthopAttributes{
+			ID: id,
+		},
+	}
+
+	flags := netlink.Request
+	nexthops, err := n.execute(ctx, req, unix.RTM_GETNEXTHOP, flags)
+	if err != nil {
+		return NexthopMessage{}, err
+	}
+
+	if len(nexthops) != 1 {
+		return NexthopMessage{}, fmt.Errorf("too many/little matches, expected 1, actual %d", len(nexthops))
+	}
+
+	return nexthops[0], nil
+}
+
+// List retrieves all nexthops and nexthop groups.
+func (n *NexthopService) List() ([]NexthopMessage, error) {
+	return n.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (n *NexthopService) ListContext(ctx context.Context) ([]NexthopMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return n.execute(ctx, &NexthopMessage{}, unix.RTM_GETNEXTHOP, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the nexthops
+// one at a time. See Conn.ExecuteSeq.
+func (n *NexthopService) ListSeq() iter.Seq2[NexthopMessage, error] {
+	return n.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (n *NexthopService) ListSeqContext(ctx context.Context) iter.Seq2[NexthopMessage, error] {
+	return n.ListMatchSeqContext(ctx, &NexthopMessage{})
+}
+
+// ListMatch retrieves the nexthops and nexthop groups matching req, which are
+// filtered by the kernel. The Family of req and the OutIface, Groups, Master
+// and FDB of its attributes are used as filter when set, all other fields are
+// ignored.
+func (n *NexthopService) ListMatch(req *NexthopMessage) ([]NexthopMessage, error) {
+	return n.ListMatchContext(context.Background(), req)
+}
+
+// ListMatchContext is like ListMatch, but takes a context. See Conn.ExecuteContext.
+func (n *NexthopService) ListMatchContext(ctx context.Context, req *NexthopMessage) ([]NexthopMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return n.execute(ctx, nexthopDumpFilter(req), unix.RTM_GETNEXTHOP, flags)
+}
+
+// ListMatchSeq is like ListMatch, but returns an iterator which yields the nexthops
+// one at a time. See Conn.ExecuteSeq.
+func (n *NexthopService) ListMatchSeq(req *NexthopMessage) iter.Seq2[NexthopMessage, error] {
+	return n.ListMatchSeqContext(context.Background(), req)
+}
+
+// ListMatchSeqContext is like ListMatchSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (n *NexthopService) ListMatchSeqContext(ctx context.Context, req *NexthopMessage) iter.Seq2[NexthopMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[NexthopMessage](ctx, n.c, nexthopDumpFilter(req), unix.RTM_GETNEXTHOP, flags)
+}
+
+// nexthopDumpFilter returns the dump request for the nexthops matching req.
+func nexthopDumpFilter(req *NexthopMessage) *NexthopMessage {
+	filter := &NexthopMessage{Family: req.Family}
+	if a := req.Attributes; a != nil {
+		filter.Attributes = &NexthopAttributes{
+			OutIface: a.OutIface,
+			Groups:   a.Groups,
+			Master:   a.Master,
+			FDB:      a.FDB,
+		}
+	}
+
+	return filter
+}
+
+// NexthopAttributes contains all attributes for a nexthop.
+type NexthopAttributes struct {
+	// Unique ID of the nexthop. Zero lets the kernel allocate an ID when
+	// adding a nexthop.
+	ID uint32
+
+	// Members of a nexthop group. A group contains no gateway, output
+	// interface or blackhole of its own.
+	Group []NexthopGroupEntry
+
+	// Type of a nexthop group (unix.NEXTHOP_GRP_TYPE_*). The zero value is a
+	// multipath group.
+	GroupType uint16
+
+	// Configuration of a resilient nexthop group.
+	ResGroup *NexthopResGroup
+
+	// Blackhole drops packets using the nexthop.
+	Blackhole bool
+
+	// Output interface index
+	OutIface uint32
+
+	// Gateway address
+	Gateway net.IP
+
+	// Groups restricts a dump to nexthop groups.
+	Groups bool
+
+	// Master restricts a dump to nexthops using interfaces enslaved to the
+	// given master device.
+	Master uint32
+
+	// FDB marks a nexthop or group used by the bridge FDB (e.g. for VXLAN).
+	FDB bool
+}
+
+func (a *NexthopAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NHA_ID:
+			a.ID = ad.Uint32()
+		case unix.NHA_GROUP:
+			ad.Do(a.decodeGroup)
+		case unix.NHA_GROUP_TYPE:
+			a.GroupType = ad.Uint16()
+		case unix.NHA_RES_GROUP:
+			a.ResGroup = &NexthopResGroup{}
+			ad.Nested(a.ResGroup.decode)
+		case unix.NHA_BLACKHOLE:
+			a.Blackhole = true
+		case unix.NHA_OIF:
+			a.OutIface = ad.Uint32()
+		case unix.NHA_GATEWAY:
+			ad.Do(decodeIP(&a.Gateway))
+		case unix.NHA_GROUPS:
+			a.Groups = true
+		case unix.NHA_MASTER:
+			a.Master = ad.Uint32()
+		case unix.NHA_FDB:
+			a.FDB = true
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *NexthopAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.ID != 0 {
+		ae.Uint32(unix.NHA_ID, a.ID)
+	}
+
+	if len(a.Group) > 0 {
+		ae.Do(unix.NHA_GROUP, a.encodeGroup)
+	}
+
+	if a.GroupType != 0 {
+		ae.Uint16(unix.NHA_GROUP_TYPE, a.GroupType)
+	}
+
+	if a.ResGroup != nil {
+		ae.Nested(unix.NHA_RES_GROUP, a.ResGroup.encode)
+	}
+
+	if a.Blackhole {
+		ae.Flag(unix.NHA_BLACKHOLE, true)
+	}
+
+	if a.OutIface != 0 {
+		ae.Uint32(unix.NHA_OIF, a.OutIface)
+	}
+
+	if a.Gateway != nil {
+		ae.Do(unix.NHA_GATEWAY, encodeIP(a.Gateway))
+	}
+
+	if a.Groups {
+		ae.Flag(unix.NHA_GROUPS, true)
+	}
+
+	if a.Master != 0 {
+		ae.Uint32(unix.NHA_MASTER, a.Master)
+	}
+
+	if a.FDB {
+		ae.Flag(unix.NHA_FDB, true)
+	}
+
+	return nil
+}
+
+// sizeofNexthopGrp is the size of a struct nexthop_grp.
+const sizeofNexthopGrp = 8
+
+// encodeGroup encodes Group as an array of struct nexthop_grp.
+func (a *NexthopAttributes) encodeGroup() ([]byte, error) {
+	b := make([]byte, len(a.Group)*sizeofNexthopGrp)
+	for i, e := range a.Group {
+		// The kernel stores the weight minus one, so that the full range of
+		// weights fits the available bits.
+		w := e.Weight
+		if w == 0 {
+			w = 1
+		}
+		w--
+
+		off := i * sizeofNexthopGrp
+		nativeEndian.PutUint32(b[off:off+4], e.ID)
+		b[off+4] = uint8(w)
+		b[off+5] = uint8(w >> 8)
+	}
+
+	return b, nil
+}
+
+// decodeGroup decodes an array of struct nexthop_grp into Group.
+func (a *NexthopAttributes) decodeGroup(b []byte) error {
+	if len(b)%sizeofNexthopGrp != 0 {
+		return errInvalidNexthopGroup
+	}
+
+	a.Group = make([]NexthopGroupEntry, 0, len(b)/sizeofNexthopGrp)
+	for off := 0; off < len(b); off += sizeofNexthopGrp {
+		a.Group = append(a.Group, NexthopGroupEntry{
+			ID:     nativeEndian.Uint32(b[off : off+4]),
+			Weight: (uint16(b[off+5])<<8 | uint16(b[off+4])) + 1,
+		})
+	}
+
+	return nil
+}
+
+// A NexthopGroupEntry is a member of a nexthop group.
+type NexthopGroupEntry struct {
+	// ID of the member nexthop
+	ID uint32
+
+	// Weight of the member in the group. Zero is treated as a weight of 1.
+	Weight uint16
+}
+
+// NexthopResGroup contains the configuration of a resilient nexthop group
+// (NHA_RES_GROUP). Timers are in clock ticks (USER_HZ).
+type NexthopResGroup struct {
+	// Number of hash buckets of the group. It can not be changed once the
+	// group was created.
+	Buckets *uint16
+
+	// Time a bucket must be idle before it is migrated to another nexthop
+	// to restore balance.
+	IdleTimer *uint32
+
+	// Time after which idle buckets are migrated regardless of their
+	// activity if the group stays unbalanced.
+	UnbalancedTimer *uint32
+
+	// Time the group has been unbalanced. Only reported by the kernel.
+	UnbalancedTime *uint64
+}
+
+func (r *NexthopResGroup) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NHA_RES_GROUP_BUCKETS:
+			v := ad.Uint16()
+			r.Buckets = &v
+		case unix.NHA_RES_GROUP_IDLE_TIMER:
+			v := ad.Uint32()
+			r.IdleTimer = &v
+		case unix.NHA_RES_GROUP_UNBALANCED_TIMER:
+			v := ad.Uint32()
+			r.UnbalancedTimer = &v
+		case unix.NHA_RES_GROUP_UNBALANCED_TIME:
+			v := ad.Uint64()
+			r.UnbalancedTime = &v
+		}
+	}
+
+	return nil
+}
+
+func (r *NexthopResGroup) encode(ae *netlink.AttributeEncoder) error {
+	if r.Buckets != nil {
+		ae.Uint16(unix.NHA_RES_GROUP_BUCKETS, *r.Buckets)
+	}
+
+	if r.IdleTimer != nil {
+		ae.Uint32(unix.NHA_RES_GROUP_IDLE_TIMER, *r.IdleTimer)
+	}
+
+	if r.UnbalancedTimer != nil {
+		ae.Uint32(unix.NHA_RES_GROUP_UNBALANCED_TIMER, *r.UnbalancedTimer)
+	}
+
+	return nil
+}
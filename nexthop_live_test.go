@@ -0,0 +1,105 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestNexthopGroupRoute(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	// Nexthops need their interface to be up.
+	if err := conn.Link.Set(&LinkMessage{Index: lo, Flags: unix.IFF_UP, Change: unix.IFF_UP}); err != nil {
+		t.Fatalf("failed to set lo up: %v", err)
+	}
+
+	for _, id := range []uint32{1, 2} {
+		err := conn.Nexthop.Add(&NexthopMessage{
+			Family: unix.AF_INET,
+			Attributes: &NexthopAttributes{
+				ID:       id,
+				OutIface: lo,
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to add nexthop %d: %v", id, err)
+		}
+	}
+
+	err = conn.Nexthop.Add(&NexthopMessage{
+		Attributes: &NexthopAttributes{
+			ID: 10,
+			Group: []NexthopGroupEntry{
+				{ID: 1, Weight: 1},
+				{ID: 2, Weight: 3},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to add nexthop group: %v", err)
+	}
+
+	group, err := conn.Nexthop.Get(10)
+	if err != nil {
+		t.Fatalf("failed to get nexthop group: %v", err)
+	}
+	if want, got := []NexthopGroupEntry{{ID: 1, Weight: 1}, {ID: 2, Weight: 3}}, group.Attributes.Group; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("unexpected nexthop group:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	route := &RouteMessage{
+		Family:    unix.AF_INET,
+		DstLength: 24,
+		Table:     unix.RT_TABLE_MAIN,
+		Protocol:  unix.RTPROT_BOOT,
+		Scope:     unix.RT_SCOPE_UNIVERSE,
+		Type:      unix.RTN_UNICAST,
+		Attributes: RouteAttributes{
+			Dst:  net.IPv4(192, 0, 2, 0).To4(),
+			NhID: 10,
+		},
+	}
+	if err := conn.Route.Add(route); err != nil {
+		t.Fatalf("failed to add route using nexthop group: %v", err)
+	}
+
+	routes, err := conn.Route.List()
+	if err != nil {
+		t.Fatalf("failed to list routes: %v", err)
+	}
+	var found bool
+	for _, r := range routes {
+		if r.Attributes.Dst.Equal(route.Attributes.Dst) && r.Attributes.NhID == 10 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("route using nexthop group not found")
+	}
+
+	nexthops, err := conn.Nexthop.List()
+	if err != nil {
+		t.Fatalf("failed to list nexthops: %v", err)
+	}
+	if want, got := 3, len(nexthops); want != got {
+		t.Fatalf("unexpected number of nexthops: want %d, got %d", want, got)
+	}
+
+	if err := conn.Nexthop.Delete(10); err != nil {
+		t.Fatalf("failed to delete nexthop group: %v", err)
+	}
+	if _, err := conn.Nexthop.Get(10); err == nil {
+		t.Fatal("expected error getting deleted nexthop group")
+	}
+}
@@ -0,0 +1,134 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestNexthopMessage(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m            Message
+		b            []byte
+		marshalErr   error
+		unmarshalErr error
+	}{
+		"empty": {
+			m: &NexthopMessage{},
+			b: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		"gateway": {
+			m: &NexthopMessage{
+				Family:   2,
+				Scope:    253,
+				Protocol: 4,
+				Flags:    4,
+				Attributes: &NexthopAttributes{
+					ID:       1,
+					OutIface: 2,
+					Gateway:  net.IPv4(192, 0, 2, 1).To4(),
+				},
+			},
+			b: []byte{
+				0x02, 0xfd, 0x04, 0x00, 0x04, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x01, 0x00,
+				0x00, 0x00, 0x08, 0x00, 0x05, 0x00, 0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x06, 0x00,
+				0xc0, 0x00, 0x02, 0x01,
+			},
+		},
+		"resilient group": {
+			m: &NexthopMessage{
+				Attributes: &NexthopAttributes{
+					ID: 10,
+					Group: []NexthopGroupEntry{
+						{ID: 1, Weight: 1},
+						{ID: 2, Weight: 300},
+					},
+					GroupType: 1,
+					ResGroup: &NexthopResGroup{
+						Buckets:         uint16Ptr(64),
+						IdleTimer:       uint32Ptr(120),
+						UnbalancedTimer: uint32Ptr(300),
+					},
+					FDB: true,
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x0a, 0x00,
+				0x00, 0x00, 0x14, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x2b, 0x01, 0x00, 0x00, 0x06, 0x00, 0x03, 0x00, 0x01, 0x00,
+				0x00, 0x00, 0x1c, 0x00, 0x0c, 0x80, 0x06, 0x00, 0x01, 0x00, 0x40, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x02, 0x00, 0x78, 0x00, 0x00, 0x00, 0x08, 0x00, 0x03, 0x00, 0x2c, 0x01,
+				0x00, 0x00, 0x04, 0x00, 0x0b, 0x00,
+			},
+		},
+		"blackhole": {
+			m: &NexthopMessage{
+				Attributes: &NexthopAttributes{
+					ID:        3,
+					Blackhole: true,
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x03, 0x00,
+				0x00, 0x00, 0x04, 0x00, 0x04, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b []byte
+			t.Run("marshal", func(t *testing.T) {
+				var marshalErr error
+				b, marshalErr = tt.m.MarshalBinary()
+
+				if !errors.Is(marshalErr, tt.marshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.marshalErr, marshalErr)
+				}
+			})
+
+			t.Run("compare bytes", func(t *testing.T) {
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+
+			m := &NexthopMessage{}
+			t.Run("unmarshal", func(t *testing.T) {
+				unmarshalErr := (m).UnmarshalBinary(b)
+				if !errors.Is(unmarshalErr, tt.unmarshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.unmarshalErr, unmarshalErr)
+				}
+			})
+
+			t.Run("compare messages", func(t *testing.T) {
+				if !reflect.DeepEqual(tt.m, m) {
+					t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", tt.m, m)
+				}
+			})
+		})
+	}
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &NexthopMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x00, 0x01, 0x2, 0x03})
+		if !errors.Is(unmarshalErr, errInvalidNexthopMessage) {
+			t.Fatalf("Expected 'errInvalidNexthopMessage' but got '%v'", unmarshalErr)
+		}
+	})
+
+	t.Run("invalid group", func(t *testing.T) {
+		m := &NexthopMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x02, 0x00, 0x01, 0x00,
+			0x00, 0x00,
+		})
+		if !errors.Is(unmarshalErr, errInvalidNexthopGroup) {
+			t.Fatalf("Expected 'errInvalidNexthopGroup' but got '%v'", unmarshalErr)
+		}
+	})
+}
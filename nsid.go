@@ -0,0 +1,244 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// errInvalidNSIDMessage is returned when a NSIDMessage is malformed.
+var errInvalidNSIDMessage = errors.New("rtnetlink NSIDMessage is invalid or too short")
+
+// sizeofNSIDMsg is the size of the header of a NSIDMessage, a struct rtgenmsg
+// padded to 4 bytes.
+const sizeofNSIDMsg = 4
+
+var _ Message = &NSIDMessage{}
+
+// A NSIDMessage is a route netlink network namespace ID message.
+//
+// Network namespace IDs identify peer network namespaces relative to the
+// namespace of the Conn, e.g. in the IFLA_LINK_NETNSID of a veth whose peer
+// lives in another namespace, or to operate on links of a peer namespace
+// with LinkAttributes.TargetNetNSID.
+type NSIDMessage struct {
+	// Always set to AF_UNSPEC (0)
+	Family uint8
+
+	// Optional attributes which are appended when not nil.
+	Attributes *NSIDAttributes
+}
+
+// MarshalBinary marshals a NSIDMessage into a byte slice.
+func (m *NSIDMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofNSIDMsg)
+	b[0] = m.Family
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a NSIDMessage.
+func (m *NSIDMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < sizeofNSIDMsg {
+		return errInvalidNSIDMessage
+	}
+
+	m.Family = b[0]
+
+	if l > sizeofNSIDMsg {
+		m.Attributes = &NSIDAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[sizeofNSIDMsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*NSIDMessage) rtMessage() {}
+
+// NSIDAttributes contains all attributes for a network namespace ID.
+type NSIDAttributes struct {
+	// ID of the network namespace. The kernel reports
+	// unix.NETNSA_NSID_NOT_ASSIGNED for a namespace without an ID.
+	NSID *int32
+
+	// Network namespace the ID refers to. Only used in requests.
+	NetNS *NetNS
+
+	// ID of the network namespace in which the request is evaluated, instead
+	// of the namespace of the Conn.
+	TargetNSID *int32
+
+	// ID of the namespace of the Conn in the namespace given by TargetNSID,
+	// reported in replies to requests with a TargetNSID.
+	CurrentNSID *int32
+}
+
+func (a *NSIDAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NETNSA_NSID:
+			v := ad.Int32()
+			a.NSID = &v
+		case unix.NETNSA_TARGET_NSID:
+			v := ad.Int32()
+			a.TargetNSID = &v
+		case unix.NETNSA_CURRENT_NSID:
+			v := ad.Int32()
+			a.CurrentNSID = &v
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *NSIDAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.NSID != nil {
+		ae.Int32(unix.NETNSA_NSID, *a.NSID)
+	}
+
+	if a.NetNS != nil {
+		switch typ, v := a.NetNS.value(); typ {
+		case unix.IFLA_NET_NS_FD:
+			ae.Uint32(unix.NETNSA_FD, v)
+		case unix.IFLA_NET_NS_PID:
+			ae.Uint32(unix.NETNSA_PID, v)
+		}
+	}
+
+	if a.TargetNSID != nil {
+		ae.Int32(unix.NETNSA_TARGET_NSID, *a.TargetNSID)
+	}
+
+	return nil
+}
+
+// NSIDService is used to assign and retrieve the IDs of network namespaces.
+type NSIDService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a NSIDMessage slice
+func (n *NSIDService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]NSIDMessage, error) {
+	msgs, err := n.c.ExecuteContext(ctx, m, family, flags)
+
+	nsids := make([]NSIDMessage, len(msgs))
+	for i, msg := range msgs {
+		if nm, ok := msg.(*NSIDMessage); ok {
+			nsids[i] = *nm
+		}
+	}
+
+	return nsids, err
+}
+
+// Add assigns nsid to the network namespace ns and returns the assigned ID.
+// If nsid is unix.NETNSA_NSID_NOT_ASSIGNED, the kernel allocates a free ID.
+// A namespace keeps its ID once assigned, adding another one fails with
+// EEXIST.
+func (n *NSIDService) Add(ns *NetNS, nsid int32) (int32, error) {
+	return n.AddContext(context.Background(), ns, nsid)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (n *NSIDService) AddContext(ctx context.Context, ns *NetNS, nsid int32) (int32, error) {
+	req := &NSIDMessage{
+		Attributes: &NSIDAttributes{
+			NSID:  &nsid,
+			NetNS: ns,
+		},
+	}
+
+	flags := netlink.Request | netlink.Acknowledge
+	if _, err := n.c.ExecuteContext(ctx, req, unix.RTM_NEWNSID, flags); err != nil {
+		return 0, err
+	}
+
+	if nsid >= 0 {
+		return nsid, nil
+	}
+
+	// The kernel does not report an allocated ID in its acknowledgement.
+	return n.GetContext(ctx, ns)
+}
+
+// Get retrieves the ID of the network namespace ns. It returns
+// unix.NETNSA_NSID_NOT_ASSIGNED if ns has no ID.
+func (n *NSIDService) Get(ns *NetNS) (int32, error) {
+	return n.GetContext(context.Background(), ns)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (n *NSIDService) GetContext(ctx context.Context, ns *NetNS) (int32, error) {
+	req := &NSIDMessage{
+		Attributes: &NSIDAttributes{
+			NetNS: ns,
+		},
+	}
+
+	flags := netlink.Request
+	nsids, err := n.execute(ctx, req, unix.RTM_GETNSID, flags)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(nsids) != 1 {
+		return 0, fmt.Errorf("too many/little matches, expected 1, actual %d", len(nsids))
+	}
+	if a := nsids[0].Attributes; a == nil || a.NSID == nil {
+		return 0, errInvalidNSIDMessage
+	}
+
+	return *nsids[0].Attributes.NSID, nil
+}
+
+// List retrieves the IDs assigned to network namespaces.
+func (n *NSIDService) List() ([]NSIDMessage, error) {
+	return n.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (n *NSIDService) ListContext(ctx context.Context) ([]NSIDMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return n.execute(ctx, &NSIDMessage{}, unix.RTM_GETNSID, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the IDs
+// one at a time. See Conn.ExecuteSeq.
+func (n *NSIDService) ListSeq() iter.Seq2[NSIDMessage, error] {
+	return n.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (n *NSIDService) ListSeqContext(ctx context.Context) iter.Seq2[NSIDMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[NSIDMessage](ctx, n.c, &NSIDMessage{}, unix.RTM_GETNSID, flags)
+}
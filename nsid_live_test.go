@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestNSID(t *testing.T) {
+	conn, err := DialNetNS(NetNSForFD(uint32(testutils.NetNS(t))), nil)
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	peer := NetNSForFD(uint32(testutils.NetNS(t)))
+
+	nsid, err := conn.NSID.Get(peer)
+	if err != nil {
+		t.Fatalf("failed to get nsid: %v", err)
+	}
+	if nsid != unix.NETNSA_NSID_NOT_ASSIGNED {
+		t.Fatalf("expected no nsid for a new namespace, got: %d", nsid)
+	}
+
+	nsid, err = conn.NSID.Add(peer, unix.NETNSA_NSID_NOT_ASSIGNED)
+	if err != nil {
+		t.Fatalf("failed to allocate nsid: %v", err)
+	}
+	if nsid < 0 {
+		t.Fatalf("unexpected allocated nsid: %d", nsid)
+	}
+
+	if _, err := conn.NSID.Add(peer, nsid+1); !errors.Is(err, syscall.EEXIST) {
+		t.Fatalf("expected EEXIST when assigning a second nsid, got: %v", err)
+	}
+
+	got, err := conn.NSID.Get(peer)
+	if err != nil {
+		t.Fatalf("failed to get nsid: %v", err)
+	}
+	if got != nsid {
+		t.Fatalf("unexpected nsid: want %d, got %d", nsid, got)
+	}
+
+	nsids, err := conn.NSID.List()
+	if err != nil {
+		t.Fatalf("failed to list nsids: %v", err)
+	}
+	if len(nsids) != 1 || nsids[0].Attributes == nil || nsids[0].Attributes.NSID == nil || *nsids[0].Attributes.NSID != nsid {
+		t.Fatalf("unexpected nsids: %+v", nsids)
+	}
+
+	// The links of the peer namespace are available through its nsid.
+	links, err := conn.Link.ListMatch(&LinkMessage{Attributes: &LinkAttributes{TargetNetNSID: &nsid}})
+	if err != nil {
+		t.Fatalf("failed to list links of peer namespace: %v", err)
+	}
+	if len(links) != 1 || links[0].Attributes.Name != "lo" {
+		t.Fatalf("unexpected links: %+v", links)
+	}
+	if a := links[0].Attributes; a.TargetNetNSID == nil || *a.TargetNetNSID != nsid {
+		t.Fatalf("expected the target nsid to be reported, got: %+v", a.TargetNetNSID)
+	}
+
+	// Links are created in the peer namespace as well.
+	err = conn.Link.New(&LinkMessage{
+		Attributes: &LinkAttributes{
+			Name:          "nsid0",
+			Info:          &LinkInfo{Kind: "veth"},
+			TargetNetNSID: &nsid,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create link in peer namespace: %v", err)
+	}
+
+	peerConn, err := DialNetNS(peer, nil)
+	if err != nil {
+		t.Fatalf("failed to dial peer namespace: %v", err)
+	}
+	defer peerConn.Close()
+
+	peerLinks, err := peerConn.Link.List()
+	if err != nil {
+		t.Fatalf("failed to list links of peer namespace: %v", err)
+	}
+	var found bool
+	for _, l := range peerLinks {
+		found = found || l.Attributes.Name == "nsid0"
+	}
+	if !found {
+		t.Fatalf("expected link in peer namespace, got: %+v", peerLinks)
+	}
+}
@@ -0,0 +1,132 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNSIDMessage(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m            Message
+		b            []byte
+		marshalErr   error
+		unmarshalErr error
+	}{
+		"empty": {
+			m: &NSIDMessage{},
+			b: []byte{0x00, 0x00, 0x00, 0x00},
+		},
+		"not assigned": {
+			m: &NSIDMessage{
+				Attributes: &NSIDAttributes{
+					NSID: int32Ptr(-1),
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		"target": {
+			m: &NSIDMessage{
+				Attributes: &NSIDAttributes{
+					NSID:       int32Ptr(5),
+					TargetNSID: int32Ptr(2),
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x05, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x04, 0x00, 0x02, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b []byte
+			t.Run("marshal", func(t *testing.T) {
+				var marshalErr error
+				b, marshalErr = tt.m.MarshalBinary()
+
+				if !errors.Is(marshalErr, tt.marshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.marshalErr, marshalErr)
+				}
+			})
+
+			t.Run("compare bytes", func(t *testing.T) {
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+
+			m := &NSIDMessage{}
+			t.Run("unmarshal", func(t *testing.T) {
+				unmarshalErr := (m).UnmarshalBinary(b)
+				if !errors.Is(unmarshalErr, tt.unmarshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.unmarshalErr, unmarshalErr)
+				}
+			})
+
+			t.Run("compare messages", func(t *testing.T) {
+				if !reflect.DeepEqual(tt.m, m) {
+					t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", tt.m, m)
+				}
+			})
+		})
+	}
+
+	t.Run("netns", func(t *testing.T) {
+		for _, tt := range []struct {
+			ns *NetNS
+			b  []byte
+		}{
+			{
+				ns: NetNSForFD(3),
+				b:  []byte{0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x03, 0x00, 0x03, 0x00, 0x00, 0x00},
+			},
+			{
+				ns: NetNSForPID(1),
+				b:  []byte{0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00},
+			},
+		} {
+			b, err := (&NSIDMessage{Attributes: &NSIDAttributes{NetNS: tt.ns}}).MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+			}
+		}
+	})
+
+	t.Run("current nsid", func(t *testing.T) {
+		m := &NSIDMessage{}
+		err := (m).UnmarshalBinary([]byte{
+			0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x05, 0x00, 0x00, 0x00,
+			0x08, 0x00, 0x05, 0x00, 0x03, 0x00, 0x00, 0x00,
+		})
+		if err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+
+		want := &NSIDMessage{Attributes: &NSIDAttributes{NSID: int32Ptr(5), CurrentNSID: int32Ptr(3)}}
+		if !reflect.DeepEqual(want, m) {
+			t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", want, m)
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &NSIDMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x00, 0x01})
+		if !errors.Is(unmarshalErr, errInvalidNSIDMessage) {
+			t.Fatalf("Expected 'errInvalidNSIDMessage' but got '%v'", unmarshalErr)
+		}
+	})
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
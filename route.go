@@ -1,9 +1,11 @@
 package rtnetlink
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"iter"
 	"net"
 	"unsafe"
 
@@ -107,8 +109,8 @@ type RouteService struct {
 	c *Conn
 }
 
-func (r *RouteService) execute(m Message, family uint16, flags netlink.HeaderFlags) ([]RouteMessage, error) {
-	msgs, err := r.c.Execute(m, family, flags)
+func (r *RouteService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]RouteMessage, error) {
+	msgs, err := r.c.ExecuteContext(ctx, m, family, flags)
 
 	routes := make([]RouteMessage, len(msgs))
 	for i := range msgs {
@@ -120,44 +122,179 @@ func (r *RouteService) execute(m Message, family uint16, flags netlink.HeaderFla
 
 // Add new route
 func (r *RouteService) Add(req *RouteMessage) error {
+	return r.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (r *RouteService) AddContext(ctx context.Context, req *RouteMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
-	_, err := r.c.Execute(req, unix.RTM_NEWROUTE, flags)
+	_, err := r.c.ExecuteContext(ctx, req, unix.RTM_NEWROUTE, flags)
 
 	return err
 }
 
 // Replace or add new route
 func (r *RouteService) Replace(req *RouteMessage) error {
+	return r.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (r *RouteService) ReplaceContext(ctx context.Context, req *RouteMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
-	_, err := r.c.Execute(req, unix.RTM_NEWROUTE, flags)
+	_, err := r.c.ExecuteContext(ctx, req, unix.RTM_NEWROUTE, flags)
 
 	return err
 }
 
 // Delete existing route
 func (r *RouteService) Delete(req *RouteMessage) error {
+	return r.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (r *RouteService) DeleteContext(ctx context.Context, req *RouteMessage) error {
 	flags := netlink.Request | netlink.Acknowledge
-	_, err := r.c.Execute(req, unix.RTM_DELROUTE, flags)
+	_, err := r.c.ExecuteContext(ctx, req, unix.RTM_DELROUTE, flags)
 
 	return err
 }
 
 // Get Route(s).
 func (r *RouteService) Get(req *RouteMessage) ([]RouteMessage, error) {
+	return r.GetContext(context.Background(), req)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (r *RouteService) GetContext(ctx context.Context, req *RouteMessage) ([]RouteMessage, error) {
 	flags := netlink.Request
-	return r.execute(req, unix.RTM_GETROUTE, flags)
+	return r.execute(ctx, req, unix.RTM_GETROUTE, flags)
 }
 
 // List all routes
 func (r *RouteService) List() ([]RouteMessage, error) {
-	return r.ListMatch(&RouteMessage{})
+	return r.ListContext(context.Background())
 }
 
-// List matching Route(s). For attributes to be included as part of the match filter the
-// netlink connection must be in strict mode.
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (r *RouteService) ListContext(ctx context.Context) ([]RouteMessage, error) {
+	return r.ListMatchContext(ctx, &RouteMessage{})
+}
+
+// ListMatch retrieves the routes matching req. The Family, Table, Protocol
+// and Type of req and the Table and OutIface of its attributes are used as
+// filter when non-zero, all other fields are ignored.
+//
+// The kernel filters the dump if the Conn has strict checking enabled, see
+// netlink.Config.Strict, which requires Linux 4.20 or later. Otherwise the
+// routes are filtered after they have been received.
 func (r *RouteService) ListMatch(req *RouteMessage) ([]RouteMessage, error) {
+	return r.ListMatchContext(context.Background(), req)
+}
+
+// ListMatchContext is like ListMatch, but takes a context. See Conn.ExecuteContext.
+func (r *RouteService) ListMatchContext(ctx context.Context, req *RouteMessage) ([]RouteMessage, error) {
+	filter := routeDumpFilter(req)
+
 	flags := netlink.Request | netlink.Dump
-	return r.execute(req, unix.RTM_GETROUTE, flags)
+	routes, err := r.execute(ctx, filter, unix.RTM_GETROUTE, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := routes[:0]
+	for _, rt := range routes {
+		if rt.matches(filter) {
+			matched = append(matched, rt)
+		}
+	}
+
+	return matched, nil
+}
+
+// ListSeq is like List, but returns an iterator which yields the routes as
+// they are received. See Conn.ExecuteSeq.
+func (r *RouteService) ListSeq() iter.Seq2[RouteMessage, error] {
+	return r.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (r *RouteService) ListSeqContext(ctx context.Context) iter.Seq2[RouteMessage, error] {
+	return r.ListMatchSeqContext(ctx, &RouteMessage{})
+}
+
+// ListMatchSeq is like ListMatch, but returns an iterator which yields the
+// routes one at a time. See Conn.ExecuteSeq.
+func (r *RouteService) ListMatchSeq(req *RouteMessage) iter.Seq2[RouteMessage, error] {
+	return r.ListMatchSeqContext(context.Background(), req)
+}
+
+// ListMatchSeqContext is like ListMatchSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (r *RouteService) ListMatchSeqContext(ctx context.Context, req *RouteMessage) iter.Seq2[RouteMessage, error] {
+	filter := routeDumpFilter(req)
+
+	flags := netlink.Request | netlink.Dump
+	routes := executeSeq[RouteMessage](ctx, r.c, filter, unix.RTM_GETROUTE, flags)
+
+	return filterSeq(routes, func(rt *RouteMessage) bool {
+		return rt.matches(filter)
+	})
+}
+
+// routeDumpFilter returns the dump request for the routes matching req.
+func routeDumpFilter(req *RouteMessage) *RouteMessage {
+	// Strict checking rejects any other field or attribute in a dump request.
+	return &RouteMessage{
+		Family:   req.Family,
+		Table:    req.Table,
+		Protocol: req.Protocol,
+		Type:     req.Type,
+		Attributes: RouteAttributes{
+			Table:    req.Attributes.Table,
+			OutIface: req.Attributes.OutIface,
+		},
+	}
+}
+
+// matches reports whether m matches the non-zero fields of the dump filter f.
+func (m *RouteMessage) matches(f *RouteMessage) bool {
+	switch {
+	case f.Family != 0 && m.Family != f.Family:
+		return false
+	case f.Protocol != 0 && m.Protocol != f.Protocol:
+		return false
+	case f.Type != 0 && m.Type != f.Type:
+		return false
+	case f.Attributes.OutIface != 0 && !m.usesIface(f.Attributes.OutIface):
+		return false
+	}
+
+	// Tables beyond 255 are only carried by RTA_TABLE.
+	if table := f.table(); table != 0 && m.table() != table {
+		return false
+	}
+
+	return true
+}
+
+// usesIface reports whether the route or one of its nexthops uses the
+// interface with the given index.
+func (m *RouteMessage) usesIface(index uint32) bool {
+	if m.Attributes.OutIface == index {
+		return true
+	}
+	for _, nh := range m.Attributes.Multipath {
+		if nh.Hop.IfIndex == index {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *RouteMessage) table() uint32 {
+	if m.Attributes.Table != 0 {
+		return m.Attributes.Table
+	}
+	return uint32(m.Table)
 }
 
 type RouteAttributes struct {
@@ -173,6 +310,7 @@ type RouteAttributes struct {
 	Expires   *uint32
 	Metrics   *RouteMetrics
 	Multipath []NextHop
+	NhID      uint32 // ID of a nexthop object (RTA_NH_ID), see NexthopService
 }
 
 // RouteVia is the RTA_VIA attribute: a next-hop whose address family differs from the route's
@@ -236,6 +374,8 @@ func (a *RouteAttributes) decode(ad *netlink.AttributeDecoder) error {
 		case unix.RTA_PREF:
 			pref := ad.Uint8()
 			a.Pref = &pref
+		case unix.RTA_NH_ID:
+			a.NhID = ad.Uint32()
 		}
 	}
 
@@ -291,6 +431,10 @@ func (a *RouteAttributes) encode(ae *netlink.AttributeEncoder) error {
 		ae.Do(unix.RTA_MULTIPATH, a.encodeMultipath)
 	}
 
+	if a.NhID != 0 {
+		ae.Uint32(unix.RTA_NH_ID, a.NhID)
+	}
+
 	return nil
 }
 
@@ -330,6 +330,18 @@ func TestRouteMessageMarshalRoundTrip(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "nexthop object",
+			m: &RouteMessage{
+				Family:    unix.AF_INET,
+				DstLength: 24,
+				Type:      unix.RTN_UNICAST,
+				Attributes: RouteAttributes{
+					Dst:  net.IPv4(10, 0, 0, 0).To4(),
+					NhID: 10,
+				},
+			},
+		},
 		{
 			name: "multipath RTA_VIA, IPv4 dst via IPv6 next-hops (ECMP)",
 			m: &RouteMessage{
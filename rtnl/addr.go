@@ -78,28 +78,21 @@ func (c *Conn) AddrDel(ifc *net.Interface, addr *net.IPNet) error {
 //
 //	conn.Addrs(nil, 0)
 func (c *Conn) Addrs(ifc *net.Interface, family int) (out []*net.IPNet, err error) {
-	rx, err := c.Conn.Address.List()
+	req := &rtnetlink.AddressMessage{Family: uint8(family)}
+	if ifc != nil {
+		req.Index = uint32(ifc.Index)
+	}
+	rx, err := c.Conn.Address.ListMatch(req)
 	if err != nil {
 		return nil, err
 	}
-	match := func(v *rtnetlink.AddressMessage, ifc *net.Interface, family int) bool {
-		if ifc != nil && v.Index != uint32(ifc.Index) {
-			return false
-		}
-		if family != 0 && v.Family != uint8(family) {
-			return false
-		}
-		return true
-	}
 	for _, m := range rx {
-		if match(&m, ifc, family) {
-			bitlen := 8 * len(m.Attributes.Address)
-			a := &net.IPNet{
-				IP:   m.Attributes.Address,
-				Mask: net.CIDRMask(int(m.PrefixLength), bitlen),
-			}
-			out = append(out, a)
+		bitlen := 8 * len(m.Attributes.Address)
+		a := &net.IPNet{
+			IP:   m.Attributes.Address,
+			Mask: net.CIDRMask(int(m.PrefixLength), bitlen),
 		}
+		out = append(out, a)
 	}
 	return
 }
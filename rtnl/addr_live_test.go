@@ -8,8 +8,6 @@ import (
 	"net"
 	"syscall"
 	"testing"
-
-	"github.com/mdlayher/netlink"
 )
 
 var errNoLoopback = errors.New("no loopback interface")
@@ -102,7 +100,7 @@ func TestLiveAddrAddDel(t *testing.T) {
 
 	if err := c.AddrAdd(lo, testip); err != nil {
 		// requires specific privilege - skip the test if can't do
-		if v, ok := err.(*netlink.OpError); ok && v.Err == syscall.EPERM {
+		if errors.Is(err, syscall.EPERM) {
 			t.Skip("AddrAdd: ", err)
 		}
 		t.Fatal("AddrAdd:", err)
@@ -19,24 +19,16 @@ type Neigh struct {
 
 // Neighbours lists entries from the neighbor table (e.g. the ARP table).
 func (c *Conn) Neighbours(ifc *net.Interface, family int) (r []*Neigh, err error) {
-	rx, err := c.Conn.Neigh.List()
+	req := &rtnetlink.NeighMessage{Family: uint16(family)}
+	if ifc != nil {
+		req.Index = uint32(ifc.Index)
+	}
+	rx, err := c.Conn.Neigh.ListMatch(req)
 	if err != nil {
 		return nil, err
 	}
-	match := func(v *rtnetlink.NeighMessage, ifc *net.Interface, family int) bool {
-		if ifc != nil && v.Index != uint32(ifc.Index) {
-			return false
-		}
-		if family != 0 && v.Family != uint16(family) {
-			return false
-		}
-		return true
-	}
 	ifcache := map[int]*net.Interface{}
 	for _, m := range rx {
-		if !match(&m, ifc, family) {
-			continue
-		}
 		ifindex := int(m.Index)
 		iface, ok := ifcache[ifindex]
 		if !ok {
@@ -2,8 +2,10 @@ package rtnetlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
+	"iter"
 	"net"
 
 	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
@@ -114,8 +116,8 @@ type RuleService struct {
 	c *Conn
 }
 
-func (r *RuleService) execute(m Message, family uint16, flags netlink.HeaderFlags) ([]RuleMessage, error) {
-	msgs, err := r.c.Execute(m, family, flags)
+func (r *RuleService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]RuleMessage, error) {
+	msgs, err := r.c.ExecuteContext(ctx, m, family, flags)
 
 	rules := make([]RuleMessage, len(msgs))
 	for i := range msgs {
@@ -127,38 +129,147 @@ func (r *RuleService) execute(m Message, family uint16, flags netlink.HeaderFlag
 
 // Add new rule
 func (r *RuleService) Add(req *RuleMessage) error {
+	return r.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (r *RuleService) AddContext(ctx context.Context, req *RuleMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
-	_, err := r.c.Execute(req, unix.RTM_NEWRULE, flags)
+	_, err := r.c.ExecuteContext(ctx, req, unix.RTM_NEWRULE, flags)
 
 	return err
 }
 
 // Replace or add new rule
 func (r *RuleService) Replace(req *RuleMessage) error {
+	return r.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (r *RuleService) ReplaceContext(ctx context.Context, req *RuleMessage) error {
 	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
-	_, err := r.c.Execute(req, unix.RTM_NEWRULE, flags)
+	_, err := r.c.ExecuteContext(ctx, req, unix.RTM_NEWRULE, flags)
 
 	return err
 }
 
 // Delete existing rule
 func (r *RuleService) Delete(req *RuleMessage) error {
+	return r.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (r *RuleService) DeleteContext(ctx context.Context, req *RuleMessage) error {
 	flags := netlink.Request | netlink.Acknowledge
-	_, err := r.c.Execute(req, unix.RTM_DELRULE, flags)
+	_, err := r.c.ExecuteContext(ctx, req, unix.RTM_DELRULE, flags)
 
 	return err
 }
 
 // Get Rule(s)
 func (r *RuleService) Get(req *RuleMessage) ([]RuleMessage, error) {
+	return r.GetContext(context.Background(), req)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (r *RuleService) GetContext(ctx context.Context, req *RuleMessage) ([]RuleMessage, error) {
 	flags := netlink.Request | netlink.DumpFiltered
-	return r.execute(req, unix.RTM_GETRULE, flags)
+	return r.execute(ctx, req, unix.RTM_GETRULE, flags)
 }
 
 // List all rules
 func (r *RuleService) List() ([]RuleMessage, error) {
+	return r.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (r *RuleService) ListContext(ctx context.Context) ([]RuleMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return r.execute(ctx, &RuleMessage{}, unix.RTM_GETRULE, flags)
+}
+
+// ListMatch retrieves the rules matching req. The Family and Table of req and
+// the Table and Protocol of its attributes are used as filter when set, all
+// other fields are ignored.
+//
+// The kernel only filters rule dumps by family, the table and protocol are
+// matched after the rules have been received.
+func (r *RuleService) ListMatch(req *RuleMessage) ([]RuleMessage, error) {
+	return r.ListMatchContext(context.Background(), req)
+}
+
+// ListMatchContext is like ListMatch, but takes a context. See Conn.ExecuteContext.
+func (r *RuleService) ListMatchContext(ctx context.Context, req *RuleMessage) ([]RuleMessage, error) {
+	// Strict checking rejects any other field or attribute in a dump request.
 	flags := netlink.Request | netlink.Dump
-	return r.execute(&RuleMessage{}, unix.RTM_GETRULE, flags)
+	rules, err := r.execute(ctx, &RuleMessage{Family: req.Family}, unix.RTM_GETRULE, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := rules[:0]
+	for _, rule := range rules {
+		if rule.matches(req) {
+			matched = append(matched, rule)
+		}
+	}
+
+	return matched, nil
+}
+
+// ListSeq is like List, but returns an iterator which yields the rules
+// one at a time. See Conn.ExecuteSeq.
+func (r *RuleService) ListSeq() iter.Seq2[RuleMessage, error] {
+	return r.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (r *RuleService) ListSeqContext(ctx context.Context) iter.Seq2[RuleMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[RuleMessage](ctx, r.c, &RuleMessage{}, unix.RTM_GETRULE, flags)
+}
+
+// ListMatchSeq is like ListMatch, but returns an iterator which yields the
+// rules one at a time. See Conn.ExecuteSeq.
+func (r *RuleService) ListMatchSeq(req *RuleMessage) iter.Seq2[RuleMessage, error] {
+	return r.ListMatchSeqContext(context.Background(), req)
+}
+
+// ListMatchSeqContext is like ListMatchSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (r *RuleService) ListMatchSeqContext(ctx context.Context, req *RuleMessage) iter.Seq2[RuleMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	rules := executeSeq[RuleMessage](ctx, r.c, &RuleMessage{Family: req.Family}, unix.RTM_GETRULE, flags)
+
+	return filterSeq(rules, func(rule *RuleMessage) bool {
+		return rule.matches(req)
+	})
+}
+
+// matches reports whether m matches the family, table and protocol of f.
+func (m *RuleMessage) matches(f *RuleMessage) bool {
+	if f.Family != 0 && m.Family != f.Family {
+		return false
+	}
+
+	// Tables beyond 255 are only carried by FRA_TABLE.
+	if table := f.table(); table != 0 && m.table() != table {
+		return false
+	}
+
+	if f.Attributes != nil && f.Attributes.Protocol != nil {
+		if m.Attributes == nil || m.Attributes.Protocol == nil || *m.Attributes.Protocol != *f.Attributes.Protocol {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *RuleMessage) table() uint32 {
+	if m.Attributes != nil && m.Attributes.Table != nil {
+		return *m.Attributes.Table
+	}
+	return uint32(m.Table)
 }
 
 // RuleAttributes contains all attributes for a rule.
@@ -0,0 +1,140 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"os"
+
+	"github.com/mdlayher/netlink"
+)
+
+// ExecuteSeq sends a single Message to netlink using Send and returns an
+// iterator over the replies, which are unpacked one at a time while iterating
+// instead of being collected first. Replies other than route messages are
+// skipped. Iterating stops after the first error, which is yielded with a nil
+// Message.
+//
+// This makes it possible to process a large dump, such as a full routing
+// table, without decoding all of it up front.
+//
+// ExecuteSeq locks the Conn until the iteration has finished, so that no
+// other request can be sent before the reply has been received completely.
+// The loop body must therefore not use the same Conn.
+func (c *Conn) ExecuteSeq(m Message, family uint16, flags netlink.HeaderFlags) iter.Seq2[Message, error] {
+	return c.ExecuteSeqContext(context.Background(), m, family, flags)
+}
+
+// ExecuteSeqContext is like ExecuteSeq, but the request is canceled when ctx
+// is done, in which case ctx.Err() is yielded. See Conn.ExecuteContext.
+func (c *Conn) ExecuteSeqContext(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		nm, err := packMessage(m, family, flags)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		stop := func() {}
+		if ctx.Done() != nil {
+			stop = c.interruptOnDone(ctx)
+		}
+
+		msgs, err := c.receiveSeq(nm)
+		stop()
+
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) && ctx.Err() != nil {
+				c.drain()
+				err = ctx.Err()
+			}
+			yield(nil, err)
+			return
+		}
+
+		for _, msg := range msgs {
+			rtmsg, err := unpackMessage(msg)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if rtmsg == nil {
+				continue
+			}
+			if !yield(rtmsg, nil) {
+				return
+			}
+		}
+	}
+}
+
+// receiveSeq sends the request nm and receives its reply, which is validated
+// but not unpacked yet.
+func (c *Conn) receiveSeq(nm netlink.Message) ([]netlink.Message, error) {
+	req, err := c.c.Send(nm)
+	if err != nil {
+		return nil, newOpError(nm, err)
+	}
+
+	msgs, err := c.c.Receive()
+	if err == nil {
+		err = netlink.Validate(req, msgs)
+	}
+	if err != nil {
+		return nil, newOpError(req, err)
+	}
+
+	if c.failInterrupted {
+		for _, m := range msgs {
+			if m.Header.Flags&netlink.DumpInterrupted != 0 {
+				return nil, errDumpInterrupted
+			}
+		}
+	}
+
+	return msgs, nil
+}
+
+// executeSeq is like Conn.ExecuteSeqContext, but yields the replies of type
+// T only, as values.
+func executeSeq[T any, PT interface {
+	*T
+	Message
+}](ctx context.Context, c *Conn, m Message, family uint16, flags netlink.HeaderFlags) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for msg, err := range c.ExecuteSeqContext(ctx, m, family, flags) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			if pm, ok := msg.(PT); ok && !yield(*pm, nil) {
+				return
+			}
+		}
+	}
+}
+
+// filterSeq returns an iterator over the values of seq for which keep
+// reports true, and all errors.
+func filterSeq[T any](seq iter.Seq2[T, error], keep func(*T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v, err := range seq {
+			if err == nil && !keep(&v) {
+				continue
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
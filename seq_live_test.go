@@ -0,0 +1,107 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestRouteListSeq(t *testing.T) {
+	// Enough routes for the dump to span several datagrams.
+	const count = 1000
+
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Link.Set(&LinkMessage{Index: lo, Flags: unix.IFF_UP, Change: unix.IFF_UP}); err != nil {
+		t.Fatalf("failed to set lo up: %v", err)
+	}
+
+	for i := 0; i < count; i++ {
+		err := conn.Route.Add(&RouteMessage{
+			Family:    unix.AF_INET,
+			DstLength: 32,
+			Table:     unix.RT_TABLE_MAIN,
+			Protocol:  unix.RTPROT_STATIC,
+			Scope:     unix.RT_SCOPE_LINK,
+			Type:      unix.RTN_UNICAST,
+			Attributes: RouteAttributes{
+				Dst:      net.IPv4(10, 0, byte(i>>8), byte(i)).To4(),
+				OutIface: lo,
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to add route %d: %v", i, err)
+		}
+	}
+
+	routes, err := conn.Route.List()
+	if err != nil {
+		t.Fatalf("failed to list routes: %v", err)
+	}
+
+	var n int
+	for r, err := range conn.Route.ListSeq() {
+		if err != nil {
+			t.Fatalf("failed to list routes: %v", err)
+		}
+		if n >= len(routes) {
+			t.Fatalf("unexpected extra route: %v", r.Attributes.Dst)
+		}
+		if !r.Attributes.Dst.Equal(routes[n].Attributes.Dst) {
+			t.Fatalf("unexpected route %d: want %v, got %v", n, routes[n].Attributes.Dst, r.Attributes.Dst)
+		}
+		n++
+	}
+	if want, got := len(routes), n; want != got {
+		t.Fatalf("unexpected number of routes: want %d, got %d", want, got)
+	}
+
+	t.Run("break", func(t *testing.T) {
+		for _, err := range conn.Route.ListSeq() {
+			if err != nil {
+				t.Fatalf("failed to list routes: %v", err)
+			}
+			break
+		}
+
+		// The Conn must be usable for the next request.
+		routes, err := conn.Route.List()
+		if err != nil {
+			t.Fatalf("failed to list routes after break: %v", err)
+		}
+		if want, got := n, len(routes); want != got {
+			t.Fatalf("unexpected number of routes after break: want %d, got %d", want, got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := &LinkMessage{
+			Index:      lo,
+			Attributes: &LinkAttributes{Name: "lo"},
+		}
+		flags := netlink.Request | netlink.Create | netlink.Excl | netlink.Acknowledge
+
+		var n int
+		for _, err := range conn.ExecuteSeq(req, unix.RTM_NEWLINK, flags) {
+			n++
+			if !errors.Is(err, syscall.EEXIST) {
+				t.Fatalf("expected EEXIST, got: %v", err)
+			}
+		}
+		if n != 1 {
+			t.Fatalf("expected a single error, got %d values", n)
+		}
+	})
+}
@@ -0,0 +1,193 @@
+//go:build linux
+// +build linux
+
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestConnExecuteSeq(t *testing.T) {
+	skipBigEndian(t)
+
+	receive := []netlink.Message{
+		{
+			Header: netlink.Header{Type: unix.RTM_NEWLINK},
+			Data:   mustMarshal(&LinkMessage{Index: 1}),
+		},
+		{
+			Header: netlink.Header{Type: unix.RTM_NEWLINK},
+			Data: mustMarshal(&LinkMessage{
+				Index:      2,
+				Attributes: &LinkAttributes{Master: uint32Ptr(1)},
+			}),
+		},
+		{
+			// Replies other than route messages are skipped.
+			Header: netlink.Header{Type: netlink.Error},
+			Data:   make([]byte, 4),
+		},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.receive = receive
+
+		var msgs []Message
+		for m, err := range c.ExecuteSeq(&LinkMessage{}, unix.RTM_GETLINK, netlink.Request|netlink.Dump) {
+			if err != nil {
+				t.Fatalf("failed to execute: %v", err)
+			}
+			msgs = append(msgs, m)
+		}
+
+		want := []Message{
+			&LinkMessage{Index: 1},
+			&LinkMessage{Index: 2, Attributes: &LinkAttributes{Master: uint32Ptr(1)}},
+		}
+		if !reflect.DeepEqual(want, msgs) {
+			t.Fatalf("unexpected replies:\n- want: %#v\n-  got: %#v", want, msgs)
+		}
+		if want, got := netlink.HeaderType(unix.RTM_GETLINK), tc.send.Header.Type; want != got {
+			t.Fatalf("unexpected request type: want %d, got %d", want, got)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.receive = receive
+
+		var links []LinkMessage
+		for l, err := range c.Link.ListSeq() {
+			if err != nil {
+				t.Fatalf("failed to list links: %v", err)
+			}
+			links = append(links, l)
+			break
+		}
+
+		if want := []LinkMessage{{Index: 1}}; !reflect.DeepEqual(want, links) {
+			t.Fatalf("unexpected links:\n- want: %#v\n-  got: %#v", want, links)
+		}
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.receive = receive
+
+		for _, err := range c.Link.ListSeq() {
+			if err != nil {
+				t.Fatalf("failed to list links: %v", err)
+			}
+			if c.mu.TryLock() {
+				t.Fatal("Conn is not locked while iterating")
+			}
+		}
+
+		if !c.mu.TryLock() {
+			t.Fatal("Conn is still locked after iterating")
+		}
+	})
+
+	t.Run("match", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.receive = receive
+
+		var links []LinkMessage
+		req := &LinkMessage{Attributes: &LinkAttributes{Master: uint32Ptr(1)}}
+		for l, err := range c.Link.ListMatchSeq(req) {
+			if err != nil {
+				t.Fatalf("failed to list links: %v", err)
+			}
+			links = append(links, l)
+		}
+
+		want := []LinkMessage{{Index: 2, Attributes: &LinkAttributes{Master: uint32Ptr(1)}}}
+		if !reflect.DeepEqual(want, links) {
+			t.Fatalf("unexpected links:\n- want: %#v\n-  got: %#v", want, links)
+		}
+	})
+
+	t.Run("rule match", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.receive = []netlink.Message{
+			{
+				Header: netlink.Header{Type: unix.RTM_NEWRULE},
+				Data:   mustMarshal(&RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_LOCAL}),
+			},
+			{
+				Header: netlink.Header{Type: unix.RTM_NEWRULE},
+				Data:   mustMarshal(&RuleMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN}),
+			},
+		}
+
+		var rules []RuleMessage
+		for r, err := range c.Rule.ListMatchSeq(&RuleMessage{Table: unix.RT_TABLE_MAIN}) {
+			if err != nil {
+				t.Fatalf("failed to list rules: %v", err)
+			}
+			rules = append(rules, r)
+		}
+
+		want := []RuleMessage{{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN}}
+		if !reflect.DeepEqual(want, rules) {
+			t.Fatalf("unexpected rules:\n- want: %#v\n-  got: %#v", want, rules)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c, tc := testConn(t)
+		tc.err = unix.ENOBUFS
+
+		var n int
+		for _, err := range c.Link.ListSeq() {
+			n++
+			if !errors.Is(err, unix.ENOBUFS) {
+				t.Fatalf("expected ENOBUFS, got: %v", err)
+			}
+		}
+		if n != 1 {
+			t.Fatalf("expected a single error, got %d values", n)
+		}
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		c, tc := testConn(t)
+		c.failInterrupted = true
+		tc.receive = []netlink.Message{{
+			Header: netlink.Header{
+				Type:  unix.RTM_NEWLINK,
+				Flags: netlink.Multi | netlink.DumpInterrupted,
+			},
+			Data: mustMarshal(&LinkMessage{Index: 1}),
+		}}
+
+		for l, err := range c.Link.ListSeq() {
+			if !errors.Is(err, errDumpInterrupted) {
+				t.Fatalf("expected errDumpInterrupted, got link %#v and error %v", l, err)
+			}
+		}
+	})
+
+	t.Run("canceled before", func(t *testing.T) {
+		c, tc := testConn(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for _, err := range c.Link.ListSeqContext(ctx) {
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got: %v", err)
+			}
+		}
+		if !reflect.DeepEqual(netlink.Message{}, tc.send) {
+			t.Fatal("request was sent for a canceled context")
+		}
+	})
+}
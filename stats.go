@@ -0,0 +1,577 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// errInvalidStatsMessage is returned when a StatsMessage is malformed.
+var errInvalidStatsMessage = errors.New("rtnetlink StatsMessage is invalid or too short")
+
+// sizeofIfStatsMsg is the size of a struct if_stats_msg.
+const sizeofIfStatsMsg = 12
+
+// Filter bits of a StatsMessage, selecting the statistics to report.
+const (
+	StatsFilterLink64            uint32 = 1 << (unix.IFLA_STATS_LINK_64 - 1)
+	StatsFilterLinkXStats        uint32 = 1 << (unix.IFLA_STATS_LINK_XSTATS - 1)
+	StatsFilterLinkXStatsSlave   uint32 = 1 << (unix.IFLA_STATS_LINK_XSTATS_SLAVE - 1)
+	StatsFilterLinkOffloadXStats uint32 = 1 << (unix.IFLA_STATS_LINK_OFFLOAD_XSTATS - 1)
+	StatsFilterAFSpec            uint32 = 1 << (unix.IFLA_STATS_AF_SPEC - 1)
+)
+
+// Filter bits of StatsAttributes.OffloadXStatsFilter, selecting the offload
+// statistics to report.
+const (
+	OffloadXStatsFilterCPUHit      uint32 = 1 << (unix.IFLA_OFFLOAD_XSTATS_CPU_HIT - 1)
+	OffloadXStatsFilterHWStatsInfo uint32 = 1 << (unix.IFLA_OFFLOAD_XSTATS_HW_S_INFO - 1)
+	OffloadXStatsFilterL3Stats     uint32 = 1 << (unix.IFLA_OFFLOAD_XSTATS_L3_STATS - 1)
+)
+
+var _ Message = &StatsMessage{}
+
+// A StatsMessage is a route netlink link statistics message.
+type StatsMessage struct {
+	// Always set to AF_UNSPEC (0)
+	Family uint8
+
+	// Interface index
+	Index uint32
+
+	// Statistics to report (StatsFilter*). Must be set when retrieving
+	// statistics and zero when setting them.
+	FilterMask uint32
+
+	// Optional attributes which are appended when not nil.
+	Attributes *StatsAttributes
+}
+
+// MarshalBinary marshals a StatsMessage into a byte slice.
+func (m *StatsMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, sizeofIfStatsMsg)
+
+	b[0] = m.Family
+	// 3 bytes of padding
+	nativeEndian.PutUint32(b[4:8], m.Index)
+	nativeEndian.PutUint32(b[8:12], m.FilterMask)
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a StatsMessage.
+func (m *StatsMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < sizeofIfStatsMsg {
+		return errInvalidStatsMessage
+	}
+
+	m.Family = b[0]
+	m.Index = nativeEndian.Uint32(b[4:8])
+	m.FilterMask = nativeEndian.Uint32(b[8:12])
+
+	if l > sizeofIfStatsMsg {
+		m.Attributes = &StatsAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[sizeofIfStatsMsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*StatsMessage) rtMessage() {}
+
+// StatsAttributes contains all attributes for link statistics. Requests and
+// replies use different attributes.
+type StatsAttributes struct {
+	// Offload statistics to report (OffloadXStatsFilter*) when
+	// StatsFilterLinkOffloadXStats is requested. The kernel reports all but
+	// the L3 statistics by default. Only used in requests.
+	OffloadXStatsFilter *uint32
+
+	// Enables or disables the collection of L3 statistics by the hardware.
+	// Only used when setting statistics.
+	SetOffloadL3Stats *bool
+
+	Link64          *LinkStats64   // Statistics of the interface
+	LinkXStats      *LinkXStats    // Extended statistics of the interface
+	LinkXStatsSlave *LinkXStats    // Extended statistics of the interface as a port of its master
+	OffloadXStats   *OffloadXStats // Statistics of the traffic offloaded to hardware
+	AFSpec          *StatsAFSpec   // Address family specific statistics
+}
+
+func (a *StatsAttributes) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_STATS_LINK_64:
+			a.Link64 = &LinkStats64{}
+			ad.Do(a.Link64.unmarshalBinary)
+		case unix.IFLA_STATS_LINK_XSTATS:
+			a.LinkXStats = &LinkXStats{}
+			ad.Nested(a.LinkXStats.decode)
+		case unix.IFLA_STATS_LINK_XSTATS_SLAVE:
+			a.LinkXStatsSlave = &LinkXStats{}
+			ad.Nested(a.LinkXStatsSlave.decode)
+		case unix.IFLA_STATS_LINK_OFFLOAD_XSTATS:
+			a.OffloadXStats = &OffloadXStats{}
+			ad.Nested(a.OffloadXStats.decode)
+		case unix.IFLA_STATS_AF_SPEC:
+			a.AFSpec = &StatsAFSpec{}
+			ad.Nested(a.AFSpec.decode)
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *StatsAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.OffloadXStatsFilter != nil {
+		ae.Nested(unix.IFLA_STATS_GET_FILTERS, func(nae *netlink.AttributeEncoder) error {
+			nae.Uint32(unix.IFLA_STATS_LINK_OFFLOAD_XSTATS, *a.OffloadXStatsFilter)
+			return nil
+		})
+	}
+
+	if a.SetOffloadL3Stats != nil {
+		var v uint8
+		if *a.SetOffloadL3Stats {
+			v = 1
+		}
+		ae.Uint8(unix.IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS, v)
+	}
+
+	return nil
+}
+
+// LinkXStats contains the extended statistics of a bridge or bond, or of one
+// of their ports.
+type LinkXStats struct {
+	Bridge *BridgeXStats
+	Bond   *BondXStats
+}
+
+func (x *LinkXStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.LINK_XSTATS_TYPE_BRIDGE:
+			x.Bridge = &BridgeXStats{}
+			ad.Nested(x.Bridge.decode)
+		case unix.LINK_XSTATS_TYPE_BOND:
+			x.Bond = &BondXStats{}
+			ad.Nested(x.Bond.decode)
+		}
+	}
+
+	return nil
+}
+
+// BridgeXStats contains the extended statistics of a bridge or bridge port.
+type BridgeXStats struct {
+	Vlans []BridgeVlanXStats // Per-VLAN statistics, counted if enabled on the bridge
+	Mcast *BridgeMcastStats  // Multicast statistics, if enabled on the bridge
+	STP   *BridgeSTPXStats   // Spanning tree statistics of a port
+}
+
+func (x *BridgeXStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.BRIDGE_XSTATS_VLAN:
+			var v BridgeVlanXStats
+			ad.Do(v.unmarshalBinary)
+			x.Vlans = append(x.Vlans, v)
+		case unix.BRIDGE_XSTATS_MCAST:
+			x.Mcast = &BridgeMcastStats{}
+			ad.Do(x.Mcast.unmarshalBinary)
+		case unix.BRIDGE_XSTATS_STP:
+			x.STP = &BridgeSTPXStats{}
+			ad.Do(x.STP.unmarshalBinary)
+		}
+	}
+
+	return nil
+}
+
+// BridgeVlanXStats contains the statistics of a VLAN of a bridge or bridge
+// port (struct bridge_vlan_xstats).
+type BridgeVlanXStats struct {
+	RXBytes   uint64
+	RXPackets uint64
+	TXBytes   uint64
+	TXPackets uint64
+	Vid       uint16
+	Flags     uint16 // unix.BRIDGE_VLAN_INFO_*
+}
+
+func (s *BridgeVlanXStats) unmarshalBinary(b []byte) error {
+	if len(b) < 40 {
+		return fmt.Errorf("incorrect BridgeVlanXStats size, want: 40, got: %d", len(b))
+	}
+
+	s.RXBytes = nativeEndian.Uint64(b[0:8])
+	s.RXPackets = nativeEndian.Uint64(b[8:16])
+	s.TXBytes = nativeEndian.Uint64(b[16:24])
+	s.TXPackets = nativeEndian.Uint64(b[24:32])
+	s.Vid = nativeEndian.Uint16(b[32:34])
+	s.Flags = nativeEndian.Uint16(b[34:36])
+
+	return nil
+}
+
+// BridgeMcastStats contains the multicast statistics of a bridge or bridge
+// port (struct br_mcast_stats). The counters of received messages are at
+// index 0, those of transmitted messages at index 1.
+type BridgeMcastStats struct {
+	IGMPV1Queries   [2]uint64
+	IGMPV2Queries   [2]uint64
+	IGMPV3Queries   [2]uint64
+	IGMPLeaves      [2]uint64
+	IGMPV1Reports   [2]uint64
+	IGMPV2Reports   [2]uint64
+	IGMPV3Reports   [2]uint64
+	IGMPParseErrors uint64
+
+	MLDV1Queries   [2]uint64
+	MLDV2Queries   [2]uint64
+	MLDLeaves      [2]uint64
+	MLDV1Reports   [2]uint64
+	MLDV2Reports   [2]uint64
+	MLDParseErrors uint64
+
+	McastBytes   [2]uint64
+	McastPackets [2]uint64
+}
+
+func (s *BridgeMcastStats) unmarshalBinary(b []byte) error {
+	return unmarshalUint64s("BridgeMcastStats", b,
+		&s.IGMPV1Queries[0], &s.IGMPV1Queries[1],
+		&s.IGMPV2Queries[0], &s.IGMPV2Queries[1],
+		&s.IGMPV3Queries[0], &s.IGMPV3Queries[1],
+		&s.IGMPLeaves[0], &s.IGMPLeaves[1],
+		&s.IGMPV1Reports[0], &s.IGMPV1Reports[1],
+		&s.IGMPV2Reports[0], &s.IGMPV2Reports[1],
+		&s.IGMPV3Reports[0], &s.IGMPV3Reports[1],
+		&s.IGMPParseErrors,
+		&s.MLDV1Queries[0], &s.MLDV1Queries[1],
+		&s.MLDV2Queries[0], &s.MLDV2Queries[1],
+		&s.MLDLeaves[0], &s.MLDLeaves[1],
+		&s.MLDV1Reports[0], &s.MLDV1Reports[1],
+		&s.MLDV2Reports[0], &s.MLDV2Reports[1],
+		&s.MLDParseErrors,
+		&s.McastBytes[0], &s.McastBytes[1],
+		&s.McastPackets[0], &s.McastPackets[1],
+	)
+}
+
+// BridgeSTPXStats contains the spanning tree statistics of a bridge port
+// (struct bridge_stp_xstats).
+type BridgeSTPXStats struct {
+	TransitionBlk uint64 // transitions to the blocking state
+	TransitionFwd uint64 // transitions to the forwarding state
+	RXBPDU        uint64
+	TXBPDU        uint64
+	RXTCN         uint64
+	TXTCN         uint64
+}
+
+func (s *BridgeSTPXStats) unmarshalBinary(b []byte) error {
+	return unmarshalUint64s("BridgeSTPXStats", b,
+		&s.TransitionBlk, &s.TransitionFwd, &s.RXBPDU, &s.TXBPDU, &s.RXTCN, &s.TXTCN)
+}
+
+// BondXStats contains the extended statistics of a bond or bond port.
+type BondXStats struct {
+	LACP *BondLACPStats // 802.3ad statistics
+}
+
+func (x *BondXStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.BOND_XSTATS_3AD {
+			x.LACP = &BondLACPStats{}
+			ad.Nested(x.LACP.decode)
+		}
+	}
+
+	return nil
+}
+
+// BondLACPStats contains the 802.3ad statistics of a bond or bond port.
+type BondLACPStats struct {
+	LACPDURX        uint64
+	LACPDUTX        uint64
+	LACPDUUnknownRX uint64
+	LACPDUIllegalRX uint64
+	MarkerRX        uint64
+	MarkerTX        uint64
+	MarkerRespRX    uint64
+	MarkerRespTX    uint64
+	MarkerUnknownRX uint64
+}
+
+func (s *BondLACPStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.BOND_3AD_STAT_LACPDU_RX:
+			s.LACPDURX = ad.Uint64()
+		case unix.BOND_3AD_STAT_LACPDU_TX:
+			s.LACPDUTX = ad.Uint64()
+		case unix.BOND_3AD_STAT_LACPDU_UNKNOWN_RX:
+			s.LACPDUUnknownRX = ad.Uint64()
+		case unix.BOND_3AD_STAT_LACPDU_ILLEGAL_RX:
+			s.LACPDUIllegalRX = ad.Uint64()
+		case unix.BOND_3AD_STAT_MARKER_RX:
+			s.MarkerRX = ad.Uint64()
+		case unix.BOND_3AD_STAT_MARKER_TX:
+			s.MarkerTX = ad.Uint64()
+		case unix.BOND_3AD_STAT_MARKER_RESP_RX:
+			s.MarkerRespRX = ad.Uint64()
+		case unix.BOND_3AD_STAT_MARKER_RESP_TX:
+			s.MarkerRespTX = ad.Uint64()
+		case unix.BOND_3AD_STAT_MARKER_UNKNOWN_RX:
+			s.MarkerUnknownRX = ad.Uint64()
+		}
+	}
+
+	return nil
+}
+
+// OffloadXStats contains the statistics of the traffic of an interface which
+// is offloaded to hardware.
+type OffloadXStats struct {
+	// Statistics of the traffic which was handled by the CPU
+	CPUHit *LinkStats64
+
+	// State of the collection of L3 statistics by the hardware
+	L3StatsInfo *OffloadHWStatsInfo
+
+	// L3 statistics collected by the hardware, if in use
+	L3Stats *LinkHWStats64
+}
+
+func (x *OffloadXStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_OFFLOAD_XSTATS_CPU_HIT:
+			x.CPUHit = &LinkStats64{}
+			ad.Do(x.CPUHit.unmarshalBinary)
+		case unix.IFLA_OFFLOAD_XSTATS_HW_S_INFO:
+			ad.Nested(func(nad *netlink.AttributeDecoder) error {
+				for nad.Next() {
+					if nad.Type() == unix.IFLA_OFFLOAD_XSTATS_L3_STATS {
+						x.L3StatsInfo = &OffloadHWStatsInfo{}
+						nad.Nested(x.L3StatsInfo.decode)
+					}
+				}
+				return nil
+			})
+		case unix.IFLA_OFFLOAD_XSTATS_L3_STATS:
+			x.L3Stats = &LinkHWStats64{}
+			ad.Do(x.L3Stats.unmarshalBinary)
+		}
+	}
+
+	return nil
+}
+
+// OffloadHWStatsInfo contains the state of the collection of a type of
+// statistics by the hardware.
+type OffloadHWStatsInfo struct {
+	Request bool // Collection was requested
+	Used    bool // The hardware collects the statistics
+}
+
+func (i *OffloadHWStatsInfo) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_OFFLOAD_XSTATS_HW_S_INFO_REQUEST:
+			i.Request = ad.Uint8() != 0
+		case unix.IFLA_OFFLOAD_XSTATS_HW_S_INFO_USED:
+			i.Used = ad.Uint8() != 0
+		}
+	}
+
+	return nil
+}
+
+// LinkHWStats64 contains packet statistics collected by hardware
+// (struct rtnl_hw_stats64).
+type LinkHWStats64 struct {
+	RXPackets uint64
+	TXPackets uint64
+	RXBytes   uint64
+	TXBytes   uint64
+	RXErrors  uint64
+	TXErrors  uint64
+	RXDropped uint64
+	TXDropped uint64
+	Multicast uint64
+}
+
+func (s *LinkHWStats64) unmarshalBinary(b []byte) error {
+	return unmarshalUint64s("LinkHWStats64", b,
+		&s.RXPackets, &s.TXPackets, &s.RXBytes, &s.TXBytes, &s.RXErrors,
+		&s.TXErrors, &s.RXDropped, &s.TXDropped, &s.Multicast)
+}
+
+// StatsAFSpec contains the address family specific statistics of an
+// interface.
+type StatsAFSpec struct {
+	MPLS *MPLSLinkStats
+}
+
+func (s *StatsAFSpec) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == unix.AF_MPLS {
+			ad.Nested(func(nad *netlink.AttributeDecoder) error {
+				for nad.Next() {
+					if nad.Type() == unix.MPLS_STATS_LINK {
+						s.MPLS = &MPLSLinkStats{}
+						nad.Do(s.MPLS.unmarshalBinary)
+					}
+				}
+				return nil
+			})
+		}
+	}
+
+	return nil
+}
+
+// MPLSLinkStats contains the MPLS statistics of an interface
+// (struct mpls_link_stats).
+type MPLSLinkStats struct {
+	RXPackets uint64
+	TXPackets uint64
+	RXBytes   uint64
+	TXBytes   uint64
+	RXErrors  uint64
+	TXErrors  uint64
+	RXDropped uint64
+	TXDropped uint64
+	RXNoRoute uint64 // packets without a route
+}
+
+func (s *MPLSLinkStats) unmarshalBinary(b []byte) error {
+	return unmarshalUint64s("MPLSLinkStats", b,
+		&s.RXPackets, &s.TXPackets, &s.RXBytes, &s.TXBytes, &s.RXErrors,
+		&s.TXErrors, &s.RXDropped, &s.TXDropped, &s.RXNoRoute)
+}
+
+// unmarshalUint64s unmarshals a structure of native endian 64 bit values into
+// fields, in order. Values appended to the structure by newer kernels are
+// ignored.
+func unmarshalUint64s(name string, b []byte, fields ...*uint64) error {
+	if len(b) < 8*len(fields) {
+		return fmt.Errorf("incorrect %s size, want: %d, got: %d", name, 8*len(fields), len(b))
+	}
+
+	for i, f := range fields {
+		*f = nativeEndian.Uint64(b[i*8 : i*8+8])
+	}
+
+	return nil
+}
+
+// StatsService is used to retrieve and configure link statistics.
+type StatsService struct {
+	c *Conn
+}
+
+// execute executes the request and returns the messages as a StatsMessage slice
+func (s *StatsService) execute(ctx context.Context, m Message, family uint16, flags netlink.HeaderFlags) ([]StatsMessage, error) {
+	msgs, err := s.c.ExecuteContext(ctx, m, family, flags)
+
+	stats := make([]StatsMessage, len(msgs))
+	for i, msg := range msgs {
+		if sm, ok := msg.(*StatsMessage); ok {
+			stats[i] = *sm
+		}
+	}
+
+	return stats, err
+}
+
+// Get retrieves the statistics selected by the FilterMask of req for the
+// interface with the Index of req.
+func (s *StatsService) Get(req *StatsMessage) (StatsMessage, error) {
+	return s.GetContext(context.Background(), req)
+}
+
+// GetContext is like Get, but takes a context. See Conn.ExecuteContext.
+func (s *StatsService) GetContext(ctx context.Context, req *StatsMessage) (StatsMessage, error) {
+	flags := netlink.Request
+	stats, err := s.execute(ctx, req, unix.RTM_GETSTATS, flags)
+	if err != nil {
+		return StatsMessage{}, err
+	}
+
+	if len(stats) != 1 {
+		return StatsMessage{}, fmt.Errorf("too many/little matches, expected 1, actual %d", len(stats))
+	}
+
+	return stats[0], nil
+}
+
+// List retrieves the statistics selected by the FilterMask of req for all
+// interfaces. The Index of req must be zero.
+func (s *StatsService) List(req *StatsMessage) ([]StatsMessage, error) {
+	return s.ListContext(context.Background(), req)
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (s *StatsService) ListContext(ctx context.Context, req *StatsMessage) ([]StatsMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return s.execute(ctx, req, unix.RTM_GETSTATS, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the statistics
+// one at a time. See Conn.ExecuteSeq.
+func (s *StatsService) ListSeq(req *StatsMessage) iter.Seq2[StatsMessage, error] {
+	return s.ListSeqContext(context.Background(), req)
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (s *StatsService) ListSeqContext(ctx context.Context, req *StatsMessage) iter.Seq2[StatsMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[StatsMessage](ctx, s.c, req, unix.RTM_GETSTATS, flags)
+}
+
+// Set configures the collection of statistics for the interface with the
+// Index of req, e.g. enables L3 statistics with SetOffloadL3Stats.
+func (s *StatsService) Set(req *StatsMessage) error {
+	return s.SetContext(context.Background(), req)
+}
+
+// SetContext is like Set, but takes a context. See Conn.ExecuteContext.
+func (s *StatsService) SetContext(ctx context.Context, req *StatsMessage) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := s.c.ExecuteContext(ctx, req, unix.RTM_SETSTATS, flags)
+
+	return err
+}
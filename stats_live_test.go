@@ -0,0 +1,102 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/mdlayher/netlink"
+)
+
+func TestStats(t *testing.T) {
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	const (
+		lo          = 1
+		bridgeIndex = 3101
+		portIndex   = 3102
+	)
+
+	stats, err := conn.Stats.Get(&StatsMessage{Index: lo, FilterMask: StatsFilterLink64})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.Index != lo || stats.Attributes == nil || stats.Attributes.Link64 == nil {
+		t.Fatalf("expected 64 bit statistics of lo: %+v", stats)
+	}
+
+	err = conn.Link.New(&LinkMessage{
+		Index:      bridgeIndex,
+		Attributes: &LinkAttributes{Name: "statsbr0", Info: &LinkInfo{Kind: "bridge"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create bridge: %v", err)
+	}
+
+	master := uint32(bridgeIndex)
+	err = conn.Link.New(&LinkMessage{
+		Index: portIndex,
+		Attributes: &LinkAttributes{
+			Name:   "statsveth0",
+			Info:   &LinkInfo{Kind: "veth"},
+			Master: &master,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create bridge port: %v", err)
+	}
+
+	stats, err = conn.Stats.Get(&StatsMessage{Index: portIndex, FilterMask: StatsFilterLinkXStatsSlave})
+	if err != nil {
+		t.Fatalf("failed to get port statistics: %v", err)
+	}
+	if a := stats.Attributes; a == nil || a.LinkXStatsSlave == nil || a.LinkXStatsSlave.Bridge == nil || a.LinkXStatsSlave.Bridge.STP == nil {
+		t.Fatalf("expected spanning tree statistics of the bridge port: %+v", a)
+	}
+
+	enable := true
+	err = conn.Stats.Set(&StatsMessage{
+		Index:      portIndex,
+		Attributes: &StatsAttributes{SetOffloadL3Stats: &enable},
+	})
+	if err != nil {
+		t.Fatalf("failed to enable L3 statistics: %v", err)
+	}
+
+	filter := OffloadXStatsFilterHWStatsInfo
+	stats, err = conn.Stats.Get(&StatsMessage{
+		Index:      portIndex,
+		FilterMask: StatsFilterLinkOffloadXStats,
+		Attributes: &StatsAttributes{OffloadXStatsFilter: &filter},
+	})
+	if err != nil {
+		t.Fatalf("failed to get offload statistics: %v", err)
+	}
+	if x := stats.Attributes.OffloadXStats; x == nil || x.L3StatsInfo == nil || !x.L3StatsInfo.Request {
+		t.Fatalf("expected L3 statistics to be requested: %+v", x)
+	}
+
+	all, err := conn.Stats.List(&StatsMessage{FilterMask: StatsFilterLink64 | StatsFilterLinkXStats})
+	if err != nil {
+		t.Fatalf("failed to list statistics: %v", err)
+	}
+
+	var found bool
+	for _, s := range all {
+		if s.Attributes == nil || s.Attributes.Link64 == nil {
+			t.Fatalf("expected 64 bit statistics of interface %d: %+v", s.Index, s.Attributes)
+		}
+		if s.Index == bridgeIndex {
+			found = s.Attributes.LinkXStats != nil && s.Attributes.LinkXStats.Bridge != nil
+		}
+	}
+	if !found {
+		t.Fatalf("expected extended statistics of the bridge in: %+v", all)
+	}
+}
@@ -0,0 +1,184 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+func TestStatsMessageMarshalBinary(t *testing.T) {
+	skipBigEndian(t)
+
+	enable := true
+
+	tests := map[string]struct {
+		m *StatsMessage
+		b []byte
+	}{
+		"empty": {
+			m: &StatsMessage{},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		"get with filter": {
+			m: &StatsMessage{
+				Index:      2,
+				FilterMask: StatsFilterLinkOffloadXStats,
+				Attributes: &StatsAttributes{
+					OffloadXStatsFilter: uint32Ptr(OffloadXStatsFilterL3Stats),
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x00, 0x00,
+				0x0c, 0x00, 0x01, 0x80, 0x08, 0x00, 0x04, 0x00, 0x04, 0x00, 0x00, 0x00,
+			},
+		},
+		"set L3 stats": {
+			m: &StatsMessage{
+				Index: 2,
+				Attributes: &StatsAttributes{
+					SetOffloadL3Stats: &enable,
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x05, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+			}
+		})
+	}
+}
+
+func TestStatsMessageUnmarshalBinary(t *testing.T) {
+	skipBigEndian(t)
+
+	b := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x1e, 0x00, 0x00, 0x00,
+		0x68, 0x00, 0x03, 0x80, 0x64, 0x00, 0x01, 0x80, 0x2c, 0x00, 0x01, 0x00,
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x02, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x34, 0x00, 0x04, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x00, 0x02, 0x80,
+		0x20, 0x00, 0x02, 0x80, 0x1c, 0x00, 0x01, 0x80, 0x0c, 0x00, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x01, 0x00,
+		0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x68, 0x00, 0x04, 0x80,
+		0x18, 0x00, 0x02, 0x80, 0x14, 0x00, 0x03, 0x80, 0x05, 0x00, 0x01, 0x00,
+		0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x4c, 0x00, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x54, 0x00, 0x05, 0x80, 0x50, 0x00, 0x1c, 0x80,
+		0x4c, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	want := &StatsMessage{
+		Index:      3,
+		FilterMask: StatsFilterLinkXStats | StatsFilterLinkXStatsSlave | StatsFilterLinkOffloadXStats | StatsFilterAFSpec,
+		Attributes: &StatsAttributes{
+			LinkXStats: &LinkXStats{
+				Bond: &BondXStats{
+					LACP: &BondLACPStats{
+						LACPDURX: 3,
+						LACPDUTX: 4,
+					},
+				},
+			},
+			LinkXStatsSlave: &LinkXStats{
+				Bridge: &BridgeXStats{
+					Vlans: []BridgeVlanXStats{{
+						RXBytes:   1,
+						RXPackets: 2,
+						TXBytes:   3,
+						TXPackets: 4,
+						Vid:       10,
+						Flags:     unix.BRIDGE_VLAN_INFO_PVID,
+					}},
+					STP: &BridgeSTPXStats{
+						TransitionBlk: 1,
+						TransitionFwd: 2,
+						RXBPDU:        3,
+						TXBPDU:        4,
+						RXTCN:         5,
+						TXTCN:         6,
+					},
+				},
+			},
+			OffloadXStats: &OffloadXStats{
+				L3StatsInfo: &OffloadHWStatsInfo{
+					Request: true,
+					Used:    true,
+				},
+				L3Stats: &LinkHWStats64{
+					RXPackets: 1,
+					TXPackets: 2,
+					RXBytes:   3,
+					TXBytes:   4,
+					RXErrors:  5,
+					TXErrors:  6,
+					RXDropped: 7,
+					TXDropped: 8,
+					Multicast: 9,
+				},
+			},
+			AFSpec: &StatsAFSpec{
+				MPLS: &MPLSLinkStats{
+					RXPackets: 1,
+					TXPackets: 2,
+					RXBytes:   3,
+					TXBytes:   4,
+					RXErrors:  5,
+					TXErrors:  6,
+					RXDropped: 7,
+					TXDropped: 8,
+					RXNoRoute: 9,
+				},
+			},
+		},
+	}
+
+	m := &StatsMessage{}
+	if err := m.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, m) {
+		t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", want, m)
+	}
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &StatsMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x00, 0x01, 0x02, 0x03})
+		if !errors.Is(unmarshalErr, errInvalidStatsMessage) {
+			t.Fatalf("Expected 'errInvalidStatsMessage' but got '%v'", unmarshalErr)
+		}
+	})
+}
@@ -0,0 +1,301 @@
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+// defaultGroups are the multicast groups joined by Subscribe when no groups
+// are specified. Groups added in later kernels, such as the nexthop group,
+// are left out, as joining them fails on kernels which lack them.
+var defaultGroups = []uint32{
+	unix.RTNLGRP_LINK,
+	unix.RTNLGRP_NEIGH,
+	unix.RTNLGRP_IPV4_IFADDR,
+	unix.RTNLGRP_IPV4_ROUTE,
+	unix.RTNLGRP_IPV4_RULE,
+	unix.RTNLGRP_IPV6_IFADDR,
+	unix.RTNLGRP_IPV6_ROUTE,
+	unix.RTNLGRP_IPV6_RULE,
+}
+
+// An EventOp describes what happened to the object carried by an Event.
+type EventOp int
+
+// Possible EventOp values.
+//
+// The kernel uses the same RTM_NEW* message for creating and updating most
+// objects. EventChanged is only reported when the notification says so
+// (a link notification with a partial change mask, or any notification with
+// NLM_F_REPLACE set); all other RTM_NEW* notifications are reported as
+// EventAdded.
+const (
+	EventAdded EventOp = iota + 1
+	EventChanged
+	EventRemoved
+)
+
+func (o EventOp) String() string {
+	switch o {
+	case EventAdded:
+		return "added"
+	case EventChanged:
+		return "changed"
+	case EventRemoved:
+		return "removed"
+	default:
+		return fmt.Sprintf("unknown EventOp value (%d)", o)
+	}
+}
+
+// An Event is a typed rtnetlink notification delivered by a Subscription.
+//
+// The concrete type is one of LinkEvent, AddressEvent, RouteEvent,
+// NeighEvent, RuleEvent, NexthopEvent, NSIDEvent, NetconfEvent, TcEvent or ResyncEvent.
+type Event interface {
+	rtEvent()
+}
+
+// A LinkEvent is delivered for RTM_NEWLINK and RTM_DELLINK notifications.
+type LinkEvent struct {
+	Op   EventOp
+	Link LinkMessage
+}
+
+// An AddressEvent is delivered for RTM_NEWADDR and RTM_DELADDR notifications.
+type AddressEvent struct {
+	Op      EventOp
+	Address AddressMessage
+}
+
+// A RouteEvent is delivered for RTM_NEWROUTE and RTM_DELROUTE notifications.
+type RouteEvent struct {
+	Op    EventOp
+	Route RouteMessage
+}
+
+// A NeighEvent is delivered for RTM_NEWNEIGH and RTM_DELNEIGH notifications.
+type NeighEvent struct {
+	Op    EventOp
+	Neigh NeighMessage
+}
+
+// A RuleEvent is delivered for RTM_NEWRULE and RTM_DELRULE notifications.
+type RuleEvent struct {
+	Op   EventOp
+	Rule RuleMessage
+}
+
+// A NexthopEvent is delivered for RTM_NEWNEXTHOP and RTM_DELNEXTHOP
+// notifications, which are received on the unix.RTNLGRP_NEXTHOP group.
+type NexthopEvent struct {
+	Op      EventOp
+	Nexthop NexthopMessage
+}
+
+// A NSIDEvent is delivered for RTM_NEWNSID and RTM_DELNSID notifications,
+// which are received on the unix.RTNLGRP_NSID group.
+type NSIDEvent struct {
+	Op   EventOp
+	NSID NSIDMessage
+}
+
+// A NetconfEvent is delivered for RTM_NEWNETCONF and RTM_DELNETCONF
+// notifications, which are received on the unix.RTNLGRP_IPV4_NETCONF and
+// unix.RTNLGRP_IPV6_NETCONF groups.
+type NetconfEvent struct {
+	Op      EventOp
+	Netconf NetconfMessage
+}
+
+// A TcEvent is delivered for qdisc, class and filter notifications, which are
+// received on the unix.RTNLGRP_TC group.
+type TcEvent struct {
+	Op EventOp
+	Tc TcMessage
+}
+
+// A ResyncEvent is delivered when the socket receive buffer overran (ENOBUFS)
+// and notifications were lost. Consumers that keep state derived from events
+// must re-dump that state to get back in sync with the kernel.
+type ResyncEvent struct{}
+
+func (LinkEvent) rtEvent()    {}
+func (AddressEvent) rtEvent() {}
+func (RouteEvent) rtEvent()   {}
+func (NeighEvent) rtEvent()   {}
+func (RuleEvent) rtEvent()    {}
+func (NexthopEvent) rtEvent() {}
+func (NSIDEvent) rtEvent()    {}
+func (NetconfEvent) rtEvent() {}
+func (TcEvent) rtEvent()      {}
+func (ResyncEvent) rtEvent()  {}
+
+// A Subscription delivers typed events received from rtnetlink multicast
+// groups. It is created by Conn.Subscribe.
+type Subscription struct {
+	events chan Event
+	err    error
+}
+
+// Events returns the channel on which events are delivered. The channel is
+// closed when the Subscription ends, after which Err reports the reason.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns the error which ended the Subscription. It must only be called
+// after the channel returned by Events has been closed. If the Subscription
+// ended because its context was done, Err returns the context's error.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Subscribe joins the given RTNLGRP_* multicast groups and delivers the
+// notifications received on them as typed events until ctx is done or
+// receiving fails. If no groups are given, the link, neighbor, address, route
+// and rule groups for IPv4 and IPv6 are joined. Other groups, such as
+// unix.RTNLGRP_NEXTHOP, must be given explicitly.
+//
+// Notifications share the socket with request replies, so a Conn used for a
+// Subscription should not be used for anything else until the Subscription
+// has ended. The groups are left and the read deadline of the Conn is reset
+// when the Subscription ends.
+func (c *Conn) Subscribe(ctx context.Context, groups ...uint32) (*Subscription, error) {
+	if len(groups) == 0 {
+		groups = defaultGroups
+	}
+
+	for i, g := range groups {
+		if err := c.c.JoinGroup(g); err != nil {
+			for _, g := range groups[:i] {
+				_ = c.c.LeaveGroup(g)
+			}
+			return nil, err
+		}
+	}
+
+	s := &Subscription{
+		events: make(chan Event),
+	}
+
+	go func() {
+		s.err = c.receiveEvents(ctx, s.events)
+		for _, g := range groups {
+			_ = c.c.LeaveGroup(g)
+		}
+		close(s.events)
+	}()
+
+	return s, nil
+}
+
+// receiveEvents receives notifications and sends them on events until ctx is
+// done or a non-recoverable error occurs.
+func (c *Conn) receiveEvents(ctx context.Context, events chan<- Event) error {
+	defer c.interruptOnDone(ctx)()
+
+	send := func(ev Event) error {
+		select {
+		case events <- ev:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for {
+		rtmsgs, msgs, err := c.Receive()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, unix.ENOBUFS) {
+				if err := send(ResyncEvent{}); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		for i, m := range rtmsgs {
+			ev, ok := newEvent(msgs[i].Header, m)
+			if !ok {
+				continue
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newEvent builds an Event from a notification and its decoded Message. It
+// returns false if the notification is not of a known type.
+func newEvent(h netlink.Header, m Message) (Event, bool) {
+	op := EventAdded
+	if h.Flags&netlink.Replace != 0 {
+		op = EventChanged
+	}
+
+	switch m := m.(type) {
+	case *LinkMessage:
+		// Newly registered links are announced with a full change mask.
+		if h.Type == unix.RTM_NEWLINK && m.Change != ^uint32(0) {
+			op = EventChanged
+		}
+		if h.Type == unix.RTM_DELLINK {
+			op = EventRemoved
+		}
+		return LinkEvent{Op: op, Link: *m}, true
+	case *AddressMessage:
+		if h.Type == unix.RTM_DELADDR {
+			op = EventRemoved
+		}
+		return AddressEvent{Op: op, Address: *m}, true
+	case *RouteMessage:
+		if h.Type == unix.RTM_DELROUTE {
+			op = EventRemoved
+		}
+		return RouteEvent{Op: op, Route: *m}, true
+	case *NeighMessage:
+		if h.Type == unix.RTM_DELNEIGH {
+			op = EventRemoved
+		}
+		return NeighEvent{Op: op, Neigh: *m}, true
+	case *RuleMessage:
+		if h.Type == unix.RTM_DELRULE {
+			op = EventRemoved
+		}
+		return RuleEvent{Op: op, Rule: *m}, true
+	case *NexthopMessage:
+		if h.Type == unix.RTM_DELNEXTHOP {
+			op = EventRemoved
+		}
+		return NexthopEvent{Op: op, Nexthop: *m}, true
+	case *NSIDMessage:
+		if h.Type == unix.RTM_DELNSID {
+			op = EventRemoved
+		}
+		return NSIDEvent{Op: op, NSID: *m}, true
+	case *NetconfMessage:
+		if h.Type == unix.RTM_DELNETCONF {
+			op = EventRemoved
+		}
+		return NetconfEvent{Op: op, Netconf: *m}, true
+	case *TcMessage:
+		switch h.Type {
+		case unix.RTM_DELQDISC, unix.RTM_DELTCLASS, unix.RTM_DELTFILTER:
+			op = EventRemoved
+		}
+		return TcEvent{Op: op, Tc: *m}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,186 @@
+//go:build linux
+// +build linux
+
+package rtnetlink
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestConnSubscribe(t *testing.T) {
+	skipBigEndian(t)
+
+	c, tc := testSubscribeConn(t)
+	tc.recv <- subscribeResult{msgs: []netlink.Message{
+		{
+			Header: netlink.Header{Type: unix.RTM_NEWLINK},
+			Data:   mustMarshal(&LinkMessage{Index: 2, Change: ^uint32(0)}),
+		},
+		{
+			Header: netlink.Header{Type: unix.RTM_NEWLINK},
+			Data:   mustMarshal(&LinkMessage{Index: 2, Change: unix.IFF_UP}),
+		},
+		{
+			Header: netlink.Header{Type: unix.RTM_DELLINK},
+			Data:   mustMarshal(&LinkMessage{Index: 2}),
+		},
+	}}
+	tc.recv <- subscribeResult{err: &netlink.OpError{Op: "receive", Err: os.NewSyscallError("recvmsg", unix.ENOBUFS)}}
+	tc.recv <- subscribeResult{msgs: []netlink.Message{
+		{
+			Header: netlink.Header{Type: unix.RTM_NEWROUTE, Flags: netlink.Replace},
+			Data:   mustMarshal(&RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN}),
+		},
+		{
+			// Not a notification we decode, must be skipped.
+			Header: netlink.Header{Type: netlink.Noop},
+		},
+		{
+			Header: netlink.Header{Type: unix.RTM_DELADDR},
+			Data:   mustMarshal(&AddressMessage{Family: unix.AF_INET, Index: 2}),
+		},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	groups := []uint32{unix.RTNLGRP_LINK, unix.RTNLGRP_IPV4_ROUTE}
+	sub, err := c.Subscribe(ctx, groups...)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	if want, got := groups, tc.joined; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected joined groups:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	want := []Event{
+		LinkEvent{Op: EventAdded, Link: LinkMessage{Index: 2, Change: ^uint32(0)}},
+		LinkEvent{Op: EventChanged, Link: LinkMessage{Index: 2, Change: unix.IFF_UP}},
+		LinkEvent{Op: EventRemoved, Link: LinkMessage{Index: 2}},
+		ResyncEvent{},
+		RouteEvent{Op: EventChanged, Route: RouteMessage{Family: unix.AF_INET, Table: unix.RT_TABLE_MAIN}},
+		AddressEvent{Op: EventRemoved, Address: AddressMessage{Family: unix.AF_INET, Index: 2}},
+	}
+
+	var got []Event
+	for len(got) < len(want) {
+		got = append(got, <-sub.Events())
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected events:\n- want: %#v\n-  got: %#v", want, got)
+	}
+
+	cancel()
+	for range sub.Events() {
+	}
+
+	if err := sub.Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if want, got := groups, tc.left; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected left groups:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestConnSubscribeError(t *testing.T) {
+	c, tc := testSubscribeConn(t)
+
+	wantErr := errors.New("receive failed")
+	tc.recv <- subscribeResult{err: wantErr}
+
+	sub, err := c.Subscribe(context.Background())
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	for range sub.Events() {
+		t.Fatal("unexpected event")
+	}
+
+	if err := sub.Err(); !errors.Is(err, wantErr) {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", wantErr, err)
+	}
+	if want, got := defaultGroups, tc.joined; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected joined groups:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestEventOpString(t *testing.T) {
+	tests := []struct {
+		op   EventOp
+		want string
+	}{
+		{EventAdded, "added"},
+		{EventChanged, "changed"},
+		{EventRemoved, "removed"},
+		{EventOp(99), "unknown EventOp value (99)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.op.String(); got != tt.want {
+				t.Errorf("EventOp.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func testSubscribeConn(t *testing.T) (*Conn, *subscribeConn) {
+	c := &subscribeConn{
+		recv:     make(chan subscribeResult, 8),
+		deadline: make(chan struct{}, 1),
+	}
+	return newConn(c), c
+}
+
+type subscribeResult struct {
+	msgs []netlink.Message
+	err  error
+}
+
+// subscribeConn is a conn which hands out queued Receive results in order,
+// and blocks in Receive once they are exhausted until a read deadline is set.
+type subscribeConn struct {
+	recv     chan subscribeResult
+	deadline chan struct{}
+
+	joined, left []uint32
+
+	noopConn
+}
+
+func (c *subscribeConn) JoinGroup(group uint32) error {
+	c.joined = append(c.joined, group)
+	return nil
+}
+
+func (c *subscribeConn) LeaveGroup(group uint32) error {
+	c.left = append(c.left, group)
+	return nil
+}
+
+func (c *subscribeConn) Receive() ([]netlink.Message, error) {
+	select {
+	case r := <-c.recv:
+		return r.msgs, r.err
+	case <-c.deadline:
+		return nil, &netlink.OpError{Op: "receive", Err: os.ErrDeadlineExceeded}
+	}
+}
+
+func (c *subscribeConn) SetReadDeadline(t time.Time) error {
+	if !t.IsZero() {
+		c.deadline <- struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,652 @@
+package rtnetlink
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+
+	"github.com/mdlayher/netlink"
+)
+
+var (
+	// errInvalidTcMessage is returned when a TcMessage is malformed.
+	errInvalidTcMessage = errors.New("rtnetlink TcMessage is invalid or too short")
+
+	// errInvalidTcStats is returned when the statistics of a TcMessage are malformed.
+	errInvalidTcStats = errors.New("rtnetlink TcMessage contains invalid statistics")
+)
+
+// Special traffic control handles.
+const (
+	TcHandleRoot       uint32 = unix.TC_H_ROOT    // Root of the egress qdisc hierarchy
+	TcHandleIngress    uint32 = unix.TC_H_INGRESS // Handle and parent of ingress qdiscs
+	TcHandleClsact     uint32 = unix.TC_H_CLSACT  // Handle and parent of clsact qdiscs
+	TcHandleMinIngress uint16 = unix.TC_H_MIN_INGRESS
+	TcHandleMinEgress  uint16 = unix.TC_H_MIN_EGRESS
+)
+
+// TcHandle returns the traffic control handle major:minor.
+//
+// Filters attached to the ingress or egress hook of a clsact qdisc use
+// TcHandle(0xffff, TcHandleMinIngress) or TcHandle(0xffff, TcHandleMinEgress)
+// as their parent.
+func TcHandle(major, minor uint16) uint32 {
+	return uint32(major)<<16 | uint32(minor)
+}
+
+// TcFilterInfo returns the Info of a filter TcMessage with the given priority
+// and protocol (unix.ETH_P_*). The protocol is stored in network byte order.
+func TcFilterInfo(priority, protocol uint16) uint32 {
+	return uint32(priority)<<16 | uint32(htons(protocol))
+}
+
+func htons(v uint16) uint16 {
+	b := make([]byte, 2)
+	nativeEndian.PutUint16(b, v)
+	return uint16(b[0])<<8 | uint16(b[1])
+}
+
+// A tcObject is the kind of traffic control object carried by a TcMessage.
+type tcObject int
+
+const (
+	tcQdisc tcObject = iota
+	tcClass
+	tcFilter
+)
+
+var _ Message = &TcMessage{}
+
+// A TcMessage is a route netlink traffic control message. It is used for
+// queueing disciplines, traffic classes and packet classifiers (filters).
+type TcMessage struct {
+	// Always set to AF_UNSPEC (0)
+	Family uint8
+
+	// Interface index
+	Index uint32
+
+	// Handle of the object, see TcHandle
+	Handle uint32
+
+	// Handle of the parent of the object
+	Parent uint32
+
+	// For filters, the priority and protocol of the filter, see TcFilterInfo
+	Info uint32
+
+	// Optional attributes which are appended when not nil.
+	Attributes *TcAttributes
+
+	// object selects the drivers used to decode the options.
+	object tcObject
+}
+
+// MarshalBinary marshals a TcMessage into a byte slice.
+func (m *TcMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, unix.SizeofTcMsg)
+
+	b[0] = m.Family
+	// b[1:4] are padding
+	nativeEndian.PutUint32(b[4:8], m.Index)
+	nativeEndian.PutUint32(b[8:12], m.Handle)
+	nativeEndian.PutUint32(b[12:16], m.Parent)
+	nativeEndian.PutUint32(b[16:20], m.Info)
+
+	if m.Attributes == nil {
+		return b, nil
+	}
+
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = nativeEndian
+	if err := m.Attributes.encode(ae); err != nil {
+		return nil, err
+	}
+
+	a, err := ae.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, a...), nil
+}
+
+// UnmarshalBinary unmarshals the contents of a byte slice into a TcMessage.
+func (m *TcMessage) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < unix.SizeofTcMsg {
+		return errInvalidTcMessage
+	}
+
+	m.Family = b[0]
+	m.Index = nativeEndian.Uint32(b[4:8])
+	m.Handle = nativeEndian.Uint32(b[8:12])
+	m.Parent = nativeEndian.Uint32(b[12:16])
+	m.Info = nativeEndian.Uint32(b[16:20])
+
+	if l > unix.SizeofTcMsg {
+		m.Attributes = &TcAttributes{}
+		ad, err := netlink.NewAttributeDecoder(b[unix.SizeofTcMsg:])
+		if err != nil {
+			return err
+		}
+		ad.ByteOrder = nativeEndian
+		if err := m.Attributes.decode(ad, m.object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rtMessage is an empty method to sattisfy the Message interface.
+func (*TcMessage) rtMessage() {}
+
+// TcAttributes contains all attributes for a traffic control object.
+type TcAttributes struct {
+	// Kind of the queueing discipline, class or filter (e.g. "htb", "bpf")
+	Kind string
+
+	// Kind specific options, decoded by the TcDriver registered for Kind
+	Options TcDriver
+
+	// Filter chain index
+	Chain *uint32
+
+	// Whether the object is offloaded to hardware. Only reported by the kernel.
+	HwOffload *uint8
+
+	// Shared filter blocks of ingress and clsact qdiscs
+	IngressBlock *uint32
+	EgressBlock  *uint32
+
+	// Statistics of qdiscs and classes. Only reported by the kernel.
+	Stats *TcStats
+}
+
+func (a *TcAttributes) decode(ad *netlink.AttributeDecoder, object tcObject) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_KIND:
+			a.Kind = ad.String()
+		case unix.TCA_OPTIONS:
+			driver, found := getTcDriver(a.Kind, object)
+			a.Options = driver
+			if raw, ok := driver.(TcRawDriver); ok {
+				ad.Do(raw.UnmarshalBinary)
+				continue
+			}
+			if found {
+				ad.Nested(driver.Decode)
+			}
+		case unix.TCA_CHAIN:
+			v := ad.Uint32()
+			a.Chain = &v
+		case unix.TCA_HW_OFFLOAD:
+			v := ad.Uint8()
+			a.HwOffload = &v
+		case unix.TCA_INGRESS_BLOCK:
+			v := ad.Uint32()
+			a.IngressBlock = &v
+		case unix.TCA_EGRESS_BLOCK:
+			v := ad.Uint32()
+			a.EgressBlock = &v
+		case unix.TCA_STATS2:
+			a.Stats = &TcStats{}
+			ad.Nested(a.Stats.decode)
+		}
+	}
+
+	return ad.Err()
+}
+
+func (a *TcAttributes) encode(ae *netlink.AttributeEncoder) error {
+	if a.Kind != "" {
+		ae.String(unix.TCA_KIND, a.Kind)
+	}
+
+	if a.Options != nil {
+		if a.Kind != a.Options.Kind() {
+			return fmt.Errorf("driver kind %s is not equal to kind %s", a.Options.Kind(), a.Kind)
+		}
+		if raw, ok := a.Options.(TcRawDriver); ok {
+			ae.Do(unix.TCA_OPTIONS, raw.MarshalBinary)
+		} else {
+			ae.Nested(unix.TCA_OPTIONS, a.Options.Encode)
+		}
+	}
+
+	if a.Chain != nil {
+		ae.Uint32(unix.TCA_CHAIN, *a.Chain)
+	}
+
+	if a.IngressBlock != nil {
+		ae.Uint32(unix.TCA_INGRESS_BLOCK, *a.IngressBlock)
+	}
+
+	if a.EgressBlock != nil {
+		ae.Uint32(unix.TCA_EGRESS_BLOCK, *a.EgressBlock)
+	}
+
+	return nil
+}
+
+// TcStats contains the statistics of a queueing discipline or class.
+type TcStats struct {
+	Bytes      uint64 // Number of bytes seen
+	Packets    uint32 // Number of packets seen
+	Bps        uint32 // Estimated rate in bytes per second
+	Pps        uint32 // Estimated rate in packets per second
+	Qlen       uint32 // Queue length in packets
+	Backlog    uint32 // Queue backlog in bytes
+	Drops      uint32 // Number of dropped packets
+	Requeues   uint32 // Number of requeued packets
+	Overlimits uint32 // Number of times the rate limit was exceeded
+}
+
+func (s *TcStats) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.TCA_STATS_BASIC:
+			ad.Do(func(b []byte) error {
+				// struct gnet_stats_basic
+				if len(b) < 12 {
+					return errInvalidTcStats
+				}
+				s.Bytes = nativeEndian.Uint64(b[0:8])
+				s.Packets = nativeEndian.Uint32(b[8:12])
+				return nil
+			})
+		case unix.TCA_STATS_RATE_EST:
+			ad.Do(func(b []byte) error {
+				// struct gnet_stats_rate_est
+				if len(b) < 8 {
+					return errInvalidTcStats
+				}
+				s.Bps = nativeEndian.Uint32(b[0:4])
+				s.Pps = nativeEndian.Uint32(b[4:8])
+				return nil
+			})
+		case unix.TCA_STATS_QUEUE:
+			ad.Do(func(b []byte) error {
+				// struct gnet_stats_queue
+				if len(b) < 20 {
+					return errInvalidTcStats
+				}
+				s.Qlen = nativeEndian.Uint32(b[0:4])
+				s.Backlog = nativeEndian.Uint32(b[4:8])
+				s.Drops = nativeEndian.Uint32(b[8:12])
+				s.Requeues = nativeEndian.Uint32(b[12:16])
+				s.Overlimits = nativeEndian.Uint32(b[16:20])
+				return nil
+			})
+		}
+	}
+
+	return ad.Err()
+}
+
+var (
+	// registeredQdiscDrivers is the global map of registered qdisc drivers
+	registeredQdiscDrivers = make(map[string]TcDriver)
+
+	// registeredClassDrivers is the global map of registered class drivers
+	registeredClassDrivers = make(map[string]TcDriver)
+
+	// registeredFilterDrivers is the global map of registered filter drivers
+	registeredFilterDrivers = make(map[string]TcDriver)
+)
+
+// RegisterTcDriver registers a traffic control driver with the qdisc, class
+// and filter services. This allows the driver to be used to encode/decode the
+// TCA_OPTIONS of its kind. Drivers implementing TcClassDriver are used for
+// classes, drivers implementing TcFilterDriver for filters, and all other
+// drivers for qdiscs.
+//
+// This function is not threadsafe. This should not be used after Dial
+func RegisterTcDriver(d TcDriver) error {
+	registered := registeredQdiscDrivers
+	switch d.(type) {
+	case TcClassDriver:
+		registered = registeredClassDrivers
+	case TcFilterDriver:
+		registered = registeredFilterDrivers
+	}
+
+	if _, ok := registered[d.Kind()]; ok {
+		return fmt.Errorf("driver %s already registered", d.Kind())
+	}
+	registered[d.Kind()] = d
+	return nil
+}
+
+// getTcDriver returns the driver instance for the given kind and object, and true if the driver
+// is registered. It returns the default (TcData) driver, and false if the driver is not registered
+func getTcDriver(kind string, object tcObject) (TcDriver, bool) {
+	registered := registeredQdiscDrivers
+	switch object {
+	case tcClass:
+		registered = registeredClassDrivers
+	case tcFilter:
+		registered = registeredFilterDrivers
+	}
+
+	if t, ok := registered[kind]; ok {
+		return t.New(), true
+	}
+
+	return &TcData{Name: kind}, false
+}
+
+// TcDriver is the interface that wraps the kind specific Encode, Decode, and Kind methods
+// of queueing disciplines, classes and filters
+type TcDriver interface {
+	// New returns a new instance of the TcDriver
+	New() TcDriver
+
+	// Encode the driver data into the TCA_OPTIONS attribute
+	Encode(*netlink.AttributeEncoder) error
+
+	// Decode the driver data from the TCA_OPTIONS attribute
+	Decode(*netlink.AttributeDecoder) error
+
+	// Return the kind as string, this will be matched with TcAttributes.Kind to find a driver to decode the data
+	Kind() string
+}
+
+// TcClassDriver defines a TcDriver with Class method
+type TcClassDriver interface {
+	TcDriver
+
+	// Class method specifies driver is used for classes
+	Class()
+}
+
+// TcFilterDriver defines a TcDriver with Filter method
+type TcFilterDriver interface {
+	TcDriver
+
+	// Filter method specifies driver is used for filters
+	Filter()
+}
+
+// TcRawDriver defines a TcDriver whose TCA_OPTIONS are not only made of
+// attributes, such as netem which puts a struct in front of its attributes.
+// MarshalBinary and UnmarshalBinary are used for the whole TCA_OPTIONS
+// payload instead of Encode and Decode.
+type TcRawDriver interface {
+	TcDriver
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// TcData implements the default TcDriver interface for not registered drivers
+type TcData struct {
+	Name string
+	Data []byte
+}
+
+var _ TcRawDriver = &TcData{}
+
+func (d *TcData) New() TcDriver {
+	return &TcData{}
+}
+
+func (d *TcData) Decode(ad *netlink.AttributeDecoder) error {
+	return nil
+}
+
+func (d *TcData) Encode(ae *netlink.AttributeEncoder) error {
+	return nil
+}
+
+func (d *TcData) MarshalBinary() ([]byte, error) {
+	return d.Data, nil
+}
+
+func (d *TcData) UnmarshalBinary(b []byte) error {
+	d.Data = append([]byte(nil), b...)
+	return nil
+}
+
+func (d *TcData) Kind() string {
+	return d.Name
+}
+
+// executeTc executes the request and returns the messages as a TcMessage slice
+func executeTc(ctx context.Context, c *Conn, m Message, family uint16, flags netlink.HeaderFlags) ([]TcMessage, error) {
+	msgs, err := c.ExecuteContext(ctx, m, family, flags)
+
+	tcs := make([]TcMessage, len(msgs))
+	for i, msg := range msgs {
+		if tc, ok := msg.(*TcMessage); ok {
+			tcs[i] = *tc
+		}
+	}
+
+	return tcs, err
+}
+
+// QdiscService is used to retrieve rtnetlink family information.
+type QdiscService struct {
+	c *Conn
+}
+
+// Add creates a new queueing discipline.
+func (q *QdiscService) Add(req *TcMessage) error {
+	return q.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (q *QdiscService) AddContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
+	_, err := q.c.ExecuteContext(ctx, req, unix.RTM_NEWQDISC, flags)
+
+	return err
+}
+
+// Replace replaces or adds a queueing discipline.
+func (q *QdiscService) Replace(req *TcMessage) error {
+	return q.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (q *QdiscService) ReplaceContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
+	_, err := q.c.ExecuteContext(ctx, req, unix.RTM_NEWQDISC, flags)
+
+	return err
+}
+
+// Delete removes a queueing discipline.
+func (q *QdiscService) Delete(req *TcMessage) error {
+	return q.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (q *QdiscService) DeleteContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := q.c.ExecuteContext(ctx, req, unix.RTM_DELQDISC, flags)
+
+	return err
+}
+
+// List retrieves the queueing disciplines of all interfaces.
+func (q *QdiscService) List() ([]TcMessage, error) {
+	return q.ListContext(context.Background())
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (q *QdiscService) ListContext(ctx context.Context) ([]TcMessage, error) {
+	flags := netlink.Request | netlink.Dump
+	return executeTc(ctx, q.c, &TcMessage{}, unix.RTM_GETQDISC, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the queueing disciplines
+// one at a time. See Conn.ExecuteSeq.
+func (q *QdiscService) ListSeq() iter.Seq2[TcMessage, error] {
+	return q.ListSeqContext(context.Background())
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (q *QdiscService) ListSeqContext(ctx context.Context) iter.Seq2[TcMessage, error] {
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[TcMessage](ctx, q.c, &TcMessage{}, unix.RTM_GETQDISC, flags)
+}
+
+// ClassService is used to retrieve rtnetlink family information.
+type ClassService struct {
+	c *Conn
+}
+
+// Add creates a new traffic class.
+func (c *ClassService) Add(req *TcMessage) error {
+	return c.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (c *ClassService) AddContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
+	_, err := c.c.ExecuteContext(ctx, req, unix.RTM_NEWTCLASS, flags)
+
+	return err
+}
+
+// Replace replaces or adds a traffic class.
+func (c *ClassService) Replace(req *TcMessage) error {
+	return c.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (c *ClassService) ReplaceContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
+	_, err := c.c.ExecuteContext(ctx, req, unix.RTM_NEWTCLASS, flags)
+
+	return err
+}
+
+// Delete removes a traffic class.
+func (c *ClassService) Delete(req *TcMessage) error {
+	return c.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (c *ClassService) DeleteContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := c.c.ExecuteContext(ctx, req, unix.RTM_DELTCLASS, flags)
+
+	return err
+}
+
+// List retrieves the traffic classes of an interface by index.
+func (c *ClassService) List(index uint32) ([]TcMessage, error) {
+	return c.ListContext(context.Background(), index)
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (c *ClassService) ListContext(ctx context.Context, index uint32) ([]TcMessage, error) {
+	req := &TcMessage{
+		Index: index,
+	}
+
+	flags := netlink.Request | netlink.Dump
+	return executeTc(ctx, c.c, req, unix.RTM_GETTCLASS, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the traffic classes
+// one at a time. See Conn.ExecuteSeq.
+func (c *ClassService) ListSeq(index uint32) iter.Seq2[TcMessage, error] {
+	return c.ListSeqContext(context.Background(), index)
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (c *ClassService) ListSeqContext(ctx context.Context, index uint32) iter.Seq2[TcMessage, error] {
+	req := &TcMessage{
+		Index: index,
+	}
+
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[TcMessage](ctx, c.c, req, unix.RTM_GETTCLASS, flags)
+}
+
+// FilterService is used to retrieve rtnetlink family information.
+type FilterService struct {
+	c *Conn
+}
+
+// Add creates a new filter.
+func (f *FilterService) Add(req *TcMessage) error {
+	return f.AddContext(context.Background(), req)
+}
+
+// AddContext is like Add, but takes a context. See Conn.ExecuteContext.
+func (f *FilterService) AddContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Acknowledge | netlink.Excl
+	_, err := f.c.ExecuteContext(ctx, req, unix.RTM_NEWTFILTER, flags)
+
+	return err
+}
+
+// Replace replaces or adds a filter.
+func (f *FilterService) Replace(req *TcMessage) error {
+	return f.ReplaceContext(context.Background(), req)
+}
+
+// ReplaceContext is like Replace, but takes a context. See Conn.ExecuteContext.
+func (f *FilterService) ReplaceContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Create | netlink.Replace | netlink.Acknowledge
+	_, err := f.c.ExecuteContext(ctx, req, unix.RTM_NEWTFILTER, flags)
+
+	return err
+}
+
+// Delete removes a filter. Deleting with a zero Info removes all filters of
+// the parent.
+func (f *FilterService) Delete(req *TcMessage) error {
+	return f.DeleteContext(context.Background(), req)
+}
+
+// DeleteContext is like Delete, but takes a context. See Conn.ExecuteContext.
+func (f *FilterService) DeleteContext(ctx context.Context, req *TcMessage) error {
+	flags := netlink.Request | netlink.Acknowledge
+	_, err := f.c.ExecuteContext(ctx, req, unix.RTM_DELTFILTER, flags)
+
+	return err
+}
+
+// List retrieves the filters attached to parent on an interface by index.
+func (f *FilterService) List(index, parent uint32) ([]TcMessage, error) {
+	return f.ListContext(context.Background(), index, parent)
+}
+
+// ListContext is like List, but takes a context. See Conn.ExecuteContext.
+func (f *FilterService) ListContext(ctx context.Context, index, parent uint32) ([]TcMessage, error) {
+	req := &TcMessage{
+		Index:  index,
+		Parent: parent,
+	}
+
+	flags := netlink.Request | netlink.Dump
+	return executeTc(ctx, f.c, req, unix.RTM_GETTFILTER, flags)
+}
+
+// ListSeq is like List, but returns an iterator which yields the filters
+// one at a time. See Conn.ExecuteSeq.
+func (f *FilterService) ListSeq(index, parent uint32) iter.Seq2[TcMessage, error] {
+	return f.ListSeqContext(context.Background(), index, parent)
+}
+
+// ListSeqContext is like ListSeq, but takes a context. See Conn.ExecuteSeqContext.
+func (f *FilterService) ListSeqContext(ctx context.Context, index, parent uint32) iter.Seq2[TcMessage, error] {
+	req := &TcMessage{
+		Index:  index,
+		Parent: parent,
+	}
+
+	flags := netlink.Request | netlink.Dump
+	return executeSeq[TcMessage](ctx, f.c, req, unix.RTM_GETTFILTER, flags)
+}
@@ -0,0 +1,276 @@
+package rtnetlink
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+	"github.com/mdlayher/netlink"
+)
+
+func TestTcMessage(t *testing.T) {
+	skipBigEndian(t)
+
+	tests := map[string]struct {
+		m            Message
+		b            []byte
+		marshalErr   error
+		unmarshalErr error
+	}{
+		"empty": {
+			m: &TcMessage{},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		"qdisc": {
+			m: &TcMessage{
+				Index:  2,
+				Handle: TcHandle(1, 0),
+				Parent: TcHandleRoot,
+				Attributes: &TcAttributes{
+					Kind:    "htb",
+					Options: &TcData{Name: "htb", Data: []byte{0x01, 0x02, 0x03, 0x04}},
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0xff, 0xff,
+				0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x00, 0x68, 0x74, 0x62, 0x00,
+				0x08, 0x00, 0x02, 0x00, 0x01, 0x02, 0x03, 0x04,
+			},
+		},
+		"filter": {
+			m: &TcMessage{
+				Index:  3,
+				Handle: 1,
+				Parent: TcHandle(0xffff, TcHandleMinIngress),
+				Info:   TcFilterInfo(1, unix.ETH_P_ALL),
+				Attributes: &TcAttributes{
+					Kind:  "matchall",
+					Chain: uint32Ptr(0),
+				},
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xf2, 0xff,
+				0xff, 0xff, 0x00, 0x03, 0x01, 0x00, 0x0d, 0x00, 0x01, 0x00, 0x6d, 0x61, 0x74, 0x63,
+				0x68, 0x61, 0x6c, 0x6c, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0b, 0x00, 0x00, 0x00,
+				0x00, 0x00,
+			},
+		},
+		"kind mismatch": {
+			m: &TcMessage{
+				Attributes: &TcAttributes{
+					Kind:    "htb",
+					Options: &TcData{Name: "fq"},
+				},
+			},
+			marshalErr: errors.New("driver kind fq is not equal to kind htb"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b []byte
+			t.Run("marshal", func(t *testing.T) {
+				var marshalErr error
+				b, marshalErr = tt.m.MarshalBinary()
+
+				if tt.marshalErr != nil {
+					if marshalErr == nil || marshalErr.Error() != tt.marshalErr.Error() {
+						t.Fatalf("Expected error '%v' but got '%v'", tt.marshalErr, marshalErr)
+					}
+					return
+				}
+				if marshalErr != nil {
+					t.Fatalf("Unexpected error: %v", marshalErr)
+				}
+			})
+			if tt.marshalErr != nil {
+				return
+			}
+
+			t.Run("compare bytes", func(t *testing.T) {
+				if want, got := tt.b, b; !bytes.Equal(want, got) {
+					t.Fatalf("unexpected Message bytes:\n- want: [%# x]\n-  got: [%# x]", want, got)
+				}
+			})
+
+			m := &TcMessage{}
+			t.Run("unmarshal", func(t *testing.T) {
+				unmarshalErr := (m).UnmarshalBinary(b)
+				if !errors.Is(unmarshalErr, tt.unmarshalErr) {
+					t.Fatalf("Expected error '%v' but got '%v'", tt.unmarshalErr, unmarshalErr)
+				}
+			})
+
+			t.Run("compare messages", func(t *testing.T) {
+				if !reflect.DeepEqual(tt.m, m) {
+					t.Fatalf("unexpected Message:\n- want: %#v\n-  got: %#v", tt.m, m)
+				}
+			})
+		})
+	}
+
+	t.Run("invalid length", func(t *testing.T) {
+		m := &TcMessage{}
+		unmarshalErr := (m).UnmarshalBinary([]byte{0x00, 0x01, 0x2, 0x03})
+		if !errors.Is(unmarshalErr, errInvalidTcMessage) {
+			t.Fatalf("Expected 'errInvalidTcMessage' but got '%v'", unmarshalErr)
+		}
+	})
+}
+
+func TestTcMessageStats(t *testing.T) {
+	skipBigEndian(t)
+
+	ae := netlink.NewAttributeEncoder()
+	ae.String(unix.TCA_KIND, "fq")
+	ae.Nested(unix.TCA_STATS2, func(nae *netlink.AttributeEncoder) error {
+		nae.Bytes(unix.TCA_STATS_BASIC, []byte{
+			0xe8, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00,
+		})
+		nae.Bytes(unix.TCA_STATS_QUEUE, []byte{
+			0x01, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x03, 0x00,
+			0x00, 0x00, 0x04, 0x00, 0x00, 0x00,
+		})
+		return nil
+	})
+	a, err := ae.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode attributes: %v", err)
+	}
+
+	m := &TcMessage{}
+	if err := m.UnmarshalBinary(append(make([]byte, unix.SizeofTcMsg), a...)); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	want := &TcStats{
+		Bytes:      1000,
+		Packets:    10,
+		Qlen:       1,
+		Backlog:    64,
+		Drops:      2,
+		Requeues:   3,
+		Overlimits: 4,
+	}
+	if !reflect.DeepEqual(want, m.Attributes.Stats) {
+		t.Fatalf("unexpected stats:\n- want: %#v\n-  got: %#v", want, m.Attributes.Stats)
+	}
+
+	t.Run("invalid stats", func(t *testing.T) {
+		ae := netlink.NewAttributeEncoder()
+		ae.Nested(unix.TCA_STATS2, func(nae *netlink.AttributeEncoder) error {
+			nae.Bytes(unix.TCA_STATS_QUEUE, []byte{0x01, 0x00, 0x00, 0x00})
+			return nil
+		})
+		a, err := ae.Encode()
+		if err != nil {
+			t.Fatalf("failed to encode attributes: %v", err)
+		}
+
+		m := &TcMessage{}
+		err = m.UnmarshalBinary(append(make([]byte, unix.SizeofTcMsg), a...))
+		if !errors.Is(err, errInvalidTcStats) {
+			t.Fatalf("Expected 'errInvalidTcStats' but got '%v'", err)
+		}
+	})
+}
+
+// testTcDriver is a registered TcDriver used to test the registry.
+type testTcDriver struct {
+	kind  string
+	Value *uint32
+}
+
+func (d *testTcDriver) New() TcDriver { return &testTcDriver{kind: d.kind} }
+func (d *testTcDriver) Kind() string  { return d.kind }
+
+func (d *testTcDriver) Encode(ae *netlink.AttributeEncoder) error {
+	if d.Value != nil {
+		ae.Uint32(1, *d.Value)
+	}
+	return nil
+}
+
+func (d *testTcDriver) Decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		if ad.Type() == 1 {
+			v := ad.Uint32()
+			d.Value = &v
+		}
+	}
+	return ad.Err()
+}
+
+// testTcClassDriver is a registered TcClassDriver used to test the registry.
+type testTcClassDriver struct {
+	testTcDriver
+}
+
+func (d *testTcClassDriver) New() TcDriver { return &testTcClassDriver{testTcDriver{kind: d.kind}} }
+func (d *testTcClassDriver) Class()        {}
+
+func TestRegisterTcDriver(t *testing.T) {
+	skipBigEndian(t)
+
+	const kind = "rtnetlink-test"
+	t.Cleanup(func() {
+		delete(registeredQdiscDrivers, kind)
+		delete(registeredClassDrivers, kind)
+	})
+
+	if err := RegisterTcDriver(&testTcDriver{kind: kind}); err != nil {
+		t.Fatalf("failed to register qdisc driver: %v", err)
+	}
+	if err := RegisterTcDriver(&testTcClassDriver{testTcDriver{kind: kind}}); err != nil {
+		t.Fatalf("failed to register class driver with the kind of a qdisc driver: %v", err)
+	}
+	if err := RegisterTcDriver(&testTcDriver{kind: kind}); err == nil {
+		t.Fatal("expected an error registering a driver twice")
+	}
+
+	b, err := (&TcMessage{
+		Attributes: &TcAttributes{
+			Kind:    kind,
+			Options: &testTcDriver{kind: kind, Value: uint32Ptr(42)},
+		},
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	msgs, err := unpackMessages([]netlink.Message{
+		{Header: netlink.Header{Type: unix.RTM_NEWQDISC}, Data: b},
+		{Header: netlink.Header{Type: unix.RTM_NEWTCLASS}, Data: b},
+		{Header: netlink.Header{Type: unix.RTM_NEWTFILTER}, Data: b},
+	})
+	if err != nil {
+		t.Fatalf("failed to unpack messages: %v", err)
+	}
+
+	want := []TcDriver{
+		&testTcDriver{kind: kind, Value: uint32Ptr(42)},
+		&testTcClassDriver{testTcDriver{kind: kind, Value: uint32Ptr(42)}},
+		// No filter driver is registered, the options are kept as raw bytes.
+		&TcData{Name: kind, Data: []byte{0x08, 0x00, 0x01, 0x00, 0x2a, 0x00, 0x00, 0x00}},
+	}
+	for i, m := range msgs {
+		if got := m.(*TcMessage).Attributes.Options; !reflect.DeepEqual(want[i], got) {
+			t.Fatalf("unexpected options:\n- want: %#v\n-  got: %#v", want[i], got)
+		}
+	}
+}
+
+func TestTcHandle(t *testing.T) {
+	if want, got := uint32(0xffff0000), TcHandle(0xffff, 0); want != got {
+		t.Fatalf("unexpected handle: want %#x, got %#x", want, got)
+	}
+	if want, got := uint32(0xfffffff2), TcHandle(0xffff, TcHandleMinIngress); want != got {
+		t.Fatalf("unexpected handle: want %#x, got %#x", want, got)
+	}
+}
@@ -0,0 +1,99 @@
+package rtnetlink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/jsimonetti/rtnetlink/v2/internal/unix"
+)
+
+// XDPMode selects the mode an XDP program is attached in.
+type XDPMode uint32
+
+// XDP attach modes.
+const (
+	XDPModeAuto    XDPMode = 0                       // driver mode if supported, generic mode otherwise
+	XDPModeGeneric XDPMode = unix.XDP_FLAGS_SKB_MODE // generic (skb) mode, supported by every link
+	XDPModeDriver  XDPMode = unix.XDP_FLAGS_DRV_MODE // native driver mode
+	XDPModeOffload XDPMode = unix.XDP_FLAGS_HW_MODE  // offloaded to the network card
+)
+
+// String returns a string representation of the XDPMode.
+func (m XDPMode) String() string {
+	switch m {
+	case XDPModeAuto:
+		return "auto"
+	case XDPModeGeneric:
+		return "generic"
+	case XDPModeDriver:
+		return "driver"
+	case XDPModeOffload:
+		return "offload"
+	default:
+		return fmt.Sprintf("unknown XDPMode value (%d)", m)
+	}
+}
+
+// AttachXDP attaches an XDP program to the link with the given index in the
+// given mode, replacing the program attached in that mode if any.
+func (l *LinkService) AttachXDP(index uint32, prog *ebpf.Program, mode XDPMode) error {
+	return l.AttachXDPContext(context.Background(), index, prog, mode)
+}
+
+// AttachXDPContext is like AttachXDP, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) AttachXDPContext(ctx context.Context, index uint32, prog *ebpf.Program, mode XDPMode) error {
+	return l.setXDP(ctx, index, &LinkXDP{
+		FD:    int32(prog.FD()),
+		Flags: uint32(mode),
+	})
+}
+
+// ReplaceXDP atomically replaces the XDP program attached to the link with the
+// given index in the given mode with prog, as long as expected is the program
+// currently attached. A nil expected only attaches prog when no program is
+// attached in that mode. The kernel returns EEXIST when the attached program
+// does not match.
+//
+// ReplaceXDP requires Linux 5.7 or later.
+func (l *LinkService) ReplaceXDP(index uint32, prog, expected *ebpf.Program, mode XDPMode) error {
+	return l.ReplaceXDPContext(context.Background(), index, prog, expected, mode)
+}
+
+// ReplaceXDPContext is like ReplaceXDP, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) ReplaceXDPContext(ctx context.Context, index uint32, prog, expected *ebpf.Program, mode XDPMode) error {
+	expectedFD := int32(-1)
+	if expected != nil {
+		expectedFD = int32(expected.FD())
+	}
+
+	return l.setXDP(ctx, index, &LinkXDP{
+		FD:         int32(prog.FD()),
+		ExpectedFD: expectedFD,
+		Flags:      uint32(mode) | unix.XDP_FLAGS_REPLACE,
+	})
+}
+
+// DetachXDP detaches the XDP program attached to the link with the given index
+// in the given mode. It is not an error if no program is attached.
+func (l *LinkService) DetachXDP(index uint32, mode XDPMode) error {
+	return l.DetachXDPContext(context.Background(), index, mode)
+}
+
+// DetachXDPContext is like DetachXDP, but takes a context. See Conn.ExecuteContext.
+func (l *LinkService) DetachXDPContext(ctx context.Context, index uint32, mode XDPMode) error {
+	return l.setXDP(ctx, index, &LinkXDP{
+		FD:    -1,
+		Flags: uint32(mode),
+	})
+}
+
+func (l *LinkService) setXDP(ctx context.Context, index uint32, xdp *LinkXDP) error {
+	return l.SetContext(ctx, &LinkMessage{
+		Family: unix.AF_UNSPEC,
+		Index:  index,
+		Attributes: &LinkAttributes{
+			XDP: xdp,
+		},
+	})
+}
@@ -0,0 +1,98 @@
+//go:build integration
+// +build integration
+
+package rtnetlink
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/jsimonetti/rtnetlink/v2/internal/testutils"
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func xdpProgram(tb testing.TB) *ebpf.Program {
+	tb.Helper()
+
+	// Load XDP_PASS into the return value register.
+	prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+		Type: ebpf.XDP,
+		Instructions: asm.Instructions{
+			asm.LoadImm(asm.R0, int64(2), asm.DWord),
+			asm.Return(),
+		},
+		License: "MIT",
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { prog.Close() })
+
+	return prog
+}
+
+func xdpProgramID(tb testing.TB, prog *ebpf.Program) uint32 {
+	tb.Helper()
+
+	info, err := prog.Info()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	id, ok := info.ID()
+	if !ok {
+		tb.Fatal("program ID is not available")
+	}
+
+	return uint32(id)
+}
+
+func TestLinkAttachXDP(t *testing.T) {
+	testutils.SkipOnOldKernel(t, "5.7", "XDP_FLAGS_REPLACE")
+
+	conn, err := Dial(&netlink.Config{NetNS: testutils.NetNS(t)})
+	if err != nil {
+		t.Fatalf("failed to establish netlink socket: %v", err)
+	}
+	defer conn.Close()
+
+	prog1, prog2 := xdpProgram(t), xdpProgram(t)
+
+	xdp := func() *LinkXDP {
+		t.Helper()
+
+		l, err := conn.Link.Get(lo)
+		if err != nil {
+			t.Fatalf("failed to get link: %v", err)
+		}
+		return l.Attributes.XDP
+	}
+
+	if err := conn.Link.AttachXDP(lo, prog1, XDPModeGeneric); err != nil {
+		t.Fatalf("failed to attach XDP program: %v", err)
+	}
+	if got := xdp(); got.Attached != unix.XDP_FLAGS_SKB_MODE || got.SkbProgID != xdpProgramID(t, prog1) {
+		t.Fatalf("unexpected XDP state after attach: %+v", got)
+	}
+
+	// A replace only succeeds when the expected program is attached.
+	err = conn.Link.ReplaceXDP(lo, prog2, nil, XDPModeGeneric)
+	if !errors.Is(err, unix.EEXIST) {
+		t.Fatalf("expected EEXIST replacing an unexpected program, got: %v", err)
+	}
+	if err := conn.Link.ReplaceXDP(lo, prog2, prog1, XDPModeGeneric); err != nil {
+		t.Fatalf("failed to replace XDP program: %v", err)
+	}
+	if got := xdp(); got.SkbProgID != xdpProgramID(t, prog2) {
+		t.Fatalf("unexpected XDP state after replace: %+v", got)
+	}
+
+	if err := conn.Link.DetachXDP(lo, XDPModeGeneric); err != nil {
+		t.Fatalf("failed to detach XDP program: %v", err)
+	}
+	if got := xdp(); got.Attached != 0 || got.ProgID != 0 {
+		t.Fatalf("unexpected XDP state after detach: %+v", got)
+	}
+}